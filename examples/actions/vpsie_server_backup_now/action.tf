@@ -0,0 +1,7 @@
+# Back up on demand with: terraform apply -invoke=action.vpsie_server_backup_now.db
+action "vpsie_server_backup_now" "db" {
+  config {
+    vm_identifier = vpsie_server.db.identifier
+    name          = "pre-migration"
+  }
+}
@@ -0,0 +1,11 @@
+# Stop a server with: terraform apply -invoke=action.vpsie_server_power.stop_web
+action "vpsie_server_power" "stop_web" {
+  config {
+    identifier = vpsie_server.web.identifier
+    state      = "stopped"
+
+    timeouts = {
+      invoke = "15m"
+    }
+  }
+}
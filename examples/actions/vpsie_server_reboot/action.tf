@@ -0,0 +1,18 @@
+# Reboot on demand with: terraform apply -invoke=action.vpsie_server_reboot.web
+action "vpsie_server_reboot" "web" {
+  config {
+    identifier = vpsie_server.web.identifier
+  }
+}
+
+# Or reboot whenever the startup script changes.
+resource "terraform_data" "web_script" {
+  input = vpsie_script.bootstrap.script
+
+  lifecycle {
+    action_trigger {
+      events  = [after_update]
+      actions = [action.vpsie_server_reboot.web]
+    }
+  }
+}
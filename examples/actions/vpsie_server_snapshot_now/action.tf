@@ -0,0 +1,18 @@
+# Snapshot the server before every change to it.
+action "vpsie_server_snapshot_now" "web" {
+  config {
+    vm_identifier = vpsie_server.web.identifier
+    note          = "taken by terraform before an update"
+  }
+}
+
+resource "terraform_data" "web_release" {
+  input = var.release
+
+  lifecycle {
+    action_trigger {
+      events  = [before_update]
+      actions = [action.vpsie_server_snapshot_now.web]
+    }
+  }
+}
@@ -1 +1,6 @@
 data "vpsie_servers" "example" {}
+
+# Only servers tagged with both "web" and "prod".
+data "vpsie_servers" "web" {
+  tags = ["web", "prod"]
+}
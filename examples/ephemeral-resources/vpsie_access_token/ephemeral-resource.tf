@@ -0,0 +1,10 @@
+# Mint a token for the duration of the run, e.g. to hand to another
+# provider, and revoke it afterwards.
+ephemeral "vpsie_access_token" "ci" {
+  name = "ci-pipeline"
+}
+
+provider "vpsie" {
+  alias        = "scoped"
+  access_token = ephemeral.vpsie_access_token.ci.access_token
+}
@@ -0,0 +1,14 @@
+variable "enable_backups" {
+  type    = bool
+  default = true
+}
+
+resource "vpsie_server" "example" {
+  hostname            = "my-server"
+  dc_identifier       = "dc-identifier"
+  os_identifier       = "os-identifier"
+  resource_identifier = "resource-identifier"
+  project_id          = 1
+
+  backup_enabled = provider::vpsie::bool_flag(var.enable_backups)
+}
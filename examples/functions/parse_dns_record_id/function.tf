@@ -0,0 +1,7 @@
+locals {
+  record = provider::vpsie::parse_dns_record_id(vpsie_dns_record.www.id)
+}
+
+output "record_type" {
+  value = local.record.type # "A"
+}
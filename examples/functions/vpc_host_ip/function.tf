@@ -0,0 +1,8 @@
+# vpsie_vpc stores its network address and prefix length separately.
+output "gateway_ip" {
+  value = provider::vpsie::vpc_host_ip(vpsie_vpc.example.network_range, 1) # "10.0.0.1"
+}
+
+output "last_ip" {
+  value = provider::vpsie::vpc_host_ip("${vpsie_vpc.example.network_range}/${vpsie_vpc.example.network_size}", -2)
+}
@@ -0,0 +1,5 @@
+# List every bucket in the account, then run:
+#   terraform query -generate-config-out=generated.tf
+list "vpsie_bucket" "all" {
+  provider = vpsie
+}
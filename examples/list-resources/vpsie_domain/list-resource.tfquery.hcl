@@ -0,0 +1,5 @@
+# List every domain in the account, then run:
+#   terraform query -generate-config-out=generated.tf
+list "vpsie_domain" "all" {
+  provider = vpsie
+}
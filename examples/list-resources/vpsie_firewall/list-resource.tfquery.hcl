@@ -0,0 +1,5 @@
+# List every firewall group in the account, then run:
+#   terraform query -generate-config-out=generated.tf
+list "vpsie_firewall" "all" {
+  provider = vpsie
+}
@@ -0,0 +1,5 @@
+# List every Kubernetes cluster in the account, then run:
+#   terraform query -generate-config-out=generated.tf
+list "vpsie_kubernetes" "all" {
+  provider = vpsie
+}
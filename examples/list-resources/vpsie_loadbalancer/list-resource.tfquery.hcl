@@ -0,0 +1,5 @@
+# List every load balancer in the account, then run:
+#   terraform query -generate-config-out=generated.tf
+list "vpsie_loadbalancer" "all" {
+  provider = vpsie
+}
@@ -0,0 +1,9 @@
+# List the production servers, then run:
+#   terraform query -generate-config-out=generated.tf
+list "vpsie_server" "prod" {
+  provider = vpsie
+
+  config {
+    tags = ["prod"]
+  }
+}
@@ -0,0 +1,5 @@
+# List every storage volume in the account, then run:
+#   terraform query -generate-config-out=generated.tf
+list "vpsie_storage" "all" {
+  provider = vpsie
+}
@@ -0,0 +1,5 @@
+# List every VPC in the account, then run:
+#   terraform query -generate-config-out=generated.tf
+list "vpsie_vpc" "all" {
+  provider = vpsie
+}
@@ -1,3 +1,10 @@
 provider "vpsie" {
   access_token = var.vpsie_access_token
+
+  # Added to every resource that supports tags.
+  default_tags = ["managed-by-terraform"]
+
+  # Sent when a server is destroyed, without being stored in state.
+  delete_password = var.vpsie_delete_password
+  delete_reason   = "decommissioned by terraform"
 }
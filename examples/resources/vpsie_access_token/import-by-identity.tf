@@ -0,0 +1,6 @@
+import {
+  to = vpsie_access_token.example
+  identity = {
+    identifier = "token-identifier"
+  }
+}
@@ -0,0 +1,4 @@
+import {
+  to = vpsie_access_token.example
+  id = "token-identifier"
+}
@@ -3,3 +3,16 @@ resource "vpsie_access_token" "example" {
   access_token    = "your-access-token"
   expiration_date = "2025-12-31"
 }
+
+# With Terraform 1.11 or later the token value can stay out of state, e.g.
+# when it comes from an ephemeral resource.
+ephemeral "random_password" "token" {
+  length = 40
+}
+
+resource "vpsie_access_token" "write_only" {
+  name                    = "my-other-api-token"
+  access_token_wo         = ephemeral.random_password.token.result
+  access_token_wo_version = 1
+  expiration_date         = "2025-12-31"
+}
@@ -0,0 +1,6 @@
+import {
+  to = vpsie_backup.example
+  identity = {
+    identifier = "backup-identifier"
+  }
+}
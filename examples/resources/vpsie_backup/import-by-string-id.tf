@@ -0,0 +1,4 @@
+import {
+  to = vpsie_backup.example
+  id = "backup-identifier"
+}
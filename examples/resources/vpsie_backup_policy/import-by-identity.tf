@@ -0,0 +1,6 @@
+import {
+  to = vpsie_backup_policy.example
+  identity = {
+    identifier = "policy-identifier"
+  }
+}
@@ -0,0 +1,4 @@
+import {
+  to = vpsie_backup_policy.example
+  id = "policy-identifier"
+}
@@ -0,0 +1,6 @@
+import {
+  to = vpsie_bucket.example
+  identity = {
+    identifier = "bucket-identifier"
+  }
+}
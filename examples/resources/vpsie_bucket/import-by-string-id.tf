@@ -0,0 +1,4 @@
+import {
+  to = vpsie_bucket.example
+  id = "bucket-identifier"
+}
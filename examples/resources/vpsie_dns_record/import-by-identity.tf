@@ -0,0 +1,8 @@
+import {
+  to = vpsie_dns_record.example
+  identity = {
+    domain_identifier = "domain-identifier"
+    type              = "A"
+    name              = "www"
+  }
+}
@@ -0,0 +1,4 @@
+import {
+  to = vpsie_dns_record.example
+  id = "domain-identifier/A/www"
+}
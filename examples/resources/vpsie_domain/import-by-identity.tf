@@ -0,0 +1,6 @@
+import {
+  to = vpsie_domain.example
+  identity = {
+    identifier = "domain-identifier"
+  }
+}
@@ -0,0 +1,4 @@
+import {
+  to = vpsie_domain.example
+  id = "domain-identifier"
+}
@@ -0,0 +1,6 @@
+import {
+  to = vpsie_firewall.example
+  identity = {
+    identifier = "firewall-identifier"
+  }
+}
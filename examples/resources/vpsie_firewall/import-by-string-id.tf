@@ -0,0 +1,4 @@
+import {
+  to = vpsie_firewall.example
+  id = "firewall-identifier"
+}
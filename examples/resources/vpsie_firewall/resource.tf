@@ -1,3 +1,21 @@
 resource "vpsie_firewall" "example" {
   group_name = "my-firewall-group"
+
+  rules = [
+    {
+      in_bound = [
+        {
+          action = "ACCEPT"
+          proto  = "tcp"
+          dport  = "22"
+          source = ["0.0.0.0/0"]
+        },
+        {
+          action = "ACCEPT"
+          proto  = "tcp"
+          dport  = "443"
+        },
+      ]
+    },
+  ]
 }
@@ -0,0 +1,7 @@
+import {
+  to = vpsie_firewall_attachment.example
+  identity = {
+    group_id      = "firewall-group-id"
+    vm_identifier = "vm-identifier"
+  }
+}
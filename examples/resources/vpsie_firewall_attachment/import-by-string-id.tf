@@ -0,0 +1,4 @@
+import {
+  to = vpsie_firewall_attachment.example
+  id = "firewall-group-id/vm-identifier"
+}
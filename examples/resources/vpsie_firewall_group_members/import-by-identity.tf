@@ -0,0 +1,6 @@
+import {
+  to = vpsie_firewall_group_members.web
+  identity = {
+    group_id = "firewall-group-id"
+  }
+}
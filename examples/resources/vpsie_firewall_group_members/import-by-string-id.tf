@@ -0,0 +1,4 @@
+import {
+  to = vpsie_firewall_group_members.web
+  id = "firewall-group-id"
+}
@@ -0,0 +1,8 @@
+resource "vpsie_firewall_group_members" "web" {
+  group_id = vpsie_firewall.web.identifier
+
+  vm_identifiers = [
+    vpsie_server.web_1.identifier,
+    vpsie_server.web_2.identifier,
+  ]
+}
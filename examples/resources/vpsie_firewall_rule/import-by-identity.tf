@@ -0,0 +1,7 @@
+import {
+  to = vpsie_firewall_rule.postgres
+  identity = {
+    group_identifier = "firewall-identifier"
+    identifier       = "rule-identifier"
+  }
+}
@@ -0,0 +1,4 @@
+import {
+  to = vpsie_firewall_rule.postgres
+  id = "firewall-identifier/rule-identifier"
+}
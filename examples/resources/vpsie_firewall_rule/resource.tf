@@ -0,0 +1,13 @@
+resource "vpsie_firewall" "shared" {
+  group_name = "platform-shared"
+}
+
+resource "vpsie_firewall_rule" "postgres" {
+  group_identifier = vpsie_firewall.shared.identifier
+  type             = "in"
+  action           = "ACCEPT"
+  proto            = "tcp"
+  dport            = "5432"
+  source           = ["10.0.0.0/8"]
+  comment          = "postgres from private network"
+}
@@ -0,0 +1,6 @@
+import {
+  to = vpsie_floating_ip.example
+  identity = {
+    id = "1234"
+  }
+}
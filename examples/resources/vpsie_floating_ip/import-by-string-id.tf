@@ -0,0 +1,4 @@
+import {
+  to = vpsie_floating_ip.example
+  id = "1234"
+}
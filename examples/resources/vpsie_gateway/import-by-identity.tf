@@ -0,0 +1,6 @@
+import {
+  to = vpsie_gateway.example
+  identity = {
+    id = 1234
+  }
+}
@@ -0,0 +1,4 @@
+import {
+  to = vpsie_gateway.example
+  id = "1234"
+}
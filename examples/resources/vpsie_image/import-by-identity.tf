@@ -0,0 +1,6 @@
+import {
+  to = vpsie_image.example
+  identity = {
+    identifier = "image-identifier"
+  }
+}
@@ -0,0 +1,4 @@
+import {
+  to = vpsie_image.example
+  id = "image-identifier"
+}
@@ -0,0 +1,6 @@
+import {
+  to = vpsie_kubernetes.example
+  identity = {
+    identifier = "cluster-identifier"
+  }
+}
@@ -0,0 +1,4 @@
+import {
+  to = vpsie_kubernetes.example
+  id = "cluster-identifier"
+}
@@ -0,0 +1,6 @@
+import {
+  to = vpsie_kubernetes_group.example
+  identity = {
+    identifier = "group-identifier"
+  }
+}
@@ -0,0 +1,4 @@
+import {
+  to = vpsie_kubernetes_group.example
+  id = "group-identifier"
+}
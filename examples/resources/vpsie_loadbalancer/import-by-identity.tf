@@ -0,0 +1,6 @@
+import {
+  to = vpsie_loadbalancer.example
+  identity = {
+    identifier = "loadbalancer-identifier"
+  }
+}
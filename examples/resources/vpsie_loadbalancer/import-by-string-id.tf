@@ -0,0 +1,4 @@
+import {
+  to = vpsie_loadbalancer.example
+  id = "loadbalancer-identifier"
+}
@@ -0,0 +1,6 @@
+import {
+  to = vpsie_monitoring_rule.example
+  identity = {
+    identifier = "rule-identifier"
+  }
+}
@@ -0,0 +1,4 @@
+import {
+  to = vpsie_monitoring_rule.example
+  id = "rule-identifier"
+}
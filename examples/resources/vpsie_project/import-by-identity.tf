@@ -0,0 +1,6 @@
+import {
+  to = vpsie_project.example
+  identity = {
+    identifier = "project-identifier"
+  }
+}
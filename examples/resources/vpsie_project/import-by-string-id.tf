@@ -0,0 +1,4 @@
+import {
+  to = vpsie_project.example
+  id = "project-identifier"
+}
@@ -0,0 +1,7 @@
+import {
+  to = vpsie_reverse_dns.example
+  identity = {
+    vm_identifier = "vm-identifier"
+    ip            = "192.0.2.10"
+  }
+}
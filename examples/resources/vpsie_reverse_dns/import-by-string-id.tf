@@ -0,0 +1,4 @@
+import {
+  to = vpsie_reverse_dns.example
+  id = "vm-identifier/192.0.2.10"
+}
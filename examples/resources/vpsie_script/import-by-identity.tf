@@ -0,0 +1,6 @@
+import {
+  to = vpsie_script.example
+  identity = {
+    identifier = "script-identifier"
+  }
+}
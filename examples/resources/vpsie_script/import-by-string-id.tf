@@ -0,0 +1,4 @@
+import {
+  to = vpsie_script.example
+  id = "script-identifier"
+}
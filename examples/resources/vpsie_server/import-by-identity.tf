@@ -0,0 +1,6 @@
+import {
+  to = vpsie_server.example
+  identity = {
+    identifier = "vm-identifier"
+  }
+}
@@ -0,0 +1,4 @@
+import {
+  to = vpsie_server.example
+  id = "vm-identifier"
+}
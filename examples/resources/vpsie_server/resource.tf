@@ -1,9 +1,31 @@
+# The deletion password comes from the provider's delete_password.
 resource "vpsie_server" "example" {
   hostname            = "my-server"
   dc_identifier       = "dc-identifier"
   os_identifier       = "os-identifier"
   resource_identifier = "resource-identifier"
   project_id          = 1
-  password            = "secure-password"
-  delete_reason       = "no longer needed"
+
+  # Start or stop the server, and bring it back if it is powered off
+  # outside Terraform.
+  power_state = "running"
+
+  # Reinstall the server in place when os_identifier changes, keeping its
+  # IP addresses, instead of replacing it.
+  rebuild_on_os_change = true
+
+  # Refuse to destroy the server, and lock it through the API, until this
+  # is set to false in a separate apply.
+  deletion_protection = true
+
+  tags = ["web", "cost-center-a"]
+
+  ssh_key_ids = ["ssh-key-identifier-1", "ssh-key-identifier-2"]
+
+  # Uploaded as a managed cloud-init script and deleted with the server.
+  user_data = <<-EOT
+    #cloud-config
+    packages:
+      - nginx
+  EOT
 }
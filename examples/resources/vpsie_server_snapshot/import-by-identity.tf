@@ -0,0 +1,6 @@
+import {
+  to = vpsie_server_snapshot.example
+  identity = {
+    identifier = "snapshot-identifier"
+  }
+}
@@ -0,0 +1,4 @@
+import {
+  to = vpsie_server_snapshot.example
+  id = "snapshot-identifier"
+}
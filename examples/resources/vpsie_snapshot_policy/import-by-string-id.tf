@@ -0,0 +1,4 @@
+import {
+  to = vpsie_snapshot_policy.example
+  id = "policy-identifier"
+}
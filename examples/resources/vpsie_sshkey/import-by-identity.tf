@@ -0,0 +1,6 @@
+import {
+  to = vpsie_sshkey.example
+  identity = {
+    identifier = "sshkey-identifier"
+  }
+}
@@ -0,0 +1,4 @@
+import {
+  to = vpsie_sshkey.example
+  id = "sshkey-identifier"
+}
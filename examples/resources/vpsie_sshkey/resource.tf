@@ -2,3 +2,11 @@ resource "vpsie_sshkey" "example" {
   name        = "my-ssh-key"
   private_key = "ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAAB..."
 }
+
+# With Terraform 1.11 or later the key can stay out of state. Bump the
+# version to replace the key.
+resource "vpsie_sshkey" "write_only" {
+  name                   = "my-other-ssh-key"
+  private_key_wo         = file("~/.ssh/id_ed25519.pub")
+  private_key_wo_version = 1
+}
@@ -0,0 +1,6 @@
+import {
+  to = vpsie_storage.example
+  identity = {
+    identifier = "storage-identifier"
+  }
+}
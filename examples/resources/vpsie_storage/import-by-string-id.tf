@@ -0,0 +1,4 @@
+import {
+  to = vpsie_storage.example
+  id = "storage-identifier"
+}
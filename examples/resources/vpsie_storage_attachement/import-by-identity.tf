@@ -0,0 +1,6 @@
+import {
+  to = vpsie_storage_attachement.example
+  identity = {
+    storage_identifier = "storage-identifier"
+  }
+}
@@ -0,0 +1,4 @@
+import {
+  to = vpsie_storage_attachement.example
+  id = "storage-identifier"
+}
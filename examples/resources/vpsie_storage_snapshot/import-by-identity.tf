@@ -0,0 +1,6 @@
+import {
+  to = vpsie_storage_snapshot.example
+  identity = {
+    identifier = "snapshot-identifier"
+  }
+}
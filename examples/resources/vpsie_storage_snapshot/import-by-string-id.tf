@@ -0,0 +1,4 @@
+import {
+  to = vpsie_storage_snapshot.example
+  id = "snapshot-identifier"
+}
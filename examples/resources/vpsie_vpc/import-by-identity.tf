@@ -0,0 +1,6 @@
+import {
+  to = vpsie_vpc.example
+  identity = {
+    id = 1234
+  }
+}
@@ -0,0 +1,4 @@
+import {
+  to = vpsie_vpc.example
+  id = "1234"
+}
@@ -0,0 +1,7 @@
+import {
+  to = vpsie_vpc_server_assignment.example
+  identity = {
+    vm_identifier = "vm-identifier"
+    vpc_id        = 1234
+  }
+}
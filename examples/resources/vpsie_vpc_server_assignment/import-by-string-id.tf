@@ -0,0 +1,4 @@
+import {
+  to = vpsie_vpc_server_assignment.example
+  id = "vm-identifier/1234"
+}
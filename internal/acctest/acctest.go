@@ -2,10 +2,12 @@ package acctest
 
 import (
 	"os"
+	"os/exec"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/vpsie/terraform-provider-vpsie/internal/fakeapi"
 	"github.com/vpsie/terraform-provider-vpsie/internal/provider"
 )
 
@@ -17,8 +19,36 @@ var TestAccProtoV6ProviderFactories = map[string]func() (tfprotov6.ProviderServe
 	"vpsie": providerserver.NewProtocol6WithError(provider.New("test")()),
 }
 
+// FakeProtoV6ProviderFactories are like TestAccProtoV6ProviderFactories, but
+// the provider talks to backend instead of the VPSie API. Use them with
+// resource.UnitTest and PreCheckTerraformCLI to run a lifecycle test offline.
+func FakeProtoV6ProviderFactories(backend *fakeapi.Backend) map[string]func() (tfprotov6.ProviderServer, error) {
+	client := backend.Client()
+
+	return map[string]func() (tfprotov6.ProviderServer, error){
+		"vpsie": func() (tfprotov6.ProviderServer, error) {
+			return providerserver.NewProtocol6WithError(provider.NewWithClient("test", client)())()
+		},
+	}
+}
+
 func TestAccPreCheck(t *testing.T) {
 	if v := os.Getenv("VPSIE_ACCESS_TOKEN"); v == "" {
 		t.Fatal("VPSIE_ACCESS_TOKEN must be set for acceptance tests")
 	}
 }
+
+// PreCheckTerraformCLI skips a test run against the fake API when there is
+// no Terraform CLI to drive it, rather than letting the test framework try
+// to download one.
+func PreCheckTerraformCLI(t *testing.T) {
+	t.Helper()
+
+	if os.Getenv("TF_ACC_TERRAFORM_PATH") != "" || os.Getenv("TF_ACC_TERRAFORM_VERSION") != "" {
+		return
+	}
+
+	if _, err := exec.LookPath("terraform"); err != nil {
+		t.Skip("terraform CLI not found in PATH; install it or set TF_ACC_TERRAFORM_PATH to run this test")
+	}
+}
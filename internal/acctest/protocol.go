@@ -0,0 +1,444 @@
+package acctest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/vpsie/govpsie"
+	"github.com/vpsie/terraform-provider-vpsie/internal/fakeapi"
+	"github.com/vpsie/terraform-provider-vpsie/internal/provider"
+)
+
+// FakeProtoV6Server returns a provider server that talks to backend, already
+// configured with providerConfig, along with its schemas. It lets a test
+// drive the plugin protocol directly, the way Terraform does, for behaviour
+// that does not need a Terraform CLI.
+func FakeProtoV6Server(t *testing.T, backend *fakeapi.Backend, providerConfig map[string]tftypes.Value) (tfprotov6.ProviderServer, *tfprotov6.GetProviderSchemaResponse) {
+	t.Helper()
+	return ProtoV6Server(t, backend.Client(), providerConfig)
+}
+
+// ProtoV6Server is like FakeProtoV6Server, but the provider talks to client,
+// such as one returned by apitest.Server.Client.
+func ProtoV6Server(t *testing.T, client *govpsie.Client, providerConfig map[string]tftypes.Value) (tfprotov6.ProviderServer, *tfprotov6.GetProviderSchemaResponse) {
+	t.Helper()
+	ctx := context.Background()
+
+	server, err := providerserver.NewProtocol6WithError(provider.NewWithClient("test", client)())()
+	if err != nil {
+		t.Fatalf("provider server: %v", err)
+	}
+
+	schemas, err := server.GetProviderSchema(ctx, &tfprotov6.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatalf("GetProviderSchema: %v", err)
+	}
+	if len(schemas.Diagnostics) > 0 {
+		t.Fatalf("GetProviderSchema: %s", schemas.Diagnostics[0].Detail)
+	}
+
+	providerType := schemas.Provider.ValueType()
+	resp, err := server.ConfigureProvider(ctx, &tfprotov6.ConfigureProviderRequest{
+		Config: DynamicValue(t, providerType, ObjectValue(providerType, providerConfig)),
+	})
+	if err != nil {
+		t.Fatalf("ConfigureProvider: %v", err)
+	}
+	if len(resp.Diagnostics) > 0 {
+		t.Fatalf("ConfigureProvider: %s", resp.Diagnostics[0].Detail)
+	}
+
+	return server, schemas
+}
+
+// ObjectValue returns an object of typ holding values, with every other
+// attribute null.
+func ObjectValue(typ tftypes.Type, values map[string]tftypes.Value) tftypes.Value {
+	object := typ.(tftypes.Object)
+
+	attrs := make(map[string]tftypes.Value, len(object.AttributeTypes))
+	for name, attrType := range object.AttributeTypes {
+		if v, ok := values[name]; ok {
+			attrs[name] = v
+			continue
+		}
+		attrs[name] = tftypes.NewValue(attrType, nil)
+	}
+
+	return tftypes.NewValue(typ, attrs)
+}
+
+// DynamicValue encodes value for a protocol request.
+func DynamicValue(t *testing.T, typ tftypes.Type, value tftypes.Value) *tfprotov6.DynamicValue {
+	t.Helper()
+
+	dv, err := tfprotov6.NewDynamicValue(typ, value)
+	if err != nil {
+		t.Fatalf("NewDynamicValue: %v", err)
+	}
+	return &dv
+}
+
+// InvokeAction invokes the action actionType with the given configuration,
+// the way terraform apply -invoke does, and returns its progress messages
+// and final diagnostics.
+func InvokeAction(t *testing.T, server tfprotov6.ProviderServer, schemas *tfprotov6.GetProviderSchemaResponse, actionType string, config map[string]tftypes.Value) ([]string, []*tfprotov6.Diagnostic) {
+	t.Helper()
+
+	actionSchema, ok := schemas.ActionSchemas[actionType]
+	if !ok {
+		t.Fatalf("action %s is not registered", actionType)
+	}
+	typ := actionSchema.Schema.ValueType()
+
+	actionServer, ok := server.(tfprotov6.ActionServer)
+	if !ok {
+		t.Fatalf("provider server %T does not serve actions", server)
+	}
+
+	stream, err := actionServer.InvokeAction(context.Background(), &tfprotov6.InvokeActionRequest{
+		ActionType: actionType,
+		Config:     DynamicValue(t, typ, ObjectValue(typ, config)),
+	})
+	if err != nil {
+		t.Fatalf("InvokeAction: %v", err)
+	}
+
+	var progress []string
+	var diags []*tfprotov6.Diagnostic
+	for event := range stream.Events {
+		switch e := event.Type.(type) {
+		case tfprotov6.ProgressInvokeActionEventType:
+			progress = append(progress, e.Message)
+		case tfprotov6.CompletedInvokeActionEventType:
+			diags = e.Diagnostics
+		}
+	}
+
+	return progress, diags
+}
+
+// ListResult is a decoded result of a list resource.
+type ListResult struct {
+	DisplayName string
+	Identity    map[string]tftypes.Value
+	// Resource is nil unless the resource data was requested.
+	Resource map[string]tftypes.Value
+}
+
+// ListResource lists typeName with the given configuration, the way
+// terraform query does, and returns its decoded results along with the
+// diagnostics of any result that carried them.
+func ListResource(t *testing.T, server tfprotov6.ProviderServer, schemas *tfprotov6.GetProviderSchemaResponse, typeName string, config map[string]tftypes.Value, includeResource bool) ([]ListResult, []*tfprotov6.Diagnostic) {
+	t.Helper()
+	ctx := context.Background()
+
+	configSchema, ok := schemas.ListResourceSchemas[typeName]
+	if !ok {
+		t.Fatalf("list resource %s is not registered", typeName)
+	}
+	configType := configSchema.ValueType()
+
+	identitySchemas, err := server.GetResourceIdentitySchemas(ctx, &tfprotov6.GetResourceIdentitySchemasRequest{})
+	if err != nil {
+		t.Fatalf("GetResourceIdentitySchemas: %v", err)
+	}
+	identityType := identitySchemas.IdentitySchemas[typeName].ValueType()
+	resourceType := schemas.ResourceSchemas[typeName].ValueType()
+
+	listServer, ok := server.(tfprotov6.ListResourceServer)
+	if !ok {
+		t.Fatalf("provider server %T does not serve list resources", server)
+	}
+
+	stream, err := listServer.ListResource(ctx, &tfprotov6.ListResourceRequest{
+		TypeName:        typeName,
+		Config:          DynamicValue(t, configType, ObjectValue(configType, config)),
+		IncludeResource: includeResource,
+	})
+	if err != nil {
+		t.Fatalf("ListResource: %v", err)
+	}
+
+	var results []ListResult
+	var diags []*tfprotov6.Diagnostic
+	for event := range stream.Results {
+		if len(event.Diagnostics) > 0 {
+			diags = append(diags, event.Diagnostics...)
+			continue
+		}
+
+		result := ListResult{DisplayName: event.DisplayName}
+		result.Identity = decodeObject(t, identityType, event.Identity.IdentityData)
+		if event.Resource != nil {
+			result.Resource = decodeObject(t, resourceType, event.Resource)
+		}
+		results = append(results, result)
+	}
+
+	return results, diags
+}
+
+func decodeObject(t *testing.T, typ tftypes.Type, dv *tfprotov6.DynamicValue) map[string]tftypes.Value {
+	t.Helper()
+
+	value, err := dv.Unmarshal(typ)
+	if err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	var attrs map[string]tftypes.Value
+	if err := value.As(&attrs); err != nil {
+		t.Fatalf("As: %v", err)
+	}
+	return attrs
+}
+
+// ImportedResource is a decoded resource returned by an import.
+type ImportedResource struct {
+	State    map[string]tftypes.Value
+	Identity map[string]tftypes.Value
+}
+
+// ImportResourceState imports typeName the way an import block does, either
+// by id or, when id is empty, by identity, and returns the decoded imported
+// resource along with the diagnostics of the import.
+func ImportResourceState(t *testing.T, server tfprotov6.ProviderServer, schemas *tfprotov6.GetProviderSchemaResponse, typeName, id string, identity map[string]tftypes.Value) (*ImportedResource, []*tfprotov6.Diagnostic) {
+	t.Helper()
+	ctx := context.Background()
+
+	identitySchemas, err := server.GetResourceIdentitySchemas(ctx, &tfprotov6.GetResourceIdentitySchemasRequest{})
+	if err != nil {
+		t.Fatalf("GetResourceIdentitySchemas: %v", err)
+	}
+	identitySchema, ok := identitySchemas.IdentitySchemas[typeName]
+	if !ok {
+		t.Fatalf("resource %s has no identity schema", typeName)
+	}
+	identityType := identitySchema.ValueType()
+	resourceType := schemas.ResourceSchemas[typeName].ValueType()
+
+	req := &tfprotov6.ImportResourceStateRequest{
+		TypeName: typeName,
+		ID:       id,
+	}
+	if identity != nil {
+		req.Identity = &tfprotov6.ResourceIdentityData{
+			IdentityData: DynamicValue(t, identityType, ObjectValue(identityType, identity)),
+		}
+	}
+
+	resp, err := server.ImportResourceState(ctx, req)
+	if err != nil {
+		t.Fatalf("ImportResourceState: %v", err)
+	}
+	if len(resp.Diagnostics) > 0 || len(resp.ImportedResources) == 0 {
+		return nil, resp.Diagnostics
+	}
+
+	imported := resp.ImportedResources[0]
+	result := &ImportedResource{State: decodeObject(t, resourceType, imported.State)}
+	if imported.Identity != nil {
+		result.Identity = decodeObject(t, identityType, imported.Identity.IdentityData)
+	}
+	return result, nil
+}
+
+// ResourceState is the decoded state and identity of a managed resource.
+type ResourceState struct {
+	State    map[string]tftypes.Value
+	Identity *tfprotov6.ResourceIdentityData
+}
+
+// PlanResource plans config for typeName on top of prior, nil for a new
+// resource. Like Terraform, it proposes the prior value of computed
+// attributes that config leaves null.
+func PlanResource(t *testing.T, server tfprotov6.ProviderServer, schemas *tfprotov6.GetProviderSchemaResponse, typeName string, prior *ResourceState, config map[string]tftypes.Value) *tfprotov6.PlanResourceChangeResponse {
+	t.Helper()
+
+	resourceSchema, ok := schemas.ResourceSchemas[typeName]
+	if !ok {
+		t.Fatalf("resource %s is not registered", typeName)
+	}
+	typ := resourceSchema.ValueType()
+	configValue := ObjectValue(typ, config)
+
+	priorValue := tftypes.NewValue(typ, nil)
+	proposed := configValue
+	var priorIdentity *tfprotov6.ResourceIdentityData
+	if prior != nil {
+		priorValue = tftypes.NewValue(typ, prior.State)
+		priorIdentity = prior.Identity
+
+		values := map[string]tftypes.Value{}
+		for _, attr := range resourceSchema.Block.Attributes {
+			if v, ok := config[attr.Name]; ok && !v.IsNull() {
+				values[attr.Name] = v
+			} else if attr.Computed {
+				values[attr.Name] = prior.State[attr.Name]
+			}
+		}
+		proposed = ObjectValue(typ, values)
+	}
+
+	resp, err := server.PlanResourceChange(context.Background(), &tfprotov6.PlanResourceChangeRequest{
+		TypeName:         typeName,
+		PriorState:       DynamicValue(t, typ, priorValue),
+		ProposedNewState: DynamicValue(t, typ, proposed),
+		Config:           DynamicValue(t, typ, configValue),
+		PriorIdentity:    priorIdentity,
+	})
+	if err != nil {
+		t.Fatalf("PlanResourceChange: %v", err)
+	}
+
+	return resp
+}
+
+// ApplyResource plans and applies config for typeName on top of prior, nil
+// for a new resource, and returns the new resource or the diagnostics of the
+// step that failed. Like Terraform, it reports a planned value that apply did
+// not keep as an error. It only applies in-place changes.
+func ApplyResource(t *testing.T, server tfprotov6.ProviderServer, schemas *tfprotov6.GetProviderSchemaResponse, typeName string, prior *ResourceState, config map[string]tftypes.Value) (*ResourceState, []*tfprotov6.Diagnostic) {
+	t.Helper()
+	typ := schemas.ResourceSchemas[typeName].ValueType()
+
+	plan := PlanResource(t, server, schemas, typeName, prior, config)
+	if len(plan.Diagnostics) > 0 {
+		return nil, plan.Diagnostics
+	}
+	if prior != nil && len(plan.RequiresReplace) > 0 {
+		t.Fatalf("expected an in-place update of %s, got replacement for %v", typeName, plan.RequiresReplace)
+	}
+
+	priorValue := tftypes.NewValue(typ, nil)
+	if prior != nil {
+		priorValue = tftypes.NewValue(typ, prior.State)
+	}
+
+	resp, err := server.ApplyResourceChange(context.Background(), &tfprotov6.ApplyResourceChangeRequest{
+		TypeName:        typeName,
+		PriorState:      DynamicValue(t, typ, priorValue),
+		PlannedState:    plan.PlannedState,
+		Config:          DynamicValue(t, typ, ObjectValue(typ, config)),
+		PlannedIdentity: plan.PlannedIdentity,
+	})
+	if err != nil {
+		t.Fatalf("ApplyResourceChange: %v", err)
+	}
+	if len(resp.Diagnostics) > 0 {
+		return nil, resp.Diagnostics
+	}
+
+	planned := decodeObject(t, typ, plan.PlannedState)
+	state := decodeObject(t, typ, resp.NewState)
+	var diags []*tfprotov6.Diagnostic
+	for name, want := range planned {
+		if want.IsFullyKnown() && !want.Equal(state[name]) {
+			diags = append(diags, &tfprotov6.Diagnostic{
+				Severity: tfprotov6.DiagnosticSeverityError,
+				Summary:  "Provider produced inconsistent result after apply",
+				Detail:   name + " was planned as " + want.String() + " but is now " + state[name].String(),
+			})
+		}
+	}
+	if len(diags) > 0 {
+		return nil, diags
+	}
+
+	return &ResourceState{State: state, Identity: resp.NewIdentity}, nil
+}
+
+// ReadResource refreshes current the way terraform refresh does.
+func ReadResource(t *testing.T, server tfprotov6.ProviderServer, schemas *tfprotov6.GetProviderSchemaResponse, typeName string, current *ResourceState) (*ResourceState, []*tfprotov6.Diagnostic) {
+	t.Helper()
+	typ := schemas.ResourceSchemas[typeName].ValueType()
+
+	resp, err := server.ReadResource(context.Background(), &tfprotov6.ReadResourceRequest{
+		TypeName:        typeName,
+		CurrentState:    DynamicValue(t, typ, tftypes.NewValue(typ, current.State)),
+		CurrentIdentity: current.Identity,
+	})
+	if err != nil {
+		t.Fatalf("ReadResource: %v", err)
+	}
+	if len(resp.Diagnostics) > 0 {
+		return nil, resp.Diagnostics
+	}
+
+	value, err := resp.NewState.Unmarshal(typ)
+	if err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if value.IsNull() {
+		return nil, nil
+	}
+
+	return &ResourceState{State: decodeObject(t, typ, resp.NewState), Identity: resp.NewIdentity}, nil
+}
+
+// DestroyResource destroys current the way terraform destroy does and
+// returns the diagnostics of the destroy.
+func DestroyResource(t *testing.T, server tfprotov6.ProviderServer, schemas *tfprotov6.GetProviderSchemaResponse, typeName string, current *ResourceState) []*tfprotov6.Diagnostic {
+	t.Helper()
+	typ := schemas.ResourceSchemas[typeName].ValueType()
+	noState := tftypes.NewValue(typ, nil)
+
+	resp, err := server.ApplyResourceChange(context.Background(), &tfprotov6.ApplyResourceChangeRequest{
+		TypeName:     typeName,
+		PriorState:   DynamicValue(t, typ, tftypes.NewValue(typ, current.State)),
+		PlannedState: DynamicValue(t, typ, noState),
+		Config:       DynamicValue(t, typ, noState),
+	})
+	if err != nil {
+		t.Fatalf("ApplyResourceChange: %v", err)
+	}
+
+	return resp.Diagnostics
+}
+
+// CheckDeletionProtection turns on deletion_protection for current, checks
+// that destroying it then fails without removing it, turns protection off
+// again and destroys it. config is the configuration of current without
+// deletion_protection.
+func CheckDeletionProtection(t *testing.T, server tfprotov6.ProviderServer, schemas *tfprotov6.GetProviderSchemaResponse, typeName string, current *ResourceState, config map[string]tftypes.Value) {
+	t.Helper()
+
+	withProtection := func(enabled bool) map[string]tftypes.Value {
+		values := map[string]tftypes.Value{"deletion_protection": tftypes.NewValue(tftypes.Bool, enabled)}
+		for name, v := range config {
+			values[name] = v
+		}
+		return values
+	}
+
+	protected, diags := ApplyResource(t, server, schemas, typeName, current, withProtection(true))
+	if len(diags) > 0 {
+		t.Fatalf("enable protection: %s: %s", diags[0].Summary, diags[0].Detail)
+	}
+	if !protected.State["deletion_protection"].Equal(tftypes.NewValue(tftypes.Bool, true)) {
+		t.Fatalf("expected deletion_protection true, got %s", protected.State["deletion_protection"])
+	}
+
+	diags = DestroyResource(t, server, schemas, typeName, protected)
+	if len(diags) == 0 || diags[0].Summary != "Deletion protection enabled" {
+		t.Fatalf("expected a deletion protection diagnostic, got %v", diags)
+	}
+	if read, diags := ReadResource(t, server, schemas, typeName, protected); len(diags) > 0 || read == nil {
+		t.Fatalf("expected the protected resource to survive the destroy, got %v", diags)
+	}
+
+	unprotected, diags := ApplyResource(t, server, schemas, typeName, protected, withProtection(false))
+	if len(diags) > 0 {
+		t.Fatalf("disable protection: %s: %s", diags[0].Summary, diags[0].Detail)
+	}
+
+	if diags := DestroyResource(t, server, schemas, typeName, unprotected); len(diags) > 0 {
+		t.Fatalf("destroy: %s: %s", diags[0].Summary, diags[0].Detail)
+	}
+	if read, diags := ReadResource(t, server, schemas, typeName, unprotected); len(diags) > 0 || read != nil {
+		t.Fatalf("expected the resource to be gone after the destroy, got %v and %v", read, diags)
+	}
+}
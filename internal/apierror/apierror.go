@@ -0,0 +1,167 @@
+// Package apierror classifies the errors returned by the VPSie API.
+//
+// govpsie turns a failed response into an error carrying only the API's
+// message, so Transport appends the HTTP status, API error code and request
+// ID to that message before govpsie reads it, and Parse recovers them from
+// the returned error.
+package apierror
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Kind is the class of an API error. Only the classes the provider acts on
+// have a kind; other failures, such as a rejected token or request body, are
+// Unknown and keep their status and code for diagnostics.
+type Kind int
+
+const (
+	Unknown Kind = iota
+	NotFound
+	Conflict
+	RateLimited
+)
+
+func (k Kind) String() string {
+	switch k {
+	case NotFound:
+		return "not found"
+	case Conflict:
+		return "conflict"
+	case RateLimited:
+		return "rate limited"
+	}
+	return "unknown"
+}
+
+// Error is a classified API error.
+type Error struct {
+	Kind       Kind
+	StatusCode int
+	Code       string
+	RequestID  string
+	Message    string
+
+	err error
+}
+
+func (e *Error) Error() string {
+	if e.StatusCode == 0 {
+		return e.Message
+	}
+	return e.Message + " " + details(e.StatusCode, e.Code, e.RequestID)
+}
+
+func (e *Error) Unwrap() error {
+	return e.err
+}
+
+// detailsPattern matches the suffix written by details.
+var detailsPattern = regexp.MustCompile(`\s*\(HTTP (\d{3})(?:, code ([^,)]+))?(?:, request ID ([^)]+))?\)$`)
+
+func details(statusCode int, code, requestID string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "(HTTP %d", statusCode)
+	if code != "" {
+		fmt.Fprintf(&b, ", code %s", code)
+	}
+	if requestID != "" {
+		fmt.Fprintf(&b, ", request ID %s", requestID)
+	}
+	b.WriteString(")")
+
+	return b.String()
+}
+
+// Parse classifies err. It returns nil for a nil error and an Error of kind
+// Unknown for errors that did not come from the API.
+func Parse(err error) *Error {
+	if err == nil {
+		return nil
+	}
+
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		return apiErr
+	}
+
+	message := err.Error()
+	match := detailsPattern.FindStringSubmatchIndex(message)
+	if match == nil {
+		return &Error{Kind: classifyMessage(message), Message: message, err: err}
+	}
+
+	statusCode, _ := strconv.Atoi(message[match[2]:match[3]])
+	apiErr = &Error{
+		StatusCode: statusCode,
+		Message:    message[:match[0]],
+		err:        err,
+	}
+	if match[4] >= 0 {
+		apiErr.Code = message[match[4]:match[5]]
+	}
+	if match[6] >= 0 {
+		apiErr.RequestID = message[match[6]:match[7]]
+	}
+	apiErr.Kind = classify(statusCode)
+
+	return apiErr
+}
+
+func classify(statusCode int) Kind {
+	switch statusCode {
+	case http.StatusNotFound, http.StatusGone:
+		return NotFound
+	case http.StatusConflict, http.StatusLocked:
+		return Conflict
+	case http.StatusTooManyRequests:
+		return RateLimited
+	}
+	return Unknown
+}
+
+// govpsieNotFoundPattern matches the errors govpsie builds itself when a
+// lookup it does by listing comes back empty.
+var govpsieNotFoundPattern = regexp.MustCompile(`^(image not found|gateway with id \d+ not found)$`)
+
+// classifyMessage handles errors without an HTTP status. Only the lookup
+// errors govpsie builds itself are recognised; the provider's own lookups
+// return errors made by NotFoundf.
+func classifyMessage(message string) Kind {
+	if govpsieNotFoundPattern.MatchString(message) {
+		return NotFound
+	}
+	return Unknown
+}
+
+// NotFoundf returns an error of kind NotFound, for lookups that find nothing
+// in a list the API returned successfully.
+func NotFoundf(format string, a ...any) error {
+	return &Error{Kind: NotFound, Message: fmt.Sprintf(format, a...)}
+}
+
+// Is reports whether err is an API error of kind.
+func Is(err error, kind Kind) bool {
+	return err != nil && Parse(err).Kind == kind
+}
+
+// IsNotFound reports whether err means the object does not exist.
+func IsNotFound(err error) bool {
+	return Is(err, NotFound)
+}
+
+// IsConflict reports whether err means the object is busy or in a state
+// that does not allow the request.
+func IsConflict(err error) bool {
+	return Is(err, Conflict)
+}
+
+// IsRateLimited reports whether err means the request was throttled.
+func IsRateLimited(err error) bool {
+	return Is(err, RateLimited)
+}
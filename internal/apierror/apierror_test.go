@@ -0,0 +1,87 @@
+package apierror
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/vpsie/govpsie"
+)
+
+func TestUnitAPIError_Classify(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		kind   Kind
+	}{
+		{name: "not found", status: http.StatusNotFound, body: `{"error":true,"code":404,"message":"Server does not exist"}`, kind: NotFound},
+		{name: "conflict", status: http.StatusConflict, body: `{"error":true,"message":"Server is busy"}`, kind: Conflict},
+		{name: "rate limited", status: http.StatusTooManyRequests, body: `{"error":true,"message":"Slow down"}`, kind: RateLimited},
+		{name: "unauthorized", status: http.StatusUnauthorized, body: `{"error":true,"message":"Invalid token"}`, kind: Unknown},
+		{name: "validation", status: http.StatusUnprocessableEntity, body: `{"error":true,"code":"E_HOSTNAME","message":"Invalid hostname"}`, kind: Unknown},
+		{name: "non-json body", status: http.StatusNotFound, body: `<html>Not Found</html>`, kind: NotFound},
+		{name: "server error", status: http.StatusInternalServerError, body: `{"error":true,"message":"Oops"}`, kind: Unknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("X-Request-Id", "req-123")
+				w.WriteHeader(tt.status)
+				fmt.Fprint(w, tt.body)
+			}))
+			defer server.Close()
+
+			client := govpsie.NewClient(&http.Client{Transport: NewTransport(nil)})
+			if err := client.SetBaseURL(server.URL); err != nil {
+				t.Fatal(err)
+			}
+
+			req, err := client.NewRequest(context.Background(), http.MethodGet, "/apps/v2/vm/abc", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = client.Do(context.Background(), req, nil)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+
+			apiErr := Parse(err)
+			if apiErr.Kind != tt.kind {
+				t.Fatalf("expected kind %s, got %s (%q)", tt.kind, apiErr.Kind, err)
+			}
+			if apiErr.StatusCode != tt.status || apiErr.RequestID != "req-123" {
+				t.Fatalf("expected status %d and request ID req-123, got %+v", tt.status, apiErr)
+			}
+		})
+	}
+}
+
+func TestUnitAPIError_Parse(t *testing.T) {
+	apiErr := Parse(errors.New("Invalid hostname (HTTP 422, code E_HOSTNAME, request ID abc-1)"))
+	if apiErr.Kind != Unknown || apiErr.StatusCode != 422 || apiErr.Code != "E_HOSTNAME" ||
+		apiErr.RequestID != "abc-1" || apiErr.Message != "Invalid hostname" {
+		t.Fatalf("unexpected parse result: %+v", apiErr)
+	}
+
+	wrapped := fmt.Errorf("deleting server: %w", errors.New("Server does not exist (HTTP 404)"))
+	if !IsNotFound(wrapped) {
+		t.Fatalf("expected wrapped 404 to be not found")
+	}
+
+	if !IsNotFound(errors.New("image not found")) || !IsNotFound(errors.New("gateway with id 7 not found")) {
+		t.Fatalf("expected govpsie lookup errors to be not found")
+	}
+	if IsNotFound(errors.New("not found route table for gateway")) || IsNotFound(errors.New("script with name web not found")) || IsNotFound(nil) {
+		t.Fatalf("expected unrelated errors not to be not found")
+	}
+
+	notFound := fmt.Errorf("reading volume: %w", NotFoundf("volume with identifier %s not found", "vol-1"))
+	if !IsNotFound(notFound) || notFound.Error() != "reading volume: volume with identifier vol-1 not found" {
+		t.Fatalf("unexpected NotFoundf error: %v", notFound)
+	}
+}
@@ -0,0 +1,96 @@
+package apierror
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// maxErrorBody bounds how much of a failed response is read.
+const maxErrorBody = 1 << 20
+
+// requestIDHeaders are the response headers a request ID is read from.
+var requestIDHeaders = []string{"X-Request-Id", "Request-Id", "X-Correlation-Id", "X-Trace-Id"}
+
+// Transport rewrites failed API responses so that the error govpsie returns
+// for them carries the HTTP status, API error code and request ID.
+type Transport struct {
+	Base http.RoundTripper
+}
+
+// NewTransport returns a Transport wrapping base, or http.DefaultTransport
+// when base is nil.
+func NewTransport(base http.RoundTripper) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &Transport{Base: base}
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.Base.RoundTrip(req)
+	if err != nil || resp.StatusCode < http.StatusBadRequest {
+		return resp, err
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	body = annotate(body, resp.StatusCode, requestID(resp.Header))
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	resp.ContentLength = int64(len(body))
+	resp.Header.Set("Content-Length", strconv.Itoa(len(body)))
+
+	return resp, nil
+}
+
+// annotate returns an error body whose message has the details appended.
+// The body is rebuilt rather than edited, since govpsie fails to decode
+// bodies that are not JSON or carry a non-numeric code and would lose the
+// status.
+func annotate(body []byte, statusCode int, requestID string) []byte {
+	var rsp map[string]any
+	if err := json.Unmarshal(body, &rsp); err != nil || rsp == nil {
+		rsp = map[string]any{}
+		if text := strings.TrimSpace(string(body)); text != "" && len(text) < 512 {
+			rsp["message"] = text
+		}
+	}
+
+	message, _ := rsp["message"].(string)
+	if message == "" {
+		message = http.StatusText(statusCode)
+	}
+
+	code := ""
+	switch v := rsp["code"].(type) {
+	case string:
+		code = v
+	case float64:
+		code = strconv.FormatFloat(v, 'f', -1, 64)
+	}
+
+	// Only the fields govpsie decodes are kept, in the types it expects.
+	annotated, _ := json.Marshal(map[string]any{
+		"error":   true,
+		"message": message + " " + details(statusCode, code, requestID),
+	})
+
+	return annotated
+}
+
+func requestID(header http.Header) string {
+	for _, name := range requestIDHeaders {
+		if id := header.Get(name); id != "" {
+			return id
+		}
+	}
+	return ""
+}
@@ -0,0 +1,124 @@
+// Package apitest stands in for the VPSie API over HTTP in tests.
+//
+// Unlike fakeapi, which answers govpsie's service interfaces from memory,
+// a Server receives the requests a govpsie client actually sends. Tests use
+// it to check the method, path and body of a call and that its response is
+// decoded, for endpoints whose request shape matters.
+package apitest
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/vpsie/govpsie"
+	"github.com/vpsie/terraform-provider-vpsie/internal/apierror"
+)
+
+// Request is a request received by a Server.
+type Request struct {
+	Method string
+	Path   string
+	Body   string
+}
+
+// Server is an HTTP server that records every request and answers it with
+// the response registered for its method and path. A request without a
+// registered response fails the test.
+type Server struct {
+	t      *testing.T
+	client *govpsie.Client
+
+	mu        sync.Mutex
+	responses map[string]func(Request) string
+	requests  []Request
+}
+
+// New starts a Server that is closed when t ends.
+func New(t *testing.T) *Server {
+	t.Helper()
+
+	s := &Server{t: t, responses: map[string]func(Request) string{}}
+	server := httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	t.Cleanup(server.Close)
+
+	s.client = govpsie.NewClient(&http.Client{Transport: apierror.NewTransport(nil)})
+	if err := s.client.SetBaseURL(server.URL); err != nil {
+		t.Fatalf("SetBaseURL: %v", err)
+	}
+
+	return s
+}
+
+// Handle answers method requests to path with the JSON body.
+func (s *Server) Handle(method, path, body string) {
+	s.HandleFunc(method, path, func(Request) string { return body })
+}
+
+// HandleFunc answers method requests to path with the JSON body respond
+// returns for them, for responses that depend on an earlier request.
+func (s *Server) HandleFunc(method, path string, respond func(Request) string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.responses[method+" "+path] = respond
+}
+
+// Client returns a govpsie client that talks to s.
+func (s *Server) Client() *govpsie.Client {
+	return s.client
+}
+
+// Requests returns the method requests received for path, oldest first.
+func (s *Server) Requests(method, path string) []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var requests []Request
+	for _, r := range s.requests {
+		if r.Method == method && r.Path == path {
+			requests = append(requests, r)
+		}
+	}
+	return requests
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	request := Request{Method: r.Method, Path: r.URL.Path, Body: string(body)}
+
+	s.mu.Lock()
+	s.requests = append(s.requests, request)
+	respond, ok := s.responses[r.Method+" "+r.URL.Path]
+	s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	if !ok {
+		s.t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = io.WriteString(w, `{"error":true,"message":"not found"}`)
+		return
+	}
+	_, _ = io.WriteString(w, respond(request))
+}
+
+// EqualJSON reports a test error unless got and want hold the same JSON
+// value.
+func EqualJSON(t *testing.T, got, want string) {
+	t.Helper()
+
+	var gotValue, wantValue any
+	if err := json.Unmarshal([]byte(got), &gotValue); err != nil {
+		t.Errorf("invalid JSON %q: %v", got, err)
+		return
+	}
+	if err := json.Unmarshal([]byte(want), &wantValue); err != nil {
+		t.Fatalf("invalid expected JSON %q: %v", want, err)
+	}
+	if !reflect.DeepEqual(gotValue, wantValue) {
+		t.Errorf("expected JSON %s, got %s", want, got)
+	}
+}
@@ -0,0 +1,23 @@
+// Package deletion holds the provider-level settings that resources send to
+// the VPSie API when they are destroyed. Terraform does not pass a resource's
+// configuration to Delete, so these are the way to supply a deletion password
+// without keeping it in state. It also holds the deletion_protection
+// attribute that critical resources share.
+package deletion
+
+// Settings are the deletion settings of a provider.
+type Settings struct {
+	// Password confirms the deletion of a server.
+	Password string
+	// Reason and Note are recorded by the API for deletions that ask for them.
+	Reason string
+	Note   string
+}
+
+// Pick returns value, or fallback when value is empty.
+func Pick(value, fallback string) string {
+	if value != "" {
+		return value
+	}
+	return fallback
+}
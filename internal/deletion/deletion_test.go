@@ -0,0 +1,34 @@
+package deletion
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestUnitDeletion_Pick(t *testing.T) {
+	if got := Pick("resource", "provider"); got != "resource" {
+		t.Fatalf("expected the resource value, got %q", got)
+	}
+	if got := Pick("", "provider"); got != "provider" {
+		t.Fatalf("expected the fallback, got %q", got)
+	}
+}
+
+func TestUnitDeletion_CheckProtection(t *testing.T) {
+	if diags := CheckProtection(types.BoolValue(false), "bucket", "bucket-1"); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if diags := CheckProtection(types.BoolNull(), "bucket", "bucket-1"); diags.HasError() {
+		t.Fatalf("expected state without the attribute to be unprotected, got %v", diags)
+	}
+
+	diags := CheckProtection(types.BoolValue(true), "bucket", "bucket-1")
+	if !diags.HasError() || diags[0].Summary() != "Deletion protection enabled" {
+		t.Fatalf("expected a deletion protection error, got %v", diags)
+	}
+	if !strings.Contains(diags[0].Detail(), "bucket bucket-1") {
+		t.Errorf("expected the detail to name the bucket, got %q", diags[0].Detail())
+	}
+}
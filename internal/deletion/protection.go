@@ -0,0 +1,45 @@
+package deletion
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// ProtectionAttribute returns the deletion_protection attribute of a resource
+// of the given kind, such as "server".
+func ProtectionAttribute(kind string) schema.BoolAttribute {
+	return schema.BoolAttribute{
+		Optional: true,
+		Computed: true,
+		Default:  booldefault.StaticBool(false),
+		MarkdownDescription: fmt.Sprintf(
+			"Whether destroying the %s fails. Set it to `false` in a separate apply before destroying the %s. Defaults to `false`.",
+			kind, kind,
+		),
+	}
+}
+
+// CheckProtection returns an error for Delete to stop on when protection is
+// enabled for the resource of the given kind and identifier. Terraform
+// destroys a resource with the protection recorded in its state, so a
+// configuration that disables it has to be applied first.
+func CheckProtection(protection types.Bool, kind, identifier string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if protection.ValueBool() {
+		diags.AddError(
+			"Deletion protection enabled",
+			fmt.Sprintf(
+				"The %s %s has deletion_protection enabled and was not destroyed. "+
+					"Set deletion_protection = false and apply that change before destroying it.",
+				kind, identifier,
+			),
+		)
+	}
+
+	return diags
+}
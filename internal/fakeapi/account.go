@@ -0,0 +1,439 @@
+package fakeapi
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/vpsie/govpsie"
+)
+
+type accessTokenService struct {
+	govpsie.AccessTokenService
+	b *Backend
+}
+
+func (s *accessTokenService) Create(ctx context.Context, name, accessToken, expirationDate string) error {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("AccessToken.Create"); err != nil {
+		return err
+	}
+
+	_, identifier := s.b.identifier("token")
+	s.b.accessTokens.put(identifier, &govpsie.AccessToken{
+		AccessTokenIdentifier: identifier,
+		Name:                  name,
+		CreatedOn:             now(),
+		ExpirationDate:        expirationDate,
+	})
+
+	return nil
+}
+
+func (s *accessTokenService) List(ctx context.Context, options *govpsie.ListOptions) ([]govpsie.AccessToken, error) {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("AccessToken.List"); err != nil {
+		return nil, err
+	}
+
+	tokens := []govpsie.AccessToken{}
+	s.b.accessTokens.each(func(id string, token *govpsie.AccessToken) {
+		tokens = append(tokens, *token)
+	})
+
+	return tokens, nil
+}
+
+func (s *accessTokenService) Update(ctx context.Context, accessTokenIdentifier, name, expirationDate string) error {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("AccessToken.Update"); err != nil {
+		return err
+	}
+
+	token, ok := s.b.accessTokens.get(accessTokenIdentifier)
+	if !ok {
+		return notFound("access token", accessTokenIdentifier)
+	}
+	token.Name = name
+	token.ExpirationDate = expirationDate
+
+	return nil
+}
+
+func (s *accessTokenService) Delete(ctx context.Context, accessTokenIdentifier string) error {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("AccessToken.Delete"); err != nil {
+		return err
+	}
+
+	if !s.b.accessTokens.delete(accessTokenIdentifier) {
+		return notFound("access token", accessTokenIdentifier)
+	}
+	return nil
+}
+
+type dataCenterService struct {
+	govpsie.DataCenterService
+	b *Backend
+}
+
+func (s *dataCenterService) List(ctx context.Context, options *govpsie.ListOptions) ([]govpsie.DataCenter, error) {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("DataCenter.List"); err != nil {
+		return nil, err
+	}
+
+	dataCenters := []govpsie.DataCenter{}
+	s.b.dataCenters.each(func(id string, dc *govpsie.DataCenter) {
+		dataCenters = append(dataCenters, *dc)
+	})
+
+	return dataCenters, nil
+}
+
+type projectService struct {
+	govpsie.ProjectsService
+	b *Backend
+}
+
+func (s *projectService) Create(ctx context.Context, projectReq *govpsie.CreateProjectRequest) error {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("Project.Create"); err != nil {
+		return err
+	}
+
+	id, identifier := s.b.identifier("project")
+	s.b.projects.put(identifier, &govpsie.Project{
+		ID:          uint64(id),
+		Name:        projectReq.Name,
+		Description: projectReq.Description,
+		CreatedOn:   now(),
+		UpdatedAt:   now(),
+		Identifier:  identifier,
+		CreatedBy:   1,
+	})
+
+	return nil
+}
+
+func (s *projectService) Get(ctx context.Context, identifier string) (*govpsie.Project, error) {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("Project.Get"); err != nil {
+		return nil, err
+	}
+
+	project, ok := s.b.projects.get(identifier)
+	if !ok {
+		return nil, notFound("project", identifier)
+	}
+
+	copied := *project
+	return &copied, nil
+}
+
+func (s *projectService) List(ctx context.Context, options *govpsie.ListOptions) ([]govpsie.Project, error) {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("Project.List"); err != nil {
+		return nil, err
+	}
+
+	projects := []govpsie.Project{}
+	s.b.projects.each(func(id string, project *govpsie.Project) {
+		projects = append(projects, *project)
+	})
+
+	return projects, nil
+}
+
+// Delete accepts either the project identifier or its numeric ID.
+func (s *projectService) Delete(ctx context.Context, id string) error {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("Project.Delete"); err != nil {
+		return err
+	}
+
+	var identifier string
+	s.b.projects.each(func(key string, project *govpsie.Project) {
+		if strconv.FormatUint(project.ID, 10) == id || project.Identifier == id {
+			identifier = key
+		}
+	})
+	if identifier == "" {
+		return notFound("project", id)
+	}
+
+	s.b.projects.delete(identifier)
+	return nil
+}
+
+type scriptService struct {
+	govpsie.ScriptsService
+	b *Backend
+}
+
+func (s *scriptService) CreateScript(ctx context.Context, createScriptRequest *govpsie.CreateScriptRequest) error {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("Scripts.CreateScript"); err != nil {
+		return err
+	}
+
+	id, identifier := s.b.identifier("script")
+	s.b.scripts.put(identifier, &govpsie.ScriptDetail{
+		ID:         int(id),
+		UserID:     1,
+		Name:       createScriptRequest.Name,
+		Script:     createScriptRequest.ScriptContent,
+		CreatedOn:  time.Now().UTC(),
+		Identifier: identifier,
+		ScriptName: createScriptRequest.Name,
+		Type:       createScriptRequest.ScriptType,
+	})
+
+	return nil
+}
+
+func (s *scriptService) GetScripts(ctx context.Context) ([]govpsie.Script, error) {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("Scripts.GetScripts"); err != nil {
+		return nil, err
+	}
+
+	scripts := []govpsie.Script{}
+	s.b.scripts.each(func(id string, script *govpsie.ScriptDetail) {
+		scripts = append(scripts, govpsie.Script{
+			UserID:        script.UserID,
+			BoxID:         script.BoxID,
+			BoxIdentifier: script.BoxIdentifier,
+			ScriptName:    script.ScriptName,
+			Script:        script.Script,
+			CreatedOn:     script.CreatedOn,
+			Identifier:    script.Identifier,
+		})
+	})
+
+	return scripts, nil
+}
+
+func (s *scriptService) GetScript(ctx context.Context, scriptId string) (govpsie.ScriptDetail, error) {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("Scripts.GetScript"); err != nil {
+		return govpsie.ScriptDetail{}, err
+	}
+
+	script, ok := s.b.scripts.get(scriptId)
+	if !ok {
+		return govpsie.ScriptDetail{}, notFound("script", scriptId)
+	}
+
+	return *script, nil
+}
+
+func (s *scriptService) UpdateScript(ctx context.Context, scriptUpdateRequest *govpsie.ScriptUpdateRequest) error {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("Scripts.UpdateScript"); err != nil {
+		return err
+	}
+
+	script, ok := s.b.scripts.get(scriptUpdateRequest.ScriptIdentifier)
+	if !ok {
+		return notFound("script", scriptUpdateRequest.ScriptIdentifier)
+	}
+	script.Name = scriptUpdateRequest.Name
+	script.ScriptName = scriptUpdateRequest.Name
+	script.Script = scriptUpdateRequest.ScriptContent
+	script.Type = scriptUpdateRequest.ScriptType
+
+	return nil
+}
+
+func (s *scriptService) DeleteScript(ctx context.Context, scriptId string) error {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("Scripts.DeleteScript"); err != nil {
+		return err
+	}
+
+	if !s.b.scripts.delete(scriptId) {
+		return notFound("script", scriptId)
+	}
+	return nil
+}
+
+type sshKeyService struct {
+	govpsie.SshkeysService
+	b *Backend
+}
+
+func (s *sshKeyService) Create(ctx context.Context, privateKey, name string) error {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("SShKey.Create"); err != nil {
+		return err
+	}
+
+	for _, key := range s.b.sshKeys.items {
+		if key.Name == name {
+			return Error(http.StatusConflict, "ssh key "+name+" already exists")
+		}
+	}
+
+	id, identifier := s.b.identifier("sshkey")
+	s.b.sshKeys.put(identifier, &govpsie.SShKey{
+		Id:         id,
+		UserId:     1,
+		Name:       name,
+		PrivateKey: privateKey,
+		CreatedOn:  now(),
+		Identifier: identifier,
+		CreatedBy:  "terraform",
+	})
+
+	return nil
+}
+
+func (s *sshKeyService) List(ctx context.Context) ([]govpsie.SShKey, error) {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("SShKey.List"); err != nil {
+		return nil, err
+	}
+
+	keys := []govpsie.SShKey{}
+	s.b.sshKeys.each(func(id string, key *govpsie.SShKey) {
+		keys = append(keys, *key)
+	})
+
+	return keys, nil
+}
+
+func (s *sshKeyService) Get(ctx context.Context, sshKeyIdentifier string) (*govpsie.SShKey, error) {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("SShKey.Get"); err != nil {
+		return nil, err
+	}
+
+	key, ok := s.b.sshKeys.get(sshKeyIdentifier)
+	if !ok {
+		return nil, notFound("ssh key", sshKeyIdentifier)
+	}
+
+	copied := *key
+	return &copied, nil
+}
+
+func (s *sshKeyService) Delete(ctx context.Context, sshKeyIdentifier string) error {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("SShKey.Delete"); err != nil {
+		return err
+	}
+
+	if !s.b.sshKeys.delete(sshKeyIdentifier) {
+		return notFound("ssh key", sshKeyIdentifier)
+	}
+	return nil
+}
+
+type monitoringService struct {
+	govpsie.MonitoringService
+	b *Backend
+}
+
+func (s *monitoringService) CreateRule(ctx context.Context, createReq *govpsie.CreateMonitoringRuleReq) error {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("Monitoring.CreateRule"); err != nil {
+		return err
+	}
+
+	threshold, _ := strconv.Atoi(createReq.Threshold)
+	period, _ := strconv.Atoi(createReq.Period)
+	frequency, _ := strconv.Atoi(createReq.Frequency)
+	status := 1
+	if createReq.Status == "0" || createReq.Status == "inactive" {
+		status = 0
+	}
+
+	id, identifier := s.b.identifier("rule")
+	s.b.monitoringRules.put(identifier, &govpsie.MonitoringRule{
+		ID:            int(id),
+		UserId:        1,
+		MetricType:    createReq.MetricType,
+		RuleName:      createReq.RuleName,
+		Condition:     createReq.Condition,
+		Email:         createReq.Actions.Email,
+		Threshold:     threshold,
+		ThresholdType: createReq.ThresholdType,
+		Period:        period,
+		Status:        status,
+		CreatedOn:     now(),
+		Frequency:     frequency,
+		Identifier:    identifier,
+		CreatedBY:     "terraform",
+	})
+
+	return nil
+}
+
+func (s *monitoringService) ListMonitoringRule(ctx context.Context, options *govpsie.ListOptions) ([]govpsie.MonitoringRule, error) {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("Monitoring.ListMonitoringRule"); err != nil {
+		return nil, err
+	}
+
+	rules := []govpsie.MonitoringRule{}
+	s.b.monitoringRules.each(func(id string, rule *govpsie.MonitoringRule) {
+		rules = append(rules, *rule)
+	})
+
+	return rules, nil
+}
+
+func (s *monitoringService) ToggleMonitoringRuleStatus(ctx context.Context, status, ruleIdentifier string) error {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("Monitoring.ToggleMonitoringRuleStatus"); err != nil {
+		return err
+	}
+
+	rule, ok := s.b.monitoringRules.get(ruleIdentifier)
+	if !ok {
+		return notFound("monitoring rule", ruleIdentifier)
+	}
+	if status == "0" || status == "inactive" {
+		rule.Status = 0
+	} else {
+		rule.Status = 1
+	}
+
+	return nil
+}
+
+func (s *monitoringService) DeleteMonitoringRule(ctx context.Context, ruleIdentifier string) error {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("Monitoring.DeleteMonitoringRule"); err != nil {
+		return err
+	}
+
+	if !s.b.monitoringRules.delete(ruleIdentifier) {
+		return notFound("monitoring rule", ruleIdentifier)
+	}
+	return nil
+}
@@ -0,0 +1,496 @@
+package fakeapi
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/vpsie/govpsie"
+)
+
+type backupService struct {
+	govpsie.BackupsService
+	b *Backend
+}
+
+func (s *backupService) CreateBackups(ctx context.Context, vmIdentifier, name, notes string) error {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("Backup.CreateBackups"); err != nil {
+		return err
+	}
+
+	server, err := s.b.server(vmIdentifier)
+	if err != nil {
+		return err
+	}
+
+	_, identifier := s.b.identifier("backup")
+	s.b.backups.put(identifier, &govpsie.Backup{
+		HostName:     server.Hostname,
+		Name:         name,
+		Identifier:   identifier,
+		Note:         notes,
+		State:        "completed",
+		DcIdentifier: server.DcIdentifier,
+		VMIdentifier: vmIdentifier,
+		BoxID:        int(server.ID),
+		OSFullName:   server.FullName,
+		VMCategory:   server.Category,
+		CreatedBy:    "terraform",
+		CreatedOn:    now(),
+	})
+
+	return nil
+}
+
+func (s *backupService) List(ctx context.Context, options *govpsie.ListOptions) ([]govpsie.Backup, error) {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("Backup.List"); err != nil {
+		return nil, err
+	}
+
+	backups := []govpsie.Backup{}
+	s.b.backups.each(func(id string, backup *govpsie.Backup) {
+		backups = append(backups, *backup)
+	})
+
+	return backups, nil
+}
+
+func (s *backupService) Get(ctx context.Context, identifier string) (*govpsie.Backup, error) {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("Backup.Get"); err != nil {
+		return nil, err
+	}
+
+	backup, ok := s.b.backups.get(identifier)
+	if !ok {
+		return nil, notFound("backup", identifier)
+	}
+
+	copied := *backup
+	return &copied, nil
+}
+
+func (s *backupService) Rename(ctx context.Context, backupIdentifier, newName string) error {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("Backup.Rename"); err != nil {
+		return err
+	}
+
+	backup, ok := s.b.backups.get(backupIdentifier)
+	if !ok {
+		return notFound("backup", backupIdentifier)
+	}
+	backup.Name = newName
+
+	return nil
+}
+
+func (s *backupService) DeleteBackup(ctx context.Context, backupIdentifier, deleteReason, deleteNote string) error {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("Backup.DeleteBackup"); err != nil {
+		return err
+	}
+
+	if !s.b.backups.delete(backupIdentifier) {
+		return notFound("backup", backupIdentifier)
+	}
+	return nil
+}
+
+func (s *backupService) CreateBackupPolicy(ctx context.Context, createReq *govpsie.CreateBackupPolicyReq) error {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("Backup.CreateBackupPolicy"); err != nil {
+		return err
+	}
+
+	planEvery, err := strconv.Atoi(createReq.PlanEvery)
+	if err != nil {
+		return Error(http.StatusBadRequest, "invalid planEvery "+createReq.PlanEvery)
+	}
+	keep, err := strconv.Atoi(createReq.Keep)
+	if err != nil {
+		return Error(http.StatusBadRequest, "invalid keep "+createReq.Keep)
+	}
+
+	_, identifier := s.b.identifier("backup-policy")
+	s.b.backupPolicies.put(identifier, &govpsie.BackupPolicy{
+		Name:       createReq.Name,
+		Identifier: identifier,
+		CreatedOn:  now(),
+		CreatedBy:  "terraform",
+		BackupPlan: createReq.BackupPlan,
+		PlanEvery:  planEvery,
+		Keep:       keep,
+		UserId:     1,
+		Vms:        mergeTags(nil, createReq.Vms),
+	})
+
+	return nil
+}
+
+func (s *backupService) GetBackupPolicy(ctx context.Context, identifier string) (*govpsie.BackupPolicy, error) {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("Backup.GetBackupPolicy"); err != nil {
+		return nil, err
+	}
+
+	policy, ok := s.b.backupPolicies.get(identifier)
+	if !ok {
+		return nil, notFound("backup policy", identifier)
+	}
+
+	copied := *policy
+	copied.Vms = sortedCopy(policy.Vms)
+	return &copied, nil
+}
+
+func (s *backupService) ListBackupPolicies(ctx context.Context, options *govpsie.ListOptions) ([]govpsie.BackupPolicyListDetail, error) {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("Backup.ListBackupPolicies"); err != nil {
+		return nil, err
+	}
+
+	policies := []govpsie.BackupPolicyListDetail{}
+	s.b.backupPolicies.each(func(id string, policy *govpsie.BackupPolicy) {
+		policies = append(policies, govpsie.BackupPolicyListDetail{
+			Name:       policy.Name,
+			Identifier: policy.Identifier,
+			CreatedOn:  policy.CreatedOn,
+			CreatedBy:  policy.CreatedBy,
+			BackupPlan: policy.BackupPlan,
+			PlanEvery:  policy.PlanEvery,
+			Keep:       policy.Keep,
+			Disabled:   policy.Disabled,
+			VmsCount:   len(policy.Vms),
+			UserId:     policy.UserId,
+		})
+	})
+
+	return policies, nil
+}
+
+func (s *backupService) AttachBackupPolicy(ctx context.Context, policyId string, vms []string) error {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("Backup.AttachBackupPolicy"); err != nil {
+		return err
+	}
+
+	policy, ok := s.b.backupPolicies.get(policyId)
+	if !ok {
+		return notFound("backup policy", policyId)
+	}
+	policy.Vms = mergeTags(policy.Vms, vms)
+
+	return nil
+}
+
+func (s *backupService) DetachBackupPolicy(ctx context.Context, policyId string, vms []string) error {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("Backup.DetachBackupPolicy"); err != nil {
+		return err
+	}
+
+	policy, ok := s.b.backupPolicies.get(policyId)
+	if !ok {
+		return notFound("backup policy", policyId)
+	}
+	policy.Vms = without(policy.Vms, vms)
+
+	return nil
+}
+
+func (s *backupService) DeleteBackupPolicy(ctx context.Context, policyId, identifier string) error {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("Backup.DeleteBackupPolicy"); err != nil {
+		return err
+	}
+
+	if !s.b.backupPolicies.delete(identifier) {
+		return notFound("backup policy", identifier)
+	}
+	return nil
+}
+
+type snapshotService struct {
+	govpsie.SnapshotService
+	b *Backend
+}
+
+func (s *snapshotService) Create(ctx context.Context, name, vmIdentifier, note string) error {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("Snapshot.Create"); err != nil {
+		return err
+	}
+
+	server, err := s.b.server(vmIdentifier)
+	if err != nil {
+		return err
+	}
+
+	_, identifier := s.b.identifier("snapshot")
+	s.b.snapshots.put(identifier, &govpsie.Snapshot{
+		Hostname:     server.Hostname,
+		Name:         name,
+		Identifier:   identifier,
+		State:        "completed",
+		DcIdentifier: server.DcIdentifier,
+		IsSnapshot:   1,
+		VmIdentifier: vmIdentifier,
+		CreatedOn:    time.Now().UTC(),
+		Note:         note,
+		BackupSize:   server.Ssd,
+		BoxID:        server.ID,
+		OsFullName:   server.FullName,
+		VMCategory:   server.Category,
+		VMSSD:        server.Ssd,
+	})
+
+	return nil
+}
+
+func (s *snapshotService) List(ctx context.Context, options *govpsie.ListOptions) ([]govpsie.Snapshot, error) {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("Snapshot.List"); err != nil {
+		return nil, err
+	}
+
+	snapshots := []govpsie.Snapshot{}
+	s.b.snapshots.each(func(id string, snapshot *govpsie.Snapshot) {
+		snapshots = append(snapshots, *snapshot)
+	})
+
+	return snapshots, nil
+}
+
+func (s *snapshotService) Get(ctx context.Context, snapshotIdentifier string) (*govpsie.Snapshot, error) {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("Snapshot.Get"); err != nil {
+		return nil, err
+	}
+
+	snapshot, ok := s.b.snapshots.get(snapshotIdentifier)
+	if !ok {
+		return nil, notFound("snapshot", snapshotIdentifier)
+	}
+
+	copied := *snapshot
+	return &copied, nil
+}
+
+func (s *snapshotService) Update(ctx context.Context, snapshotIdentifier, newNote string) error {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("Snapshot.Update"); err != nil {
+		return err
+	}
+
+	snapshot, ok := s.b.snapshots.get(snapshotIdentifier)
+	if !ok {
+		return notFound("snapshot", snapshotIdentifier)
+	}
+	snapshot.Note = newNote
+
+	return nil
+}
+
+func (s *snapshotService) Delete(ctx context.Context, snapshotIdentifier, reason, note string) error {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("Snapshot.Delete"); err != nil {
+		return err
+	}
+
+	if !s.b.snapshots.delete(snapshotIdentifier) {
+		return notFound("snapshot", snapshotIdentifier)
+	}
+	return nil
+}
+
+func (s *snapshotService) CreateSnapShotPolicy(ctx context.Context, createReq *govpsie.CreateSnapShotPolicyReq) error {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("Snapshot.CreateSnapShotPolicy"); err != nil {
+		return err
+	}
+
+	planEvery, err := strconv.ParseInt(createReq.PlanEvery, 10, 64)
+	if err != nil {
+		return Error(http.StatusBadRequest, "invalid planEvery "+createReq.PlanEvery)
+	}
+	keep, err := strconv.ParseInt(createReq.Keep, 10, 64)
+	if err != nil {
+		return Error(http.StatusBadRequest, "invalid keep "+createReq.Keep)
+	}
+
+	policy := &govpsie.SnapShotPolicy{
+		Name:       createReq.Name,
+		CreatedOn:  now(),
+		CreatedBy:  "terraform",
+		BackupPlan: createReq.BackupPlan,
+		PlanEvery:  planEvery,
+		Keep:       keep,
+		UserId:     1,
+	}
+	_, policy.Identifier = s.b.identifier("snapshot-policy")
+	policy.Vms = s.b.snapshotVms(nil, createReq.Vms)
+	s.b.snapshotPolices.put(policy.Identifier, policy)
+
+	return nil
+}
+
+func (s *snapshotService) GetSnapShotPolicy(ctx context.Context, identifier string) (*govpsie.SnapShotPolicy, error) {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("Snapshot.GetSnapShotPolicy"); err != nil {
+		return nil, err
+	}
+
+	policy, ok := s.b.snapshotPolices.get(identifier)
+	if !ok {
+		return nil, notFound("snapshot policy", identifier)
+	}
+
+	copied := *policy
+	copied.Vms = append([]govpsie.SnapShotVms{}, policy.Vms...)
+	return &copied, nil
+}
+
+func (s *snapshotService) ListSnapShotPolicies(ctx context.Context, options *govpsie.ListOptions) ([]govpsie.SnapShotPolicyListDetail, error) {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("Snapshot.ListSnapShotPolicies"); err != nil {
+		return nil, err
+	}
+
+	policies := []govpsie.SnapShotPolicyListDetail{}
+	s.b.snapshotPolices.each(func(id string, policy *govpsie.SnapShotPolicy) {
+		policies = append(policies, govpsie.SnapShotPolicyListDetail{
+			Name:       policy.Name,
+			Identifier: policy.Identifier,
+			CreatedOn:  policy.CreatedOn,
+			CreatedBy:  policy.CreatedBy,
+			BackupPlan: policy.BackupPlan,
+			PlanEvery:  policy.PlanEvery,
+			Keep:       policy.Keep,
+			Disabled:   policy.Disabled,
+			VmsCount:   int64(len(policy.Vms)),
+			UserId:     policy.UserId,
+		})
+	})
+
+	return policies, nil
+}
+
+func (s *snapshotService) AttachSnapShotPolicy(ctx context.Context, policyId string, vms []string) error {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("Snapshot.AttachSnapShotPolicy"); err != nil {
+		return err
+	}
+
+	policy, ok := s.b.snapshotPolices.get(policyId)
+	if !ok {
+		return notFound("snapshot policy", policyId)
+	}
+	policy.Vms = s.b.snapshotVms(policy.Vms, vms)
+
+	return nil
+}
+
+func (s *snapshotService) DetachSnapShotPolicy(ctx context.Context, policyId string, vms []string) error {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("Snapshot.DetachSnapShotPolicy"); err != nil {
+		return err
+	}
+
+	policy, ok := s.b.snapshotPolices.get(policyId)
+	if !ok {
+		return notFound("snapshot policy", policyId)
+	}
+
+	detach := map[string]bool{}
+	for _, vm := range vms {
+		detach[vm] = true
+	}
+	kept := []govpsie.SnapShotVms{}
+	for _, vm := range policy.Vms {
+		if !detach[vm.Identifier] {
+			kept = append(kept, vm)
+		}
+	}
+	policy.Vms = kept
+
+	return nil
+}
+
+func (s *snapshotService) DeleteSnapShotPolicy(ctx context.Context, policyId, identifier string) error {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("Snapshot.DeleteSnapShotPolicy"); err != nil {
+		return err
+	}
+
+	if !s.b.snapshotPolices.delete(identifier) {
+		return notFound("snapshot policy", identifier)
+	}
+	return nil
+}
+
+// snapshotVms adds the servers in vms to have, skipping ones already there.
+func (b *Backend) snapshotVms(have []govpsie.SnapShotVms, vms []string) []govpsie.SnapShotVms {
+	out := append([]govpsie.SnapShotVms{}, have...)
+	for _, identifier := range vms {
+		exists := false
+		for _, vm := range out {
+			if vm.Identifier == identifier {
+				exists = true
+			}
+		}
+		if exists {
+			continue
+		}
+
+		vm := govpsie.SnapShotVms{Identifier: identifier, Type: "vm"}
+		if server, ok := b.servers.get(identifier); ok {
+			vm.Name = server.Hostname
+			vm.Category = server.Category
+			vm.Fullname = server.FullName
+		}
+		out = append(out, vm)
+	}
+	return out
+}
+
+// without returns values with every entry in remove taken out.
+func without(values, remove []string) []string {
+	drop := map[string]bool{}
+	for _, value := range remove {
+		drop[value] = true
+	}
+
+	out := []string{}
+	for _, value := range values {
+		if !drop[value] {
+			out = append(out, value)
+		}
+	}
+	return out
+}
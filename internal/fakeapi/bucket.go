@@ -0,0 +1,107 @@
+package fakeapi
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/vpsie/govpsie"
+)
+
+type bucketService struct {
+	govpsie.BucketService
+	b *Backend
+}
+
+func (s *bucketService) Create(ctx context.Context, createReq *govpsie.CreateBucketReq) error {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("Bucket.Create"); err != nil {
+		return err
+	}
+
+	for _, bucket := range s.b.buckets.items {
+		if bucket.BucketName == createReq.BucketName {
+			return Error(http.StatusConflict, "bucket "+createReq.BucketName+" already exists")
+		}
+	}
+
+	id, identifier := s.b.identifier("bucket")
+	s.b.buckets.put(identifier, &govpsie.Bucket{
+		ID:          int(id),
+		UserId:      1,
+		AccessKey:   "fake-access-key-" + identifier,
+		SecretKey:   "fake-secret-key-" + identifier,
+		BucketName:  createReq.BucketName,
+		ProjectName: createReq.ProjectId,
+		CreatedBy:   "terraform",
+		EndPoint:    "https://s3.example.com",
+		CreatedOn:   now(),
+		Identifier:  identifier,
+		State:       "active",
+		Country:     "Nowhere",
+	})
+	s.b.bucketListing[identifier] = createReq.FileListing
+
+	return nil
+}
+
+func (s *bucketService) Get(ctx context.Context, id string) (*govpsie.Bucket, error) {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("Bucket.Get"); err != nil {
+		return nil, err
+	}
+
+	bucket, ok := s.b.buckets.get(id)
+	if !ok {
+		return nil, notFound("bucket", id)
+	}
+
+	copied := *bucket
+	return &copied, nil
+}
+
+func (s *bucketService) List(ctx context.Context, options *govpsie.ListOptions) ([]govpsie.Bucket, error) {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("Bucket.List"); err != nil {
+		return nil, err
+	}
+
+	buckets := []govpsie.Bucket{}
+	s.b.buckets.each(func(id string, bucket *govpsie.Bucket) {
+		buckets = append(buckets, *bucket)
+	})
+
+	return buckets, nil
+}
+
+func (s *bucketService) Delete(ctx context.Context, buckId, reason, note string) error {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("Bucket.Delete"); err != nil {
+		return err
+	}
+
+	if !s.b.buckets.delete(buckId) {
+		return notFound("bucket", buckId)
+	}
+	delete(s.b.bucketListing, buckId)
+
+	return nil
+}
+
+func (s *bucketService) ToggleFileListing(ctx context.Context, bucketId string, fileListing bool) (bool, error) {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("Bucket.ToggleFileListing"); err != nil {
+		return false, err
+	}
+
+	if _, ok := s.b.buckets.get(bucketId); !ok {
+		return false, notFound("bucket", bucketId)
+	}
+	s.b.bucketListing[bucketId] = fileListing
+
+	return fileListing, nil
+}
@@ -0,0 +1,673 @@
+package fakeapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/vpsie/govpsie"
+)
+
+const (
+	nodeTypeMaster = 1
+	nodeTypeSlave  = 2
+)
+
+type imageService struct {
+	govpsie.ImagesService
+	b *Backend
+}
+
+func (s *imageService) CreateImages(ctx context.Context, dcIdentifier, imageName, imageUrl string) error {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("Image.CreateImages"); err != nil {
+		return err
+	}
+
+	id, identifier := s.b.identifier("image")
+	s.b.images.put(identifier, &govpsie.CustomImage{
+		ID:             int(id),
+		UserID:         1,
+		DatacenterID:   1,
+		ImageSize:      1 << 30,
+		OriginalName:   imageName,
+		FetchedFromURL: imageUrl,
+		ChangedName:    imageName,
+		ImageLabel:     imageName,
+		CreatedOn:      time.Now().UTC(),
+		Identifier:     identifier,
+		Storage:        "local",
+		DcName:         "Fake DC",
+		DcIdentifier:   dcIdentifier,
+		CreatedBy:      "terraform",
+	})
+	s.b.provision(identifier)
+
+	return nil
+}
+
+func (s *imageService) GetImage(ctx context.Context, imageIdentifier string) (*govpsie.CustomImage, error) {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("Image.GetImage"); err != nil {
+		return nil, err
+	}
+
+	image, ok := s.b.images.get(imageIdentifier)
+	if !ok || s.b.hidden(imageIdentifier) {
+		return nil, notFound("image", imageIdentifier)
+	}
+
+	copied := *image
+	return &copied, nil
+}
+
+func (s *imageService) List(ctx context.Context, options *govpsie.ListOptions) ([]govpsie.CustomImage, error) {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("Image.List"); err != nil {
+		return nil, err
+	}
+
+	images := []govpsie.CustomImage{}
+	s.b.images.each(func(id string, image *govpsie.CustomImage) {
+		if !s.b.hidden(id) {
+			images = append(images, *image)
+		}
+	})
+
+	return images, nil
+}
+
+func (s *imageService) DeleteImage(ctx context.Context, imageIdentifier string) error {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("Image.DeleteImage"); err != nil {
+		return err
+	}
+
+	if !s.b.images.delete(imageIdentifier) {
+		return notFound("image", imageIdentifier)
+	}
+	return nil
+}
+
+type kubernetesService struct {
+	govpsie.K8sService
+	b *Backend
+}
+
+func (s *kubernetesService) Create(ctx context.Context, createReq *govpsie.CreateK8sReq) error {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("K8s.Create"); err != nil {
+		return err
+	}
+
+	_, identifier := s.b.identifier("k8s")
+	cluster := &govpsie.K8s{
+		ClusterName: createReq.ClusterName,
+		Identifier:  identifier,
+		CreatedOn:   now(),
+		UpdatedOn:   now(),
+		CreatedBy:   "terraform",
+		NickName:    createReq.ClusterName,
+		Cpu:         2,
+		Ram:         4096,
+		Traffic:     1000,
+	}
+	for i := 0; i < createReq.NodesCountMaster; i++ {
+		s.b.addNode(cluster, nodeTypeMaster)
+	}
+	for i := 0; i < createReq.NodesCountSlave; i++ {
+		s.b.addNode(cluster, nodeTypeSlave)
+	}
+	s.b.clusters.put(identifier, cluster)
+	s.b.provision(identifier)
+
+	return nil
+}
+
+func (s *kubernetesService) List(ctx context.Context, options *govpsie.ListOptions) ([]govpsie.ListK8s, error) {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("K8s.List"); err != nil {
+		return nil, err
+	}
+
+	clusters := []govpsie.ListK8s{}
+	s.b.clusters.each(func(id string, cluster *govpsie.K8s) {
+		if s.b.hidden(id) {
+			return
+		}
+		clusters = append(clusters, govpsie.ListK8s{
+			ClusterName:  cluster.ClusterName,
+			Identifier:   cluster.Identifier,
+			Count:        cluster.Count,
+			CreatedOn:    cluster.CreatedOn,
+			UpdatedOn:    cluster.UpdatedOn,
+			CreatedBy:    cluster.CreatedBy,
+			NickName:     cluster.NickName,
+			Cpu:          cluster.Cpu,
+			Ram:          cluster.Ram,
+			Traffic:      cluster.Traffic,
+			Color:        cluster.Color,
+			Price:        cluster.Price,
+			ManagerCount: countNodes(cluster, nodeTypeMaster),
+			SlaveCount:   countNodes(cluster, nodeTypeSlave),
+		})
+	})
+
+	return clusters, nil
+}
+
+func (s *kubernetesService) Get(ctx context.Context, identifier string) (*govpsie.K8s, error) {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("K8s.Get"); err != nil {
+		return nil, err
+	}
+
+	cluster, err := s.b.cluster(identifier)
+	if err != nil {
+		return nil, err
+	}
+	if s.b.hidden(identifier) {
+		return nil, notFound("kubernetes cluster", identifier)
+	}
+
+	copied := *cluster
+	copied.Nodes = append([]govpsie.Node{}, cluster.Nodes...)
+	return &copied, nil
+}
+
+func (s *kubernetesService) Delete(ctx context.Context, identifier, reason, note string) error {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("K8s.Delete"); err != nil {
+		return err
+	}
+
+	if !s.b.clusters.delete(identifier) {
+		return notFound("kubernetes cluster", identifier)
+	}
+	for group, cluster := range s.b.groupClusters {
+		if cluster == identifier {
+			s.b.clusterGroups.delete(group)
+			delete(s.b.groupClusters, group)
+		}
+	}
+
+	return nil
+}
+
+func (s *kubernetesService) AddSlave(ctx context.Context, identifier string) error {
+	return s.update("K8s.AddSlave", identifier, func(cluster *govpsie.K8s) error {
+		s.b.addNode(cluster, nodeTypeSlave)
+		return nil
+	})
+}
+
+func (s *kubernetesService) RemoveSlave(ctx context.Context, identifier string) error {
+	return s.update("K8s.RemoveSlave", identifier, func(cluster *govpsie.K8s) error {
+		return removeNode(cluster, nodeTypeSlave)
+	})
+}
+
+func (s *kubernetesService) ListK8sGroups(ctx context.Context, identifier string) ([]govpsie.K8sGroup, error) {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("K8s.ListK8sGroups"); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.b.cluster(identifier); err != nil {
+		return nil, err
+	}
+
+	groups := []govpsie.K8sGroup{}
+	s.b.clusterGroups.each(func(id string, group *govpsie.K8sGroup) {
+		if s.b.groupClusters[id] == identifier {
+			groups = append(groups, *group)
+		}
+	})
+
+	return groups, nil
+}
+
+func (s *kubernetesService) CreateK8sGroup(ctx context.Context, createReq *govpsie.CreateK8sGroupReq) error {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("K8s.CreateK8sGroup"); err != nil {
+		return err
+	}
+
+	if _, err := s.b.cluster(createReq.ClusterIdentifier); err != nil {
+		return err
+	}
+
+	id, identifier := s.b.identifier("k8s-group")
+	s.b.clusterGroups.put(identifier, &govpsie.K8sGroup{
+		ID:           id,
+		GroupName:    createReq.GroupName,
+		UserID:       1,
+		BoxsizeID:    int64(createReq.KubeSizeID),
+		DatacenterID: 1,
+		RAM:          2048,
+		CPU:          1,
+		Ssd:          40,
+		Traffic:      1000,
+		CreatedOn:    time.Now().UTC(),
+		LastUpdated:  time.Now().UTC(),
+		IsActive:     1,
+		Identifier:   identifier,
+		ClusterID:    id,
+		DcIdentifier: "dc-1",
+	})
+	s.b.groupClusters[identifier] = createReq.ClusterIdentifier
+
+	return nil
+}
+
+func (s *kubernetesService) DeleteK8sGroup(ctx context.Context, groupId string, reason, note string) error {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("K8s.DeleteK8sGroup"); err != nil {
+		return err
+	}
+
+	if !s.b.clusterGroups.delete(groupId) {
+		return notFound("kubernetes group", groupId)
+	}
+	delete(s.b.groupClusters, groupId)
+
+	return nil
+}
+
+func (s *kubernetesService) AddNode(ctx context.Context, identifier, nodeType string, groupId int) error {
+	return s.updateGroup("K8s.AddNode", identifier, groupId, func(group *govpsie.K8sGroup) error {
+		group.NodesCount++
+		return nil
+	})
+}
+
+func (s *kubernetesService) RemoveNode(ctx context.Context, identifier, nodeType string, groupId int) error {
+	return s.updateGroup("K8s.RemoveNode", identifier, groupId, func(group *govpsie.K8sGroup) error {
+		if group.NodesCount == 0 {
+			return Error(http.StatusBadRequest, "kubernetes group has no nodes to remove")
+		}
+		group.NodesCount--
+		return nil
+	})
+}
+
+func (s *kubernetesService) update(method, identifier string, fn func(cluster *govpsie.K8s) error) error {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call(method); err != nil {
+		return err
+	}
+
+	cluster, err := s.b.cluster(identifier)
+	if err != nil {
+		return err
+	}
+	if err := fn(cluster); err != nil {
+		return err
+	}
+	cluster.UpdatedOn = now()
+
+	return nil
+}
+
+func (s *kubernetesService) updateGroup(method, identifier string, groupId int, fn func(group *govpsie.K8sGroup) error) error {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call(method); err != nil {
+		return err
+	}
+
+	if _, err := s.b.cluster(identifier); err != nil {
+		return err
+	}
+
+	var found *govpsie.K8sGroup
+	s.b.clusterGroups.each(func(id string, group *govpsie.K8sGroup) {
+		if group.ID == int64(groupId) && s.b.groupClusters[id] == identifier {
+			found = group
+		}
+	})
+	if found == nil {
+		return notFound("kubernetes group", strconv.Itoa(groupId))
+	}
+	if err := fn(found); err != nil {
+		return err
+	}
+	found.LastUpdated = time.Now().UTC()
+
+	return nil
+}
+
+func (b *Backend) cluster(identifier string) (*govpsie.K8s, error) {
+	cluster, ok := b.clusters.get(identifier)
+	if !ok {
+		return nil, notFound("kubernetes cluster", identifier)
+	}
+	return cluster, nil
+}
+
+func (b *Backend) addNode(cluster *govpsie.K8s, nodeType int) {
+	id := b.id()
+	cluster.Nodes = append(cluster.Nodes, govpsie.Node{
+		Id:           int(id),
+		UserId:       1,
+		HostName:     fmt.Sprintf("%s-node-%d", cluster.ClusterName, id),
+		DefaultIP:    fmt.Sprintf("192.0.2.%d", id%250+1),
+		PrivateIP:    fmt.Sprintf("10.0.2.%d", id%250+1),
+		NodeType:     nodeType,
+		NodeId:       int(id),
+		DatacenterId: 1,
+		CreatedOn:    now(),
+	})
+	cluster.Count = countNodes(cluster, nodeTypeMaster)
+}
+
+func removeNode(cluster *govpsie.K8s, nodeType int) error {
+	for i := len(cluster.Nodes) - 1; i >= 0; i-- {
+		if cluster.Nodes[i].NodeType == nodeType {
+			cluster.Nodes = append(cluster.Nodes[:i], cluster.Nodes[i+1:]...)
+			cluster.Count = countNodes(cluster, nodeTypeMaster)
+			return nil
+		}
+	}
+	return Error(http.StatusBadRequest, "kubernetes cluster has no node to remove")
+}
+
+func countNodes(cluster *govpsie.K8s, nodeType int) int {
+	n := 0
+	for _, node := range cluster.Nodes {
+		if node.NodeType == nodeType {
+			n++
+		}
+	}
+	return n
+}
+
+type loadbalancerService struct {
+	govpsie.LBsService
+	b *Backend
+}
+
+func (s *loadbalancerService) CreateLB(ctx context.Context, createLBReq *govpsie.CreateLBReq) error {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("LB.CreateLB"); err != nil {
+		return err
+	}
+
+	id, identifier := s.b.identifier("lb")
+	lb := &govpsie.LBDetails{
+		LBName:     createLBReq.LBName,
+		Identifier: identifier,
+		Traffic:    1000,
+		BoxsizeID:  1,
+		DefaultIP:  fmt.Sprintf("192.0.2.%d", id%250+1),
+		DcName:     "Fake DC",
+		DcID:       createLBReq.DcIdentifier,
+		CreatedBy:  "terraform",
+		UserID:     1,
+		Rules:      []govpsie.LBRuleDetail{},
+	}
+	for _, rule := range createLBReq.Rule {
+		lbRule, err := s.b.lbRule(rule.Scheme, rule.FrontPort, rule.BackPort, rule.Domains, rule.Backends)
+		if err != nil {
+			return err
+		}
+		lb.Rules = append(lb.Rules, lbRule)
+	}
+	s.b.lbs.put(identifier, lb)
+	s.b.provision(identifier)
+
+	return nil
+}
+
+func (s *loadbalancerService) ListLBs(ctx context.Context, options *govpsie.ListOptions) ([]govpsie.LB, error) {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("LB.ListLBs"); err != nil {
+		return nil, err
+	}
+
+	lbs := []govpsie.LB{}
+	s.b.lbs.each(func(id string, lb *govpsie.LBDetails) {
+		if s.b.hidden(id) {
+			return
+		}
+		lbs = append(lbs, govpsie.LB{
+			Cpu:        1,
+			Ssd:        20,
+			Ram:        1024,
+			LBName:     lb.LBName,
+			Traffic:    lb.Traffic,
+			BoxsizeID:  lb.BoxsizeID,
+			DefaultIP:  lb.DefaultIP,
+			DCName:     lb.DcName,
+			Identifier: lb.Identifier,
+			CreatedOn:  now(),
+			UpdatedAt:  now(),
+			CreatedBy:  lb.CreatedBy,
+			UserID:     lb.UserID,
+		})
+	})
+
+	return lbs, nil
+}
+
+func (s *loadbalancerService) GetLB(ctx context.Context, lbID string) (*govpsie.LBDetails, error) {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("LB.GetLB"); err != nil {
+		return nil, err
+	}
+
+	lb, ok := s.b.lbs.get(lbID)
+	if !ok || s.b.hidden(lbID) {
+		return nil, notFound("load balancer", lbID)
+	}
+
+	copied := copyLB(lb)
+	return &copied, nil
+}
+
+func (s *loadbalancerService) DeleteLB(ctx context.Context, lbID, reason, note string) error {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("LB.DeleteLB"); err != nil {
+		return err
+	}
+
+	if !s.b.lbs.delete(lbID) {
+		return notFound("load balancer", lbID)
+	}
+	return nil
+}
+
+func (s *loadbalancerService) AddLBRule(ctx context.Context, addRuleReq *govpsie.AddRuleReq) error {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("LB.AddLBRule"); err != nil {
+		return err
+	}
+
+	lb, ok := s.b.lbs.get(addRuleReq.LbId)
+	if !ok {
+		return notFound("load balancer", addRuleReq.LbId)
+	}
+	rule, err := s.b.lbRule(addRuleReq.Scheme, addRuleReq.FrontPort, addRuleReq.BackPort, addRuleReq.Domains, nil)
+	if err != nil {
+		return err
+	}
+	lb.Rules = append(lb.Rules, rule)
+
+	return nil
+}
+
+func (s *loadbalancerService) DeleteLBRule(ctx context.Context, ruleID string) error {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("LB.DeleteLBRule"); err != nil {
+		return err
+	}
+
+	for _, lb := range s.b.lbs.items {
+		for i, rule := range lb.Rules {
+			if rule.RuleID == ruleID {
+				lb.Rules = append(lb.Rules[:i], lb.Rules[i+1:]...)
+				return nil
+			}
+		}
+	}
+
+	return notFound("load balancer rule", ruleID)
+}
+
+func (s *loadbalancerService) UpdateLBDomain(ctx context.Context, domainUpdateReq *govpsie.DomainUpdateReq) error {
+	return s.updateDomain("LB.UpdateLBDomain", domainUpdateReq.DomainID, func(domain *govpsie.LBDomainsDetail) {
+		subdomain := domainUpdateReq.Subdomain
+		domain.Subdomain = &subdomain
+		domain.Algorithm = domainUpdateReq.Algorithm
+		domain.RedirectHTTP = domainUpdateReq.RedirectHTTP
+		domain.CookieCheck = 0
+		if domainUpdateReq.CookieCheck {
+			domain.CookieCheck = 1
+		}
+		domain.CookieName = domainUpdateReq.CookieName
+		domain.BackPort = domainUpdateReq.BackPort
+		domain.CheckInterval = domainUpdateReq.CheckInterval
+		domain.FastInterval = domainUpdateReq.FastInterval
+		domain.Rise = domainUpdateReq.Rise
+		domain.Fall = domainUpdateReq.Fall
+	})
+}
+
+func (s *loadbalancerService) UpdateDomainBackend(ctx context.Context, domainId string, backends []govpsie.Backend) error {
+	return s.updateDomain("LB.UpdateDomainBackend", domainId, func(domain *govpsie.LBDomainsDetail) {
+		domain.Backends = s.b.lbBackends(backends)
+	})
+}
+
+func (s *loadbalancerService) UpdateLBRules(ctx context.Context, ruleUpdateReq *govpsie.RuleUpdateReq) error {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("LB.UpdateLBRules"); err != nil {
+		return err
+	}
+
+	for _, lb := range s.b.lbs.items {
+		for i := range lb.Rules {
+			rule := &lb.Rules[i]
+			if rule.RuleID != ruleUpdateReq.RuleID {
+				continue
+			}
+			rule.Scheme = ruleUpdateReq.Scheme
+			rule.FrontPort = ruleUpdateReq.FrontPort
+			rule.BackPort = ruleUpdateReq.BackPort
+			rule.Backends = s.b.lbBackends(ruleUpdateReq.Backends)
+			return nil
+		}
+	}
+
+	return notFound("load balancer rule", ruleUpdateReq.RuleID)
+}
+
+func (s *loadbalancerService) updateDomain(method, domainID string, fn func(domain *govpsie.LBDomainsDetail)) error {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call(method); err != nil {
+		return err
+	}
+
+	for _, lb := range s.b.lbs.items {
+		for i := range lb.Rules {
+			for j := range lb.Rules[i].Domains {
+				if lb.Rules[i].Domains[j].DomainID == domainID {
+					fn(&lb.Rules[i].Domains[j])
+					return nil
+				}
+			}
+		}
+	}
+
+	return notFound("load balancer domain", domainID)
+}
+
+func (b *Backend) lbRule(scheme, frontPort, backPort string, domains []govpsie.LBDomain, backends []govpsie.Backend) (govpsie.LBRuleDetail, error) {
+	front, err := strconv.Atoi(frontPort)
+	if err != nil {
+		return govpsie.LBRuleDetail{}, Error(http.StatusBadRequest, "invalid frontPort "+frontPort)
+	}
+	back, err := strconv.Atoi(backPort)
+	if err != nil {
+		return govpsie.LBRuleDetail{}, Error(http.StatusBadRequest, "invalid backPort "+backPort)
+	}
+
+	_, ruleID := b.identifier("lb-rule")
+	rule := govpsie.LBRuleDetail{
+		Scheme:    scheme,
+		FrontPort: front,
+		BackPort:  back,
+		CreatedOn: time.Now().UTC(),
+		RuleID:    ruleID,
+		Backends:  b.lbBackends(backends),
+	}
+	for _, domain := range domains {
+		domainBackPort, _ := strconv.Atoi(domain.BackPort)
+		_, domainID := b.identifier("lb-domain")
+		rule.Domains = append(rule.Domains, govpsie.LBDomainsDetail{
+			DomainName:    domain.DomainName,
+			BackendScheme: domain.BackendScheme,
+			Algorithm:     "roundrobin",
+			CreatedOn:     time.Now().UTC(),
+			BackPort:      domainBackPort,
+			DomainID:      domainID,
+			Backends:      b.lbBackends(domain.Backends),
+		})
+	}
+
+	return rule, nil
+}
+
+func (b *Backend) lbBackends(backends []govpsie.Backend) []govpsie.LBBackendsDetail {
+	out := []govpsie.LBBackendsDetail{}
+	for _, backend := range backends {
+		_, identifier := b.identifier("lb-backend")
+		out = append(out, govpsie.LBBackendsDetail{
+			IP:           backend.Ip,
+			Identifier:   identifier,
+			VMIdentifier: backend.VmIdentifier,
+			CreatedOn:    time.Now().UTC(),
+		})
+	}
+	return out
+}
+
+func copyLB(lb *govpsie.LBDetails) govpsie.LBDetails {
+	copied := *lb
+	copied.Rules = make([]govpsie.LBRuleDetail, len(lb.Rules))
+	for i, rule := range lb.Rules {
+		rule.Backends = append([]govpsie.LBBackendsDetail{}, rule.Backends...)
+		domains := make([]govpsie.LBDomainsDetail, len(rule.Domains))
+		for j, domain := range rule.Domains {
+			domain.Backends = append([]govpsie.LBBackendsDetail{}, domain.Backends...)
+			domains[j] = domain
+		}
+		if rule.Domains != nil {
+			rule.Domains = domains
+		}
+		copied.Rules[i] = rule
+	}
+	return copied
+}
@@ -0,0 +1,207 @@
+package fakeapi
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/vpsie/govpsie"
+)
+
+type domainService struct {
+	govpsie.DomainService
+	b *Backend
+}
+
+func (s *domainService) CreateDomain(ctx context.Context, createReq *govpsie.CreateDomainRequest) error {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("Domain.CreateDomain"); err != nil {
+		return err
+	}
+
+	for _, domain := range s.b.domains.items {
+		if domain.DomainName == createReq.Domain {
+			return Error(http.StatusConflict, "domain "+createReq.Domain+" already exists")
+		}
+	}
+
+	_, identifier := s.b.identifier("domain")
+	s.b.domains.put(identifier, &govpsie.Domain{
+		DomainName: createReq.Domain,
+		Identifier: identifier,
+		CreatedOn:  now(),
+		LastCheck:  now(),
+	})
+
+	return nil
+}
+
+func (s *domainService) ListDomains(ctx context.Context, options *govpsie.ListOptions) ([]govpsie.Domain, error) {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("Domain.ListDomains"); err != nil {
+		return nil, err
+	}
+
+	domains := []govpsie.Domain{}
+	s.b.domains.each(func(id string, domain *govpsie.Domain) {
+		domains = append(domains, *domain)
+	})
+
+	return domains, nil
+}
+
+func (s *domainService) DeleteDomain(ctx context.Context, domainIdentifier, reason, note string) error {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("Domain.DeleteDomain"); err != nil {
+		return err
+	}
+
+	if !s.b.domains.delete(domainIdentifier) {
+		return notFound("domain", domainIdentifier)
+	}
+	delete(s.b.dnsRecords, domainIdentifier)
+
+	return nil
+}
+
+func (s *domainService) CreateDnsRecord(ctx context.Context, createReq govpsie.CreateDnsRecordReq) error {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("Domain.CreateDnsRecord"); err != nil {
+		return err
+	}
+
+	if _, ok := s.b.domains.get(createReq.DomainIdentifier); !ok {
+		return notFound("domain", createReq.DomainIdentifier)
+	}
+	if s.b.recordIndex(createReq.DomainIdentifier, createReq.Record) >= 0 {
+		return Error(http.StatusConflict, "dns record already exists")
+	}
+
+	s.b.dnsRecords[createReq.DomainIdentifier] = append(s.b.dnsRecords[createReq.DomainIdentifier], createReq.Record)
+	return nil
+}
+
+func (s *domainService) UpdateDnsRecord(ctx context.Context, updateReq *govpsie.UpdateDnsRecordReq) error {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("Domain.UpdateDnsRecord"); err != nil {
+		return err
+	}
+
+	i := s.b.recordIndex(updateReq.DomainIdentifier, updateReq.Current)
+	if i < 0 {
+		return notFound("dns record", updateReq.Current.Type+" "+updateReq.Current.Name)
+	}
+
+	s.b.dnsRecords[updateReq.DomainIdentifier][i] = updateReq.New
+	return nil
+}
+
+func (s *domainService) DeleteDnsRecord(ctx context.Context, domainIdentifier string, record *govpsie.Record) error {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("Domain.DeleteDnsRecord"); err != nil {
+		return err
+	}
+
+	i := s.b.recordIndex(domainIdentifier, *record)
+	if i < 0 {
+		return notFound("dns record", record.Type+" "+record.Name)
+	}
+
+	records := s.b.dnsRecords[domainIdentifier]
+	s.b.dnsRecords[domainIdentifier] = append(records[:i], records[i+1:]...)
+	return nil
+}
+
+func (s *domainService) AddReverse(ctx context.Context, reverseReq *govpsie.ReverseRequest) error {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("Domain.AddReverse"); err != nil {
+		return err
+	}
+
+	if _, err := s.b.server(reverseReq.VmIdentifier); err != nil {
+		return err
+	}
+
+	s.b.reverse.put(reverseReq.Ip, &govpsie.ReversePTR{
+		Ip:           reverseReq.Ip,
+		HostName:     reverseReq.HostName,
+		VmIdentifier: reverseReq.VmIdentifier,
+		PtrRecord:    reverseReq.HostName,
+	})
+
+	return nil
+}
+
+func (s *domainService) ListReversePTRRecords(ctx context.Context) ([]govpsie.ReversePTR, error) {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("Domain.ListReversePTRRecords"); err != nil {
+		return nil, err
+	}
+
+	records := []govpsie.ReversePTR{}
+	s.b.reverse.each(func(id string, record *govpsie.ReversePTR) {
+		records = append(records, *record)
+	})
+
+	return records, nil
+}
+
+func (s *domainService) UpdateReverse(ctx context.Context, reverseReq *govpsie.ReverseRequest) error {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("Domain.UpdateReverse"); err != nil {
+		return err
+	}
+
+	record, ok := s.b.reverse.get(reverseReq.Ip)
+	if !ok || record.VmIdentifier != reverseReq.VmIdentifier {
+		return notFound("reverse record", reverseReq.Ip)
+	}
+	record.HostName = reverseReq.HostName
+	record.PtrRecord = reverseReq.HostName
+
+	return nil
+}
+
+func (s *domainService) DeleteReverse(ctx context.Context, ip, vmIdentifier string) error {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("Domain.DeleteReverse"); err != nil {
+		return err
+	}
+
+	record, ok := s.b.reverse.get(ip)
+	if !ok || record.VmIdentifier != vmIdentifier {
+		return notFound("reverse record", ip)
+	}
+
+	s.b.reverse.delete(ip)
+	return nil
+}
+
+// DNSRecords returns the records of a domain. The API has no call to read
+// them back, so tests use this to check what the provider sent.
+func (b *Backend) DNSRecords(domainIdentifier string) []govpsie.Record {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return append([]govpsie.Record{}, b.dnsRecords[domainIdentifier]...)
+}
+
+// recordIndex finds a record by type and name, which is how the API tells
+// records apart.
+func (b *Backend) recordIndex(domainIdentifier string, record govpsie.Record) int {
+	for i, existing := range b.dnsRecords[domainIdentifier] {
+		if existing.Type == record.Type && existing.Name == record.Name {
+			return i
+		}
+	}
+	return -1
+}
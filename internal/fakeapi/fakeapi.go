@@ -0,0 +1,258 @@
+// Package fakeapi is an in-memory VPSie API for tests.
+//
+// Backend.Client returns a govpsie client whose services are answered from
+// memory, so resources can be created, read, updated, imported and
+// destroyed in a plain go test. The backend is stateful: deleted objects are
+// not found afterwards, new objects can be kept out of reads for a few polls
+// to simulate asynchronous provisioning, and any call can be made to fail.
+// Calls the fake does not implement fail instead of reaching the network.
+package fakeapi
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/vpsie/govpsie"
+)
+
+// Backend holds the state of the fake API.
+type Backend struct {
+	// ProvisioningPolls is the number of reads a new server, image, load
+	// balancer or kubernetes cluster stays hidden for after it is created.
+	// A server that is rebuilt, resized, started or stopped reports a
+	// transitional state, such as "rebuilding", for as many reads.
+	ProvisioningPolls int
+
+	// QueuedPolls is the number of reads a server that is rebuilt, resized,
+	// started or stopped still reads exactly as it did before the request,
+	// as the API does until it picks the request up.
+	QueuedPolls int
+
+	mu       sync.Mutex
+	nextID   int64
+	calls    map[string]int
+	failures map[string][]error
+	pending  map[string]int
+	// transitions maps a server to the transitional state it reports.
+	transitions map[string]*serverTransition
+
+	accessTokens    store[govpsie.AccessToken]
+	backups         store[govpsie.Backup]
+	backupPolicies  store[govpsie.BackupPolicy]
+	buckets         store[govpsie.Bucket]
+	bucketListing   map[string]bool
+	dataCenters     store[govpsie.DataCenter]
+	domains         store[govpsie.Domain]
+	dnsRecords      map[string][]govpsie.Record
+	reverse         store[govpsie.ReversePTR]
+	ips             store[govpsie.IP]
+	firewalls       store[govpsie.FirewallGroupDetailData]
+	gateways        store[govpsie.Gateway]
+	images          store[govpsie.CustomImage]
+	clusters        store[govpsie.K8s]
+	clusterGroups   store[govpsie.K8sGroup]
+	groupClusters   map[string]string
+	lbs             store[govpsie.LBDetails]
+	monitoringRules store[govpsie.MonitoringRule]
+	projects        store[govpsie.Project]
+	scripts         store[govpsie.ScriptDetail]
+	servers         store[govpsie.VmData]
+	serverPlans     []govpsie.LBOffers
+	serverTags      map[string][]string
+	serverSshKeys   map[string][]string
+	snapshots       store[govpsie.Snapshot]
+	snapshotPolices store[govpsie.SnapShotPolicy]
+	sshKeys         store[govpsie.SShKey]
+	storages        store[govpsie.StorageDetail]
+	storageSnaps    store[govpsie.StorageSnapShot]
+	vpcs            store[govpsie.VPC]
+}
+
+// New returns an empty backend with a single data center, "dc-1", which
+// offers the server plans "plan-small", "plan-medium" and "plan-large".
+func New() *Backend {
+	b := &Backend{
+		calls:         map[string]int{},
+		failures:      map[string][]error{},
+		pending:       map[string]int{},
+		transitions:   map[string]*serverTransition{},
+		dnsRecords:    map[string][]govpsie.Record{},
+		bucketListing: map[string]bool{},
+		groupClusters: map[string]string{},
+		serverTags:    map[string][]string{},
+		serverSshKeys: map[string][]string{},
+	}
+
+	b.dataCenters.put("dc-1", &govpsie.DataCenter{
+		DcName:     "Fake DC",
+		State:      "active",
+		Country:    "Nowhere",
+		IsActive:   1,
+		Identifier: "dc-1",
+	})
+
+	b.serverPlans = []govpsie.LBOffers{
+		{Identifier: "plan-small", NickName: "Small", Cpu: 1, Ram: 1024, Ssd: 20, Traffic: 1000},
+		{Identifier: "plan-medium", NickName: "Medium", Cpu: 2, Ram: 2048, Ssd: 40, Traffic: 2000},
+		{Identifier: "plan-large", NickName: "Large", Cpu: 4, Ram: 4096, Ssd: 80, Traffic: 4000},
+	}
+
+	return b
+}
+
+// Client returns a govpsie client backed by b.
+func (b *Backend) Client() *govpsie.Client {
+	client := govpsie.NewClient(&http.Client{Transport: unimplemented{}})
+
+	client.AccessToken = &accessTokenService{AccessTokenService: client.AccessToken, b: b}
+	client.Backup = &backupService{BackupsService: client.Backup, b: b}
+	client.Bucket = &bucketService{BucketService: client.Bucket, b: b}
+	client.DataCenter = &dataCenterService{DataCenterService: client.DataCenter, b: b}
+	client.Domain = &domainService{DomainService: client.Domain, b: b}
+	client.Fip = &fipService{FipService: client.Fip, b: b}
+	client.IP = &ipService{IPsService: client.IP, b: b}
+	client.FirewallGroup = &firewallService{FirewallGroupService: client.FirewallGroup, b: b}
+	client.Gateway = &gatewayService{GatewayService: client.Gateway, b: b}
+	client.Image = &imageService{ImagesService: client.Image, b: b}
+	client.K8s = &kubernetesService{K8sService: client.K8s, b: b}
+	client.LB = &loadbalancerService{LBsService: client.LB, b: b}
+	client.Monitoring = &monitoringService{MonitoringService: client.Monitoring, b: b}
+	client.Project = &projectService{ProjectsService: client.Project, b: b}
+	client.Scripts = &scriptService{ScriptsService: client.Scripts, b: b}
+	client.Server = &serverService{ServerService: client.Server, b: b}
+	client.Snapshot = &snapshotService{SnapshotService: client.Snapshot, b: b}
+	client.SShKey = &sshKeyService{SshkeysService: client.SShKey, b: b}
+	client.Storage = &storageService{StorageService: client.Storage, b: b}
+	client.VPC = &vpcService{VPCService: client.VPC, b: b}
+
+	return client
+}
+
+// FailNext makes the next call to method fail with err. Methods are named
+// after the govpsie service field and method, such as "Server.CreateServer".
+// Failures queue up when FailNext is called more than once.
+func (b *Backend) FailNext(method string, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures[method] = append(b.failures[method], err)
+}
+
+// Calls returns how many times method was called.
+func (b *Backend) Calls(method string) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.calls[method]
+}
+
+// Error returns an error shaped like the ones the provider gets for a
+// failed API response, for use with FailNext.
+func Error(statusCode int, message string) error {
+	return fmt.Errorf("%s (HTTP %d)", message, statusCode)
+}
+
+func notFound(kind, id string) error {
+	return Error(http.StatusNotFound, fmt.Sprintf("%s %s not found", kind, id))
+}
+
+// call records a call to method and returns the failure queued for it, if
+// any. It must be called with b.mu held.
+func (b *Backend) call(method string) error {
+	b.calls[method]++
+
+	if queue := b.failures[method]; len(queue) > 0 {
+		b.failures[method] = queue[1:]
+		return queue[0]
+	}
+	return nil
+}
+
+// provision hides a newly created object for ProvisioningPolls reads.
+func (b *Backend) provision(id string) {
+	if b.ProvisioningPolls > 0 {
+		b.pending[id] = b.ProvisioningPolls
+	}
+}
+
+// hidden reports whether the object is still being provisioned, counting
+// the read.
+func (b *Backend) hidden(id string) bool {
+	if b.pending[id] <= 0 {
+		return false
+	}
+	b.pending[id]--
+	return true
+}
+
+func (b *Backend) id() int64 {
+	b.nextID++
+	return b.nextID
+}
+
+func (b *Backend) identifier(prefix string) (int64, string) {
+	id := b.id()
+	return id, prefix + "-" + strconv.FormatInt(id, 10)
+}
+
+func now() string {
+	return time.Now().UTC().Format(time.RFC3339)
+}
+
+// store keeps objects by identifier in creation order.
+type store[T any] struct {
+	order []string
+	items map[string]*T
+}
+
+func (s *store[T]) put(id string, item *T) {
+	if s.items == nil {
+		s.items = map[string]*T{}
+	}
+	if _, ok := s.items[id]; !ok {
+		s.order = append(s.order, id)
+	}
+	s.items[id] = item
+}
+
+func (s *store[T]) get(id string) (*T, bool) {
+	item, ok := s.items[id]
+	return item, ok
+}
+
+func (s *store[T]) delete(id string) bool {
+	if _, ok := s.items[id]; !ok {
+		return false
+	}
+	delete(s.items, id)
+	for i, existing := range s.order {
+		if existing == id {
+			s.order = append(s.order[:i], s.order[i+1:]...)
+			break
+		}
+	}
+	return true
+}
+
+// each calls fn for every object in creation order.
+func (s *store[T]) each(fn func(id string, item *T)) {
+	for _, id := range s.order {
+		fn(id, s.items[id])
+	}
+}
+
+func sortedCopy(values []string) []string {
+	out := append([]string{}, values...)
+	sort.Strings(out)
+	return out
+}
+
+type unimplemented struct{}
+
+func (unimplemented) RoundTrip(req *http.Request) (*http.Response, error) {
+	return nil, fmt.Errorf("fake VPSie API does not implement %s %s", req.Method, req.URL.Path)
+}
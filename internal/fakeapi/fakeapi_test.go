@@ -0,0 +1,111 @@
+package fakeapi_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/vpsie/govpsie"
+	"github.com/vpsie/terraform-provider-vpsie/internal/apierror"
+	"github.com/vpsie/terraform-provider-vpsie/internal/fakeapi"
+)
+
+func TestUnitFakeAPI_ProvisioningPolls(t *testing.T) {
+	ctx := context.Background()
+	backend := fakeapi.New()
+	backend.ProvisioningPolls = 2
+	client := backend.Client()
+
+	if err := client.Server.CreateServer(ctx, &govpsie.CreateServerRequest{Hostname: "web", DcIdentifier: "dc-1"}); err != nil {
+		t.Fatalf("CreateServer: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		servers, err := client.Server.List(ctx, nil)
+		if err != nil {
+			t.Fatalf("List: %v", err)
+		}
+		if len(servers) != 0 {
+			t.Fatalf("poll %d: server visible while provisioning", i+1)
+		}
+	}
+
+	servers, err := client.Server.List(ctx, nil)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(servers) != 1 || servers[0].Hostname != "web" {
+		t.Fatalf("expected the server after provisioning, got %+v", servers)
+	}
+}
+
+func TestUnitFakeAPI_NotFoundAfterDelete(t *testing.T) {
+	ctx := context.Background()
+	client := fakeapi.New().Client()
+
+	if err := client.SShKey.Create(ctx, "ssh-ed25519 AAAA", "deploy"); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	keys, err := client.SShKey.List(ctx)
+	if err != nil || len(keys) != 1 {
+		t.Fatalf("List: %v, %+v", err, keys)
+	}
+
+	if err := client.SShKey.Delete(ctx, keys[0].Identifier); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	_, err = client.SShKey.Get(ctx, keys[0].Identifier)
+	if !apierror.IsNotFound(err) {
+		t.Fatalf("expected a not found error after delete, got %v", err)
+	}
+	if err := client.SShKey.Delete(ctx, keys[0].Identifier); !apierror.IsNotFound(err) {
+		t.Fatalf("expected a not found error deleting twice, got %v", err)
+	}
+}
+
+func TestUnitFakeAPI_FailNext(t *testing.T) {
+	ctx := context.Background()
+	backend := fakeapi.New()
+	client := backend.Client()
+
+	backend.FailNext("Project.Create", fakeapi.Error(http.StatusTooManyRequests, "slow down"))
+
+	err := client.Project.Create(ctx, &govpsie.CreateProjectRequest{Name: "p"})
+	if !apierror.IsRateLimited(err) {
+		t.Fatalf("expected the injected rate limit error, got %v", err)
+	}
+	if err := client.Project.Create(ctx, &govpsie.CreateProjectRequest{Name: "p"}); err != nil {
+		t.Fatalf("expected the second call to succeed, got %v", err)
+	}
+	if calls := backend.Calls("Project.Create"); calls != 2 {
+		t.Fatalf("expected 2 calls, got %d", calls)
+	}
+}
+
+func TestUnitFakeAPI_LockedServer(t *testing.T) {
+	ctx := context.Background()
+	client := fakeapi.New().Client()
+
+	if err := client.Server.CreateServer(ctx, &govpsie.CreateServerRequest{Hostname: "db"}); err != nil {
+		t.Fatalf("CreateServer: %v", err)
+	}
+	servers, _ := client.Server.List(ctx, nil)
+	identifier := servers[0].Identifier
+
+	if err := client.Server.Lock(ctx, identifier); err != nil {
+		t.Fatalf("Lock: %v", err)
+	}
+	if err := client.Server.DeleteServer(ctx, identifier, "", "", ""); !apierror.IsConflict(err) {
+		t.Fatalf("expected a conflict deleting a locked server, got %v", err)
+	}
+}
+
+func TestUnitFakeAPI_Unimplemented(t *testing.T) {
+	client := fakeapi.New().Client()
+
+	_, err := client.Server.GetServerConsole(context.Background(), "vm-1")
+	if err == nil {
+		t.Fatal("expected an error from a call the fake does not implement")
+	}
+}
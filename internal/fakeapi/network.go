@@ -0,0 +1,614 @@
+package fakeapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/vpsie/govpsie"
+)
+
+type ipService struct {
+	govpsie.IPsService
+	b *Backend
+}
+
+func (s *ipService) ListPublicIPs(ctx context.Context, options *govpsie.ListOptions) ([]govpsie.IP, error) {
+	return s.list("IP.ListPublicIPs", "public")
+}
+
+func (s *ipService) ListPrivateIPs(ctx context.Context, options *govpsie.ListOptions) ([]govpsie.IP, error) {
+	return s.list("IP.ListPrivateIPs", "private")
+}
+
+func (s *ipService) ListAllIPs(ctx context.Context, options *govpsie.ListOptions) ([]govpsie.IP, error) {
+	return s.list("IP.ListAllIPs", "")
+}
+
+func (s *ipService) list(method, ipType string) ([]govpsie.IP, error) {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call(method); err != nil {
+		return nil, err
+	}
+
+	ips := []govpsie.IP{}
+	s.b.ips.each(func(id string, ip *govpsie.IP) {
+		if ipType == "" || ip.Type == ipType {
+			ips = append(ips, *ip)
+		}
+	})
+
+	return ips, nil
+}
+
+type fipService struct {
+	govpsie.FipService
+	b *Backend
+}
+
+func (s *fipService) CreateFloatingIP(ctx context.Context, vmIdentifier, dcIdentifier, ipType string) error {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("Fip.CreateFloatingIP"); err != nil {
+		return err
+	}
+
+	server, err := s.b.server(vmIdentifier)
+	if err != nil {
+		return err
+	}
+
+	id := s.b.id()
+	address := fmt.Sprintf("198.51.100.%d", id%250+1)
+	if ipType == "ipv6" {
+		address = fmt.Sprintf("2001:db8::%x", id)
+	}
+	s.b.ips.put(strconv.FormatInt(id, 10), &govpsie.IP{
+		ID:            int(id),
+		DcIdentifier:  dcIdentifier,
+		IP:            address,
+		IPVersion:     ipType,
+		Hostname:      server.Hostname,
+		BoxID:         int(server.ID),
+		BoxIdentifier: vmIdentifier,
+		Type:          "public",
+		UpdatedAt:     now(),
+	})
+
+	return nil
+}
+
+func (s *fipService) UnassignFloatingIP(ctx context.Context, id string) error {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("Fip.UnassignFloatingIP"); err != nil {
+		return err
+	}
+
+	if !s.b.ips.delete(id) {
+		return notFound("ip", id)
+	}
+	return nil
+}
+
+type gatewayService struct {
+	govpsie.GatewayService
+	b *Backend
+}
+
+func (s *gatewayService) Create(ctx context.Context, createReq *govpsie.CreateGatewayReq) error {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("Gateway.Create"); err != nil {
+		return err
+	}
+
+	id := s.b.id()
+	s.b.gateways.put(strconv.FormatInt(id, 10), &govpsie.Gateway{
+		ID:             id,
+		DatacenterID:   1,
+		IPPropertiesID: id,
+		IP:             fmt.Sprintf("203.0.113.%d", id%250+1),
+		IPVersion:      createReq.IPType,
+		UserID:         1,
+		UpdatedAt:      time.Now().UTC(),
+		DatacenterName: "Fake DC",
+		State:          "active",
+		DcIdentifier:   createReq.DcIdentifier,
+		CreatedBy:      "terraform",
+		AttachedVms:    []govpsie.AttachedVM{},
+	})
+
+	return nil
+}
+
+func (s *gatewayService) List(ctx context.Context, options *govpsie.ListOptions) ([]govpsie.Gateway, error) {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("Gateway.List"); err != nil {
+		return nil, err
+	}
+
+	gateways := []govpsie.Gateway{}
+	s.b.gateways.each(func(id string, gateway *govpsie.Gateway) {
+		gateways = append(gateways, copyGateway(gateway))
+	})
+
+	return gateways, nil
+}
+
+func (s *gatewayService) Get(ctx context.Context, id int64) (*govpsie.Gateway, error) {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("Gateway.Get"); err != nil {
+		return nil, err
+	}
+
+	gateway, err := s.b.gateway(id)
+	if err != nil {
+		return nil, err
+	}
+
+	copied := copyGateway(gateway)
+	return &copied, nil
+}
+
+func (s *gatewayService) Delete(ctx context.Context, ipId int) error {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("Gateway.Delete"); err != nil {
+		return err
+	}
+
+	if !s.b.gateways.delete(strconv.Itoa(ipId)) {
+		return notFound("gateway", strconv.Itoa(ipId))
+	}
+	return nil
+}
+
+func (s *gatewayService) AttachVM(ctx context.Context, id int64, vms []string, ignoreLegacyVms int64) error {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("Gateway.AttachVM"); err != nil {
+		return err
+	}
+
+	gateway, err := s.b.gateway(id)
+	if err != nil {
+		return err
+	}
+	for _, vm := range vms {
+		if _, err := s.b.server(vm); err != nil {
+			return err
+		}
+		gateway.AttachedVms = append(gateway.AttachedVms, govpsie.AttachedVM{
+			Identifier:       vm,
+			GatewayMappingID: s.b.id(),
+		})
+	}
+
+	return nil
+}
+
+func (s *gatewayService) DetachVM(ctx context.Context, id int64, mapping_id []int64) error {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("Gateway.DetachVM"); err != nil {
+		return err
+	}
+
+	gateway, err := s.b.gateway(id)
+	if err != nil {
+		return err
+	}
+
+	detach := map[int64]bool{}
+	for _, mappingID := range mapping_id {
+		detach[mappingID] = true
+	}
+	attached := []govpsie.AttachedVM{}
+	for _, vm := range gateway.AttachedVms {
+		if !detach[vm.GatewayMappingID] {
+			attached = append(attached, vm)
+		}
+	}
+	gateway.AttachedVms = attached
+
+	return nil
+}
+
+func (b *Backend) gateway(id int64) (*govpsie.Gateway, error) {
+	gateway, ok := b.gateways.get(strconv.FormatInt(id, 10))
+	if !ok {
+		return nil, notFound("gateway", strconv.FormatInt(id, 10))
+	}
+	return gateway, nil
+}
+
+func copyGateway(gateway *govpsie.Gateway) govpsie.Gateway {
+	copied := *gateway
+	copied.AttachedVms = append([]govpsie.AttachedVM{}, gateway.AttachedVms...)
+	return copied
+}
+
+type vpcService struct {
+	govpsie.VPCService
+	b *Backend
+}
+
+func (s *vpcService) CreateVpc(ctx context.Context, createReq *govpsie.CreateVpcReq) error {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("VPC.CreateVpc"); err != nil {
+		return err
+	}
+
+	networkRange := createReq.NetworkRange
+	if createReq.AutoGenerate == 1 || networkRange == "" {
+		networkRange = "10.0.0.0"
+	}
+	networkSize, err := strconv.Atoi(createReq.NetworkSize)
+	if err != nil {
+		return Error(http.StatusBadRequest, "invalid networkSize "+createReq.NetworkSize)
+	}
+
+	id := s.b.id()
+	s.b.vpcs.put(strconv.FormatInt(id, 10), &govpsie.VPC{
+		ID:           int(id),
+		UserID:       1,
+		OwnerID:      1,
+		DatacenterID: 1,
+		Name:         createReq.Name,
+		Description:  createReq.Description,
+		NetworkRange: networkRange,
+		NetworkSize:  networkSize,
+		CreatedBy:    1,
+		UpdatedBy:    1,
+		CreatedOn:    time.Now().UTC(),
+		LastUpdated:  time.Now().UTC(),
+		State:        "active",
+		DcName:       "Fake DC",
+		DcIdentifier: createReq.DcIdentifier,
+	})
+
+	return nil
+}
+
+func (s *vpcService) List(ctx context.Context, options *govpsie.ListOptions) ([]govpsie.VPC, error) {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("VPC.List"); err != nil {
+		return nil, err
+	}
+
+	vpcs := []govpsie.VPC{}
+	s.b.vpcs.each(func(id string, vpc *govpsie.VPC) {
+		vpcs = append(vpcs, *vpc)
+	})
+
+	return vpcs, nil
+}
+
+func (s *vpcService) Get(ctx context.Context, id string) (*govpsie.VPC, error) {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("VPC.Get"); err != nil {
+		return nil, err
+	}
+
+	vpc, ok := s.b.vpcs.get(id)
+	if !ok {
+		return nil, notFound("vpc", id)
+	}
+
+	copied := *vpc
+	return &copied, nil
+}
+
+func (s *vpcService) DeleteVpc(ctx context.Context, vpcId, reason, note string) error {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("VPC.DeleteVpc"); err != nil {
+		return err
+	}
+
+	if !s.b.vpcs.delete(vpcId) {
+		return notFound("vpc", vpcId)
+	}
+	return nil
+}
+
+func (s *vpcService) AssignServer(ctx context.Context, assignReq *govpsie.AssignServerReq) error {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("VPC.AssignServer"); err != nil {
+		return err
+	}
+
+	server, err := s.b.server(assignReq.VmIdentifier)
+	if err != nil {
+		return err
+	}
+	if _, ok := s.b.vpcs.get(strconv.Itoa(assignReq.VpcID)); !ok {
+		return notFound("vpc", strconv.Itoa(assignReq.VpcID))
+	}
+
+	id := s.b.id()
+	server.PrivateIP = fmt.Sprintf("10.0.1.%d", id%250+1)
+	s.b.ips.put(strconv.FormatInt(id, 10), &govpsie.IP{
+		ID:            int(id),
+		DcIdentifier:  assignReq.DcIdentifier,
+		IP:            server.PrivateIP,
+		IPVersion:     "ipv4",
+		Hostname:      server.Hostname,
+		BoxID:         int(server.ID),
+		BoxIdentifier: server.Identifier,
+		Type:          "private",
+		UpdatedAt:     now(),
+	})
+
+	return nil
+}
+
+func (s *vpcService) ReleasePrivateIP(ctx context.Context, vmIdentifer string, privateIpId int) error {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("VPC.ReleasePrivateIP"); err != nil {
+		return err
+	}
+
+	id := strconv.Itoa(privateIpId)
+	ip, ok := s.b.ips.get(id)
+	if !ok || ip.BoxIdentifier != vmIdentifer || ip.Type != "private" {
+		return notFound("private ip", id)
+	}
+
+	s.b.ips.delete(id)
+	if server, ok := s.b.servers.get(vmIdentifer); ok && server.PrivateIP == ip.IP {
+		server.PrivateIP = ""
+	}
+
+	return nil
+}
+
+type firewallService struct {
+	govpsie.FirewallGroupService
+	b *Backend
+}
+
+func (s *firewallService) Create(ctx context.Context, groupName string, firewallUpdateReq []govpsie.FirewallUpdateReq) error {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("FirewallGroup.Create"); err != nil {
+		return err
+	}
+
+	id, identifier := s.b.identifier("firewall")
+	group := &govpsie.FirewallGroupDetailData{
+		Group: govpsie.FirewallGroup{
+			UserName:   "terraform",
+			ID:         id,
+			GroupName:  groupName,
+			Identifier: identifier,
+			CreatedOn:  now(),
+			UpdatedOn:  now(),
+			CreatedBy:  1,
+		},
+		Rules: []govpsie.FirewallRules{{
+			InBound:  []govpsie.InBoundFirewallRules{},
+			OutBound: []govpsie.OutBoundFirewallRules{},
+		}},
+		Vms: []govpsie.VmsData{},
+	}
+	for i := range firewallUpdateReq {
+		s.b.addFirewallRule(group, &firewallUpdateReq[i])
+	}
+	s.b.firewalls.put(identifier, group)
+
+	return nil
+}
+
+func (s *firewallService) List(ctx context.Context, options *govpsie.ListOptions) ([]govpsie.FirewallGroupListData, error) {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("FirewallGroup.List"); err != nil {
+		return nil, err
+	}
+
+	groups := []govpsie.FirewallGroupListData{}
+	s.b.firewalls.each(func(id string, group *govpsie.FirewallGroupDetailData) {
+		copied := copyFirewall(group)
+		groups = append(groups, govpsie.FirewallGroupListData{
+			UserName:      copied.Group.UserName,
+			ID:            copied.Group.ID,
+			GroupName:     copied.Group.GroupName,
+			Identifier:    copied.Group.Identifier,
+			CreatedOn:     copied.Group.CreatedOn,
+			UpdatedOn:     copied.Group.UpdatedOn,
+			InboundCount:  copied.Group.InboundCount,
+			OutboundCount: copied.Group.OutboundCount,
+			Vms:           copied.Group.Vms,
+			CreatedBy:     copied.Group.CreatedBy,
+			Rules:         copied.Rules,
+			VmsData:       copied.Vms,
+		})
+	})
+
+	return groups, nil
+}
+
+func (s *firewallService) Get(ctx context.Context, fwGroupId string) (*govpsie.FirewallGroupDetailData, error) {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("FirewallGroup.Get"); err != nil {
+		return nil, err
+	}
+
+	group, err := s.b.firewall(fwGroupId)
+	if err != nil {
+		return nil, err
+	}
+
+	copied := copyFirewall(group)
+	return &copied, nil
+}
+
+func (s *firewallService) Delete(ctx context.Context, fwGroupId string) error {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("FirewallGroup.Delete"); err != nil {
+		return err
+	}
+
+	group, err := s.b.firewall(fwGroupId)
+	if err != nil {
+		return err
+	}
+	if len(group.Vms) > 0 {
+		return Error(http.StatusConflict, "firewall group is attached to servers")
+	}
+
+	s.b.firewalls.delete(fwGroupId)
+	return nil
+}
+
+// Update adds a rule to the group, which is what the API does with it.
+func (s *firewallService) Update(ctx context.Context, fwGroupReq *govpsie.FirewallUpdateReq, fwGroupId string) error {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("FirewallGroup.Update"); err != nil {
+		return err
+	}
+
+	group, err := s.b.firewall(fwGroupId)
+	if err != nil {
+		return err
+	}
+	s.b.addFirewallRule(group, fwGroupReq)
+	group.Group.UpdatedOn = now()
+
+	return nil
+}
+
+func (s *firewallService) AttachToVpsie(ctx context.Context, groupId, vmId string) error {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("FirewallGroup.AttachToVpsie"); err != nil {
+		return err
+	}
+
+	group, err := s.b.firewall(groupId)
+	if err != nil {
+		return err
+	}
+	server, err := s.b.server(vmId)
+	if err != nil {
+		return err
+	}
+	for _, vm := range group.Vms {
+		if vm.Identifier == vmId {
+			return Error(http.StatusConflict, "firewall group is already attached to "+vmId)
+		}
+	}
+
+	group.Vms = append(group.Vms, govpsie.VmsData{
+		Hostname:   server.Hostname,
+		Identifier: server.Identifier,
+		Fullname:   server.FullName,
+		Category:   server.Category,
+	})
+	group.Group.Vms = int64(len(group.Vms))
+
+	return nil
+}
+
+func (s *firewallService) DetachFromVpsie(ctx context.Context, groupId, vmId string) error {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("FirewallGroup.DetachFromVpsie"); err != nil {
+		return err
+	}
+
+	group, err := s.b.firewall(groupId)
+	if err != nil {
+		return err
+	}
+	for i, vm := range group.Vms {
+		if vm.Identifier == vmId {
+			group.Vms = append(group.Vms[:i], group.Vms[i+1:]...)
+			group.Group.Vms = int64(len(group.Vms))
+			return nil
+		}
+	}
+
+	return notFound("firewall attachment", vmId)
+}
+
+func (b *Backend) firewall(identifier string) (*govpsie.FirewallGroupDetailData, error) {
+	group, ok := b.firewalls.get(identifier)
+	if !ok {
+		return nil, notFound("firewall group", identifier)
+	}
+	return group, nil
+}
+
+func (b *Backend) addFirewallRule(group *govpsie.FirewallGroupDetailData, req *govpsie.FirewallUpdateReq) {
+	_, identifier := b.identifier("rule")
+	created := time.Now().UTC()
+
+	if req.Type == "out" {
+		group.Rules[0].OutBound = append(group.Rules[0].OutBound, govpsie.OutBoundFirewallRules{
+			ID:         b.nextID,
+			GroupID:    group.Group.ID,
+			UserID:     1,
+			Action:     req.Action,
+			Type:       req.Type,
+			Comment:    req.Comment,
+			Dest:       req.Dest,
+			Dport:      req.Dport,
+			Proto:      req.Proto,
+			Source:     req.Source,
+			Sport:      req.Sport,
+			Enable:     req.Enable,
+			Macro:      req.Macro,
+			Identifier: identifier,
+			CreatedOn:  created,
+			UpdatedOn:  created,
+		})
+		group.Group.OutboundCount++
+		return
+	}
+
+	group.Rules[0].InBound = append(group.Rules[0].InBound, govpsie.InBoundFirewallRules{
+		ID:         b.nextID,
+		GroupID:    group.Group.ID,
+		UserID:     1,
+		Action:     req.Action,
+		Type:       req.Type,
+		Comment:    req.Comment,
+		Dest:       req.Dest,
+		Dport:      req.Dport,
+		Proto:      req.Proto,
+		Source:     req.Source,
+		Sport:      req.Sport,
+		Enable:     req.Enable,
+		Macro:      req.Macro,
+		Identifier: identifier,
+		CreatedOn:  created,
+		UpdatedOn:  created,
+	})
+	group.Group.InboundCount++
+}
+
+func copyFirewall(group *govpsie.FirewallGroupDetailData) govpsie.FirewallGroupDetailData {
+	copied := *group
+	copied.Rules = []govpsie.FirewallRules{{
+		InBound:  append([]govpsie.InBoundFirewallRules{}, group.Rules[0].InBound...),
+		OutBound: append([]govpsie.OutBoundFirewallRules{}, group.Rules[0].OutBound...),
+	}}
+	copied.Vms = append([]govpsie.VmsData{}, group.Vms...)
+	return copied
+}
@@ -0,0 +1,454 @@
+package fakeapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/vpsie/govpsie"
+)
+
+type serverService struct {
+	govpsie.ServerService
+	b *Backend
+}
+
+func (s *serverService) CreateServer(ctx context.Context, req *govpsie.CreateServerRequest) error {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("Server.CreateServer"); err != nil {
+		return err
+	}
+
+	id, identifier := s.b.identifier("vm")
+	ip := fmt.Sprintf("192.0.2.%d", id%250+1)
+	server := &govpsie.VmData{
+		ID:                id,
+		UserID:            1,
+		Hostname:          req.Hostname,
+		DefaultIP:         ip,
+		BoxVirtualization: "kvm",
+		Cpu:               1,
+		Ram:               1024,
+		Ssd:               20,
+		Traffic:           1000,
+		Notes:             req.Notes,
+		CreatedOn:         now(),
+		LastUpdated:       now(),
+		IsActive:          1,
+		Identifier:        identifier,
+		Power:             1,
+		ProjectID:         req.ProjectID,
+		ScriptID:          req.ScriptIdentifier,
+		SshKeyID:          req.SshKeyIdentifier,
+		State:             "running",
+		DcIdentifier:      req.DcIdentifier,
+		Category:          "linux",
+		FullName:          req.OsIdentifier,
+		PublicIp:          &ip,
+		InitialPassword:   fmt.Sprintf("initial-password-%d", id),
+	}
+	if plan, ok := s.b.serverPlan(req.ResourceIdentifier); ok {
+		applyServerPlan(server, plan)
+	}
+	if req.AddPrivateIp != nil && *req.AddPrivateIp == 1 {
+		server.PrivateIP = fmt.Sprintf("10.0.0.%d", id%250+1)
+	}
+	s.b.servers.put(identifier, server)
+
+	var tags []string
+	for _, tag := range req.Tags {
+		if tag != nil {
+			tags = append(tags, *tag)
+		}
+	}
+	s.b.serverTags[identifier] = sortedCopy(tags)
+	if req.SshKeyIdentifier != nil {
+		s.b.serverSshKeys[identifier] = []string{*req.SshKeyIdentifier}
+	}
+
+	ipID := s.b.id()
+	s.b.ips.put(strconv.FormatInt(ipID, 10), &govpsie.IP{
+		ID:            int(ipID),
+		DcIdentifier:  req.DcIdentifier,
+		IP:            ip,
+		IPVersion:     "ipv4",
+		IsPrimary:     1,
+		Hostname:      req.Hostname,
+		BoxID:         int(id),
+		BoxIdentifier: identifier,
+		Type:          "public",
+		UpdatedAt:     now(),
+	})
+
+	s.b.provision(identifier)
+
+	return nil
+}
+
+func (s *serverService) List(ctx context.Context, options *govpsie.ListOptions) ([]govpsie.VmData, error) {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("Server.List"); err != nil {
+		return nil, err
+	}
+
+	servers := []govpsie.VmData{}
+	s.b.servers.each(func(id string, server *govpsie.VmData) {
+		if !s.b.hidden(id) {
+			servers = append(servers, *server)
+		}
+	})
+
+	return servers, nil
+}
+
+func (s *serverService) GetServerByIdentifier(ctx context.Context, identifierId string) (*govpsie.VmData, error) {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("Server.GetServerByIdentifier"); err != nil {
+		return nil, err
+	}
+
+	server, err := s.b.server(identifierId)
+	if err != nil {
+		return nil, err
+	}
+	if s.b.hidden(identifierId) {
+		return nil, notFound("server", identifierId)
+	}
+
+	copied := *server
+	if t := s.b.transitions[identifierId]; t != nil {
+		switch {
+		case t.queued > 0:
+			t.queued--
+			copied = t.before
+		case t.polls > 0:
+			t.polls--
+			copied.State = t.state
+		}
+	}
+	return &copied, nil
+}
+
+func (s *serverService) DeleteServer(ctx context.Context, identifierId, password, reason, note string) error {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("Server.DeleteServer"); err != nil {
+		return err
+	}
+
+	server, err := s.b.server(identifierId)
+	if err != nil {
+		return err
+	}
+	if server.IsLocked == 1 {
+		return Error(http.StatusConflict, "server is locked")
+	}
+
+	s.b.servers.delete(identifierId)
+	delete(s.b.serverTags, identifierId)
+	delete(s.b.serverSshKeys, identifierId)
+	s.b.ips.each(func(id string, ip *govpsie.IP) {
+		if ip.BoxIdentifier == identifierId {
+			s.b.ips.delete(id)
+		}
+	})
+
+	return nil
+}
+
+func (s *serverService) ChangeHostName(ctx context.Context, identifierId string, newHostname string) error {
+	return s.update("Server.ChangeHostName", identifierId, func(server *govpsie.VmData) error {
+		server.Hostname = newHostname
+		return nil
+	})
+}
+
+func (s *serverService) StartServer(ctx context.Context, identifierId string) error {
+	return s.update("Server.StartServer", identifierId, func(server *govpsie.VmData) error {
+		before := *server
+		server.Power = 1
+		server.State = "running"
+		s.b.transition(identifierId, before, "starting")
+		return nil
+	})
+}
+
+func (s *serverService) StopServer(ctx context.Context, identifierId string) error {
+	return s.update("Server.StopServer", identifierId, func(server *govpsie.VmData) error {
+		before := *server
+		server.Power = 0
+		server.State = "stopped"
+		s.b.transition(identifierId, before, "stopping")
+		return nil
+	})
+}
+
+func (s *serverService) RestartServer(ctx context.Context, identifierId string) error {
+	return s.update("Server.RestartServer", identifierId, func(server *govpsie.VmData) error {
+		before := *server
+		server.Power = 1
+		server.State = "running"
+		s.b.transition(identifierId, before, "restarting")
+		return nil
+	})
+}
+
+func (s *serverService) Lock(ctx context.Context, identifierId string) error {
+	return s.update("Server.Lock", identifierId, func(server *govpsie.VmData) error {
+		server.IsLocked = 1
+		return nil
+	})
+}
+
+func (s *serverService) UnLock(ctx context.Context, identifierId string) error {
+	return s.update("Server.UnLock", identifierId, func(server *govpsie.VmData) error {
+		server.IsLocked = 0
+		return nil
+	})
+}
+
+func (s *serverService) AddSsh(ctx context.Context, identifierId, sshKeyIdentifier string) error {
+	return s.update("Server.AddSsh", identifierId, func(server *govpsie.VmData) error {
+		server.SshKeyID = &sshKeyIdentifier
+		s.b.serverSshKeys[identifierId] = mergeTags(s.b.serverSshKeys[identifierId], []string{sshKeyIdentifier})
+		return nil
+	})
+}
+
+func (s *serverService) AddScript(ctx context.Context, identifierId, scriptIdentifier string) error {
+	return s.update("Server.AddScript", identifierId, func(server *govpsie.VmData) error {
+		server.ScriptID = &scriptIdentifier
+		return nil
+	})
+}
+
+func (s *serverService) ResizeServer(ctx context.Context, identifierId, cpu, ram string) error {
+	return s.update("Server.ResizeServer", identifierId, func(server *govpsie.VmData) error {
+		newCPU, err := strconv.ParseInt(cpu, 10, 64)
+		if err != nil {
+			return Error(http.StatusBadRequest, "invalid cpu "+cpu)
+		}
+		newRAM, err := strconv.ParseInt(ram, 10, 64)
+		if err != nil {
+			return Error(http.StatusBadRequest, "invalid ram "+ram)
+		}
+		server.Cpu = newCPU
+		server.Ram = newRAM
+		return nil
+	})
+}
+
+// ListServerPlans and ChangeServerPlan stand in for the raw requests the
+// server resource makes for resource plans.
+
+func (s *serverService) ListServerPlans(ctx context.Context, dcIdentifier string) ([]govpsie.LBOffers, error) {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("Server.ListServerPlans"); err != nil {
+		return nil, err
+	}
+
+	if _, ok := s.b.dataCenters.get(dcIdentifier); !ok {
+		return nil, notFound("data center", dcIdentifier)
+	}
+
+	return append([]govpsie.LBOffers{}, s.b.serverPlans...), nil
+}
+
+func (s *serverService) ChangeServerPlan(ctx context.Context, identifierId, resourceIdentifier string) error {
+	return s.update("Server.ChangeServerPlan", identifierId, func(server *govpsie.VmData) error {
+		plan, ok := s.b.serverPlan(resourceIdentifier)
+		if !ok {
+			return Error(http.StatusBadRequest, "unknown plan "+resourceIdentifier)
+		}
+		if int64(plan.Ssd) < server.Ssd {
+			return Error(http.StatusBadRequest, "disk cannot be shrunk")
+		}
+		before := *server
+		applyServerPlan(server, plan)
+		s.b.transition(identifierId, before, "resizing")
+		return nil
+	})
+}
+
+// RebuildServer stands in for the raw request the server resource makes to
+// reinstall a server. The reinstall drops the server's SSH key and script.
+func (s *serverService) RebuildServer(ctx context.Context, identifierId, osIdentifier string) error {
+	return s.update("Server.RebuildServer", identifierId, func(server *govpsie.VmData) error {
+		before := *server
+		server.FullName = osIdentifier
+		server.BoxImageID++
+		server.SshKeyID = nil
+		server.ScriptID = nil
+		delete(s.b.serverSshKeys, identifierId)
+		server.Power = 1
+		server.State = "running"
+		s.b.transition(identifierId, before, "rebuilding")
+		return nil
+	})
+}
+
+// ListServerSshKeys, AddServerSshKey and RemoveServerSshKey stand in for the
+// raw requests the server resource makes for SSH keys.
+
+func (s *serverService) ListServerSshKeys(ctx context.Context, identifierId string) ([]string, error) {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("Server.ListServerSshKeys"); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.b.server(identifierId); err != nil {
+		return nil, err
+	}
+
+	return sortedCopy(s.b.serverSshKeys[identifierId]), nil
+}
+
+func (s *serverService) AddServerSshKey(ctx context.Context, identifierId, sshKeyIdentifier string) error {
+	return s.AddSsh(ctx, identifierId, sshKeyIdentifier)
+}
+
+func (s *serverService) RemoveServerSshKey(ctx context.Context, identifierId, sshKeyIdentifier string) error {
+	return s.update("Server.RemoveServerSshKey", identifierId, func(server *govpsie.VmData) error {
+		var keys []string
+		for _, key := range s.b.serverSshKeys[identifierId] {
+			if key != sshKeyIdentifier {
+				keys = append(keys, key)
+			}
+		}
+		s.b.serverSshKeys[identifierId] = keys
+		if server.SshKeyID != nil && *server.SshKeyID == sshKeyIdentifier {
+			server.SshKeyID = nil
+		}
+		return nil
+	})
+}
+
+func (s *serverService) AddTags(ctx context.Context, identifierId string, tags []string) error {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("Server.AddTags"); err != nil {
+		return err
+	}
+
+	if _, err := s.b.server(identifierId); err != nil {
+		return err
+	}
+
+	s.b.serverTags[identifierId] = mergeTags(s.b.serverTags[identifierId], tags)
+	return nil
+}
+
+// ListServerTags and SetServerTags stand in for the raw requests the
+// server resource makes for tags.
+
+func (s *serverService) ListServerTags(ctx context.Context, identifierId string) ([]string, error) {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("Server.ListServerTags"); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.b.server(identifierId); err != nil {
+		return nil, err
+	}
+
+	return sortedCopy(s.b.serverTags[identifierId]), nil
+}
+
+func (s *serverService) AddServerTags(ctx context.Context, identifierId string, tags []string) error {
+	return s.AddTags(ctx, identifierId, tags)
+}
+
+func (s *serverService) SetServerTags(ctx context.Context, identifierId string, tags []string) error {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("Server.SetServerTags"); err != nil {
+		return err
+	}
+
+	if _, err := s.b.server(identifierId); err != nil {
+		return err
+	}
+
+	s.b.serverTags[identifierId] = mergeTags(nil, tags)
+	return nil
+}
+
+func (s *serverService) update(method, identifierId string, fn func(server *govpsie.VmData) error) error {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call(method); err != nil {
+		return err
+	}
+
+	server, err := s.b.server(identifierId)
+	if err != nil {
+		return err
+	}
+	if err := fn(server); err != nil {
+		return err
+	}
+	server.LastUpdated = now()
+
+	return nil
+}
+
+func (b *Backend) server(identifier string) (*govpsie.VmData, error) {
+	server, ok := b.servers.get(identifier)
+	if !ok {
+		return nil, notFound("server", identifier)
+	}
+	return server, nil
+}
+
+// serverTransition is what a server reads as after a request that changes
+// it: first as it was before the request, then with a transitional state,
+// each for a number of reads, before it reads as it is now.
+type serverTransition struct {
+	before govpsie.VmData
+	queued int
+	state  string
+	polls  int
+}
+
+// transition makes the server read as before for QueuedPolls reads, then
+// report state for ProvisioningPolls reads.
+func (b *Backend) transition(identifier string, before govpsie.VmData, state string) {
+	if b.QueuedPolls > 0 || b.ProvisioningPolls > 0 {
+		b.transitions[identifier] = &serverTransition{before: before, queued: b.QueuedPolls, state: state, polls: b.ProvisioningPolls}
+	}
+}
+
+func (b *Backend) serverPlan(resourceIdentifier string) (govpsie.LBOffers, bool) {
+	for _, plan := range b.serverPlans {
+		if plan.Identifier == resourceIdentifier {
+			return plan, true
+		}
+	}
+	return govpsie.LBOffers{}, false
+}
+
+func applyServerPlan(server *govpsie.VmData, plan govpsie.LBOffers) {
+	server.Cpu = int64(plan.Cpu)
+	server.Ram = int64(plan.Ram)
+	server.Ssd = int64(plan.Ssd)
+	server.Traffic = int64(plan.Traffic)
+}
+
+func mergeTags(have, add []string) []string {
+	seen := map[string]bool{}
+	var out []string
+	for _, tag := range append(append([]string{}, have...), add...) {
+		if tag != "" && !seen[tag] {
+			seen[tag] = true
+			out = append(out, tag)
+		}
+	}
+	return sortedCopy(out)
+}
@@ -0,0 +1,267 @@
+package fakeapi
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/vpsie/govpsie"
+)
+
+type storageService struct {
+	govpsie.StorageService
+	b *Backend
+}
+
+func (s *storageService) CreateVolume(ctx context.Context, req *govpsie.StorageCreateRequest) error {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("Storage.CreateVolume"); err != nil {
+		return err
+	}
+
+	id, identifier := s.b.identifier("storage")
+	s.b.storages.put(identifier, &govpsie.StorageDetail{
+		ID:           int(id),
+		Name:         req.Name,
+		Description:  req.Description,
+		UserID:       1,
+		Identifier:   identifier,
+		StorageType:  req.StorageType,
+		DiskFormat:   req.DiskFormat,
+		IsAutomatic:  req.IsAutomatic,
+		Size:         req.Size,
+		StorageID:    int(id),
+		CreatedOn:    time.Now().UTC(),
+		UpdatedAt:    time.Now().UTC(),
+		State:        "available",
+		DcIdentifier: req.DcIdentifier,
+	})
+
+	return nil
+}
+
+func (s *storageService) ListAll(ctx context.Context, options *govpsie.ListOptions) ([]govpsie.Storage, error) {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("Storage.ListAll"); err != nil {
+		return nil, err
+	}
+
+	storages := []govpsie.Storage{}
+	s.b.storages.each(func(id string, storage *govpsie.StorageDetail) {
+		storages = append(storages, govpsie.Storage{
+			ID:             storage.ID,
+			Name:           storage.Name,
+			Description:    storage.Description,
+			UserID:         storage.UserID,
+			BoxID:          storage.BoxID,
+			Identifier:     storage.Identifier,
+			UserTemplateID: storage.UserTemplateID,
+			StorageType:    storage.StorageType,
+			DiskFormat:     storage.DiskFormat,
+			IsAutomatic:    storage.IsAutomatic,
+			Size:           storage.Size,
+			StorageID:      storage.StorageID,
+			DiskKey:        storage.DiskKey,
+			CreatedOn:      storage.CreatedOn.Format(time.RFC3339),
+			VmIdentifier:   storage.VMIdentifier,
+			Hostname:       storage.Hostname,
+			OsIdentifier:   storage.OsIdentifier,
+			State:          storage.State,
+			DcIdentifier:   storage.DcIdentifier,
+			BusDevice:      storage.BusDevice,
+			BusNumber:      storage.BusNumber,
+		})
+	})
+
+	return storages, nil
+}
+
+func (s *storageService) Get(ctx context.Context, identifier string) (*govpsie.StorageDetail, error) {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("Storage.Get"); err != nil {
+		return nil, err
+	}
+
+	storage, err := s.b.storage(identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	copied := *storage
+	return &copied, nil
+}
+
+func (s *storageService) Delete(ctx context.Context, storageIdentifier string) error {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("Storage.Delete"); err != nil {
+		return err
+	}
+
+	storage, err := s.b.storage(storageIdentifier)
+	if err != nil {
+		return err
+	}
+	if storage.VMIdentifier != "" {
+		return Error(http.StatusConflict, "storage is attached to a server")
+	}
+
+	s.b.storages.delete(storageIdentifier)
+	return nil
+}
+
+func (s *storageService) UpdateName(ctx context.Context, storageIdentifier, name string) error {
+	return s.update("Storage.UpdateName", storageIdentifier, func(storage *govpsie.StorageDetail) error {
+		storage.Name = name
+		return nil
+	})
+}
+
+func (s *storageService) UpdateSize(ctx context.Context, storageIdentifier, size string) error {
+	return s.update("Storage.UpdateSize", storageIdentifier, func(storage *govpsie.StorageDetail) error {
+		newSize, err := strconv.Atoi(size)
+		if err != nil {
+			return Error(http.StatusBadRequest, "invalid size "+size)
+		}
+		if newSize < storage.Size {
+			return Error(http.StatusBadRequest, "storage cannot be shrunk")
+		}
+		storage.Size = newSize
+		return nil
+	})
+}
+
+func (s *storageService) AttachToServer(ctx context.Context, storageIdentifier, vmIdentifier string, vmType string) error {
+	return s.update("Storage.AttachToServer", storageIdentifier, func(storage *govpsie.StorageDetail) error {
+		server, err := s.b.server(vmIdentifier)
+		if err != nil {
+			return err
+		}
+		if storage.VMIdentifier != "" {
+			return Error(http.StatusConflict, "storage is already attached")
+		}
+		storage.VMIdentifier = vmIdentifier
+		storage.Hostname = server.Hostname
+		storage.BoxID = int(server.ID)
+		storage.EntityType = vmType
+		return nil
+	})
+}
+
+func (s *storageService) DetachToServer(ctx context.Context, storageIdentifier, vmIdentifier string, vmType string) error {
+	return s.update("Storage.DetachToServer", storageIdentifier, func(storage *govpsie.StorageDetail) error {
+		if storage.VMIdentifier != vmIdentifier {
+			return Error(http.StatusBadRequest, "storage is not attached to "+vmIdentifier)
+		}
+		storage.VMIdentifier = ""
+		storage.Hostname = ""
+		storage.BoxID = 0
+		return nil
+	})
+}
+
+func (s *storageService) CreateSnapshot(ctx context.Context, storageIdentifier, name, storageType string) error {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("Storage.CreateSnapshot"); err != nil {
+		return err
+	}
+
+	storage, err := s.b.storage(storageIdentifier)
+	if err != nil {
+		return err
+	}
+
+	id, identifier := s.b.identifier("storage-snapshot")
+	s.b.storageSnaps.put(identifier, &govpsie.StorageSnapShot{
+		ID:          int(id),
+		StorageID:   storage.ID,
+		Identifier:  identifier,
+		Name:        name,
+		Size:        storage.Size,
+		CreatedOn:   time.Now().UTC(),
+		UserID:      1,
+		StorageName: storage.Name,
+		StorageType: storageType,
+		DiskFormat:  storage.DiskFormat,
+		BoxID:       storage.BoxID,
+		EntityType:  storage.EntityType,
+	})
+
+	return nil
+}
+
+func (s *storageService) ListSnapshots(ctx context.Context, options *govpsie.ListOptions) ([]govpsie.StorageSnapShot, error) {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("Storage.ListSnapshots"); err != nil {
+		return nil, err
+	}
+
+	snapshots := []govpsie.StorageSnapShot{}
+	s.b.storageSnaps.each(func(id string, snapshot *govpsie.StorageSnapShot) {
+		snapshots = append(snapshots, *snapshot)
+	})
+
+	return snapshots, nil
+}
+
+func (s *storageService) UpdateSnapshotName(ctx context.Context, snapshotIdentifier, name string) error {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("Storage.UpdateSnapshotName"); err != nil {
+		return err
+	}
+
+	snapshot, ok := s.b.storageSnaps.get(snapshotIdentifier)
+	if !ok {
+		return notFound("storage snapshot", snapshotIdentifier)
+	}
+	snapshot.Name = name
+
+	return nil
+}
+
+func (s *storageService) DeleteSnapshot(ctx context.Context, snapshotIdentifier string) error {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call("Storage.DeleteSnapshot"); err != nil {
+		return err
+	}
+
+	if !s.b.storageSnaps.delete(snapshotIdentifier) {
+		return notFound("storage snapshot", snapshotIdentifier)
+	}
+	return nil
+}
+
+func (s *storageService) update(method, identifier string, fn func(storage *govpsie.StorageDetail) error) error {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if err := s.b.call(method); err != nil {
+		return err
+	}
+
+	storage, err := s.b.storage(identifier)
+	if err != nil {
+		return err
+	}
+	if err := fn(storage); err != nil {
+		return err
+	}
+	storage.UpdatedAt = time.Now().UTC()
+
+	return nil
+}
+
+func (b *Backend) storage(identifier string) (*govpsie.StorageDetail, error) {
+	storage, ok := b.storages.get(identifier)
+	if !ok {
+		return nil, notFound("storage", identifier)
+	}
+	return storage, nil
+}
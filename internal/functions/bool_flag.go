@@ -0,0 +1,51 @@
+// Package functions implements the provider-defined functions, called in
+// configuration as provider::vpsie::<name>.
+package functions
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+var _ function.Function = &boolFlagFunction{}
+
+type boolFlagFunction struct{}
+
+func NewBoolFlagFunction() function.Function {
+	return &boolFlagFunction{}
+}
+
+func (f *boolFlagFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "bool_flag"
+}
+
+func (f *boolFlagFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Convert a bool to a 0/1 flag.",
+		MarkdownDescription: "Returns `1` for `true` and `0` for `false`, for the integer flags that VPSie resources take, " +
+			"such as `backup_enabled`, `add_public_ip_v4` or `auto_generate`.",
+		Parameters: []function.Parameter{
+			function.BoolParameter{
+				Name:                "value",
+				MarkdownDescription: "The value to convert.",
+			},
+		},
+		Return: function.Int64Return{},
+	}
+}
+
+func (f *boolFlagFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var value bool
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &value))
+	if resp.Error != nil {
+		return
+	}
+
+	var flag int64
+	if value {
+		flag = 1
+	}
+
+	resp.Error = resp.Result.Set(ctx, flag)
+}
@@ -0,0 +1,28 @@
+package functions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestUnitBoolFlagFunction(t *testing.T) {
+	for value, expected := range map[bool]int64{true: 1, false: 0} {
+		resp := &function.RunResponse{
+			Result: function.NewResultData(types.Int64Unknown()),
+		}
+		NewBoolFlagFunction().Run(context.Background(), function.RunRequest{
+			Arguments: function.NewArgumentsData([]attr.Value{types.BoolValue(value)}),
+		}, resp)
+
+		if resp.Error != nil {
+			t.Fatalf("unexpected error: %s", resp.Error)
+		}
+		if got := resp.Result.Value(); !got.Equal(types.Int64Value(expected)) {
+			t.Fatalf("bool_flag(%t): expected %d, got %s", value, expected, got)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package functions_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/vpsie/terraform-provider-vpsie/internal/acctest"
+	"github.com/vpsie/terraform-provider-vpsie/internal/fakeapi"
+)
+
+// TestUnitFunctions_Registered calls each function through the provider, as
+// Terraform does.
+func TestUnitFunctions_Registered(t *testing.T) {
+	server, _ := acctest.FakeProtoV6Server(t, fakeapi.New(), nil)
+
+	tests := []struct {
+		name      string
+		arguments []tftypes.Value
+		expected  tftypes.Value
+	}{
+		{
+			name:      "bool_flag",
+			arguments: []tftypes.Value{tftypes.NewValue(tftypes.Bool, true)},
+			expected:  tftypes.NewValue(tftypes.Number, 1),
+		},
+		{
+			name:      "vpc_host_ip",
+			arguments: []tftypes.Value{tftypes.NewValue(tftypes.String, "10.0.0.0"), tftypes.NewValue(tftypes.Number, 3)},
+			expected:  tftypes.NewValue(tftypes.String, "10.0.0.3"),
+		},
+		{
+			name:      "parse_dns_record_id",
+			arguments: []tftypes.Value{tftypes.NewValue(tftypes.String, "dom-1/MX/mail")},
+			expected: tftypes.NewValue(tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+				"domain_identifier": tftypes.String,
+				"type":              tftypes.String,
+				"name":              tftypes.String,
+			}}, map[string]tftypes.Value{
+				"domain_identifier": tftypes.NewValue(tftypes.String, "dom-1"),
+				"type":              tftypes.NewValue(tftypes.String, "MX"),
+				"name":              tftypes.NewValue(tftypes.String, "mail"),
+			}),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			arguments := make([]*tfprotov6.DynamicValue, len(tt.arguments))
+			for i, argument := range tt.arguments {
+				arguments[i] = acctest.DynamicValue(t, argument.Type(), argument)
+			}
+
+			resp, err := server.CallFunction(context.Background(), &tfprotov6.CallFunctionRequest{
+				Name:      tt.name,
+				Arguments: arguments,
+			})
+			if err != nil {
+				t.Fatalf("CallFunction: %v", err)
+			}
+			if resp.Error != nil {
+				t.Fatalf("CallFunction: %s", resp.Error.Text)
+			}
+
+			got, err := resp.Result.Unmarshal(tt.expected.Type())
+			if err != nil {
+				t.Fatalf("Unmarshal: %v", err)
+			}
+			if !got.Equal(tt.expected) {
+				t.Fatalf("expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
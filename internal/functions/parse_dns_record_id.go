@@ -0,0 +1,71 @@
+package functions
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/vpsie/terraform-provider-vpsie/internal/services/domain"
+)
+
+var _ function.Function = &parseDnsRecordIDFunction{}
+
+var dnsRecordIDAttrTypes = map[string]attr.Type{
+	"domain_identifier": types.StringType,
+	"type":              types.StringType,
+	"name":              types.StringType,
+}
+
+type parseDnsRecordIDFunction struct{}
+
+func NewParseDnsRecordIDFunction() function.Function {
+	return &parseDnsRecordIDFunction{}
+}
+
+func (f *parseDnsRecordIDFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "parse_dns_record_id"
+}
+
+func (f *parseDnsRecordIDFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Split a vpsie_dns_record ID into its parts.",
+		MarkdownDescription: "Splits a `vpsie_dns_record` ID, of the form `<domain_identifier>/<type>/<name>`, into an object " +
+			"with `domain_identifier`, `type` and `name` attributes.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "id",
+				MarkdownDescription: "The DNS record ID, such as the `id` of a `vpsie_dns_record` or its import ID.",
+			},
+		},
+		Return: function.ObjectReturn{
+			AttributeTypes: dnsRecordIDAttrTypes,
+		},
+	}
+}
+
+func (f *parseDnsRecordIDFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var id string
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &id))
+	if resp.Error != nil {
+		return
+	}
+
+	domainIdentifier, recordType, name, err := domain.ParseDnsRecordID(id)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+
+	result, diags := types.ObjectValue(dnsRecordIDAttrTypes, map[string]attr.Value{
+		"domain_identifier": types.StringValue(domainIdentifier),
+		"type":              types.StringValue(recordType),
+		"name":              types.StringValue(name),
+	})
+	resp.Error = function.FuncErrorFromDiags(ctx, diags)
+	if resp.Error != nil {
+		return
+	}
+
+	resp.Error = resp.Result.Set(ctx, result)
+}
@@ -0,0 +1,65 @@
+package functions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestUnitParseDnsRecordIDFunction(t *testing.T) {
+	tests := []struct {
+		name      string
+		id        string
+		expected  map[string]attr.Value
+		expectErr bool
+	}{
+		{
+			name: "valid id",
+			id:   "dom-1/A/www",
+			expected: map[string]attr.Value{
+				"domain_identifier": types.StringValue("dom-1"),
+				"type":              types.StringValue("A"),
+				"name":              types.StringValue("www"),
+			},
+		},
+		{
+			name:      "missing part",
+			id:        "dom-1/A",
+			expectErr: true,
+		},
+		{
+			name:      "empty part",
+			id:        "dom-1//www",
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &function.RunResponse{
+				Result: function.NewResultData(types.ObjectUnknown(dnsRecordIDAttrTypes)),
+			}
+			NewParseDnsRecordIDFunction().Run(context.Background(), function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{types.StringValue(tt.id)}),
+			}, resp)
+
+			if tt.expectErr {
+				if resp.Error == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if resp.Error != nil {
+				t.Fatalf("unexpected error: %s", resp.Error)
+			}
+
+			expected := types.ObjectValueMust(dnsRecordIDAttrTypes, tt.expected)
+			if !resp.Result.Value().Equal(expected) {
+				t.Fatalf("expected %s, got %s", expected, resp.Result.Value())
+			}
+		})
+	}
+}
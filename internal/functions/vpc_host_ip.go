@@ -0,0 +1,106 @@
+package functions
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net/netip"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+var _ function.Function = &vpcHostIPFunction{}
+
+type vpcHostIPFunction struct{}
+
+func NewVpcHostIPFunction() function.Function {
+	return &vpcHostIPFunction{}
+}
+
+func (f *vpcHostIPFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "vpc_host_ip"
+}
+
+func (f *vpcHostIPFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Compute the address of a host in a VPC network.",
+		MarkdownDescription: "Returns the address of host number `n` in a VPC network, like the built-in `cidrhost` function. " +
+			"The network range may be given the way `vpsie_vpc` stores it, as a bare network address such as `10.0.0.0`, " +
+			"or in CIDR notation such as `10.0.0.0/24`. Only CIDR notation lets the function check that the address falls " +
+			"inside the network, and lets a negative `n` count back from the end of it.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "network_range",
+				MarkdownDescription: "The network address of the VPC, optionally followed by a prefix length.",
+			},
+			function.Int64Parameter{
+				Name:                "n",
+				MarkdownDescription: "The host number. `0` is the network address itself.",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *vpcHostIPFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var networkRange string
+	var n int64
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &networkRange, &n))
+	if resp.Error != nil {
+		return
+	}
+
+	ip, err := vpcHostIP(networkRange, n)
+	if err != nil {
+		resp.Error = function.NewFuncError(err.Error())
+		return
+	}
+
+	resp.Error = resp.Result.Set(ctx, ip)
+}
+
+// vpcHostIP returns host n of networkRange, which is either a CIDR prefix or
+// a bare network address.
+func vpcHostIP(networkRange string, n int64) (string, error) {
+	var base netip.Addr
+	hostBits := -1
+
+	if strings.Contains(networkRange, "/") {
+		prefix, err := netip.ParsePrefix(networkRange)
+		if err != nil {
+			return "", fmt.Errorf("invalid network range %q: %s", networkRange, err)
+		}
+		base = prefix.Masked().Addr()
+		hostBits = base.BitLen() - prefix.Bits()
+	} else {
+		addr, err := netip.ParseAddr(networkRange)
+		if err != nil {
+			return "", fmt.Errorf("invalid network range %q: %s", networkRange, err)
+		}
+		if n < 0 {
+			return "", fmt.Errorf("a negative host number needs the network range in CIDR notation, got %q", networkRange)
+		}
+		base = addr
+	}
+
+	offset := big.NewInt(n)
+	if hostBits >= 0 {
+		size := new(big.Int).Lsh(big.NewInt(1), uint(hostBits))
+		if n < 0 {
+			offset.Add(offset, size)
+		}
+		if offset.Sign() < 0 || offset.Cmp(size) >= 0 {
+			return "", fmt.Errorf("host number %d is outside network %s", n, networkRange)
+		}
+	}
+
+	host := new(big.Int).SetBytes(base.AsSlice())
+	host.Add(host, offset)
+	if host.BitLen() > base.BitLen() {
+		return "", fmt.Errorf("host number %d is outside the address space of %s", n, networkRange)
+	}
+
+	addr, _ := netip.AddrFromSlice(host.FillBytes(make([]byte, base.BitLen()/8)))
+	return addr.String(), nil
+}
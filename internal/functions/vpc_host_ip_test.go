@@ -0,0 +1,67 @@
+package functions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestUnitVpcHostIP(t *testing.T) {
+	tests := []struct {
+		name         string
+		networkRange string
+		n            int64
+		expected     string
+		expectErr    bool
+	}{
+		{name: "bare address", networkRange: "10.0.0.0", n: 5, expected: "10.0.0.5"},
+		{name: "bare address carries over", networkRange: "10.0.0.0", n: 300, expected: "10.0.1.44"},
+		{name: "cidr", networkRange: "192.168.16.0/20", n: 258, expected: "192.168.17.2"},
+		{name: "cidr with host bits", networkRange: "10.0.0.7/24", n: 1, expected: "10.0.0.1"},
+		{name: "cidr from the end", networkRange: "10.0.0.0/24", n: -2, expected: "10.0.0.254"},
+		{name: "ipv6", networkRange: "fd00::/64", n: 16, expected: "fd00::10"},
+		{name: "outside network", networkRange: "10.0.0.0/24", n: 256, expectErr: true},
+		{name: "negative outside network", networkRange: "10.0.0.0/24", n: -257, expectErr: true},
+		{name: "negative bare address", networkRange: "10.0.0.0", n: -1, expectErr: true},
+		{name: "outside address space", networkRange: "255.255.255.255", n: 1, expectErr: true},
+		{name: "invalid address", networkRange: "10.0.0", n: 1, expectErr: true},
+		{name: "invalid prefix", networkRange: "10.0.0.0/33", n: 1, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := vpcHostIP(tt.networkRange, tt.n)
+			if tt.expectErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %s", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.expected {
+				t.Fatalf("expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestUnitVpcHostIPFunction_Run(t *testing.T) {
+	resp := &function.RunResponse{
+		Result: function.NewResultData(types.StringUnknown()),
+	}
+	NewVpcHostIPFunction().Run(context.Background(), function.RunRequest{
+		Arguments: function.NewArgumentsData([]attr.Value{types.StringValue("10.1.0.0/16"), types.Int64Value(10)}),
+	}, resp)
+
+	if resp.Error != nil {
+		t.Fatalf("unexpected error: %s", resp.Error)
+	}
+	if got := resp.Result.Value(); !got.Equal(types.StringValue("10.1.0.10")) {
+		t.Fatalf("expected 10.1.0.10, got %s", got)
+	}
+}
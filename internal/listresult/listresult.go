@@ -0,0 +1,76 @@
+// Package listresult builds the results that list resources return to
+// terraform query. Each result carries the identity of a remote object and,
+// when Terraform asks for it, the resource data an import of that identity
+// would produce, so that generated configuration matches what an import
+// block followed by a plan would see.
+package listresult
+
+import (
+	"context"
+	"iter"
+
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+)
+
+// New returns the result for one remote object, identified by identity. When
+// req.IncludeResource is set, the resource data is filled in by passing the
+// identity through r's ImportState and Read, exactly as an import does.
+func New(ctx context.Context, req list.ListRequest, r resource.ResourceWithImportState, displayName string, identity any) list.ListResult {
+	result := req.NewListResult(ctx)
+	result.DisplayName = displayName
+
+	result.Diagnostics.Append(result.Identity.Set(ctx, identity)...)
+	if result.Diagnostics.HasError() || !req.IncludeResource {
+		return result
+	}
+
+	importResp := resource.ImportStateResponse{
+		State:    tfsdk.State{Schema: result.Resource.Schema, Raw: result.Resource.Raw.Copy()},
+		Identity: copyIdentity(result.Identity),
+	}
+	r.ImportState(ctx, resource.ImportStateRequest{Identity: copyIdentity(result.Identity)}, &importResp)
+	result.Diagnostics.Append(importResp.Diagnostics...)
+	if result.Diagnostics.HasError() {
+		return result
+	}
+
+	readResp := resource.ReadResponse{
+		State:    importResp.State,
+		Identity: copyIdentity(importResp.Identity),
+	}
+	r.Read(ctx, resource.ReadRequest{State: importResp.State, Identity: copyIdentity(importResp.Identity)}, &readResp)
+	result.Diagnostics.Append(readResp.Diagnostics...)
+	if result.Diagnostics.HasError() {
+		return result
+	}
+
+	result.Resource = &tfsdk.Resource{Schema: readResp.State.Schema, Raw: readResp.State.Raw}
+	return result
+}
+
+// Stream returns the results for items, in order, built by result. It stops
+// once req.Limit results have been returned or Terraform stops reading.
+func Stream[T any](req list.ListRequest, items []T, result func(T) list.ListResult) iter.Seq[list.ListResult] {
+	return func(push func(list.ListResult) bool) {
+		for i, item := range items {
+			if req.Limit > 0 && int64(i) >= req.Limit {
+				return
+			}
+			if !push(result(item)) {
+				return
+			}
+		}
+	}
+}
+
+func copyIdentity(identity *tfsdk.ResourceIdentity) *tfsdk.ResourceIdentity {
+	if identity == nil {
+		return nil
+	}
+	return &tfsdk.ResourceIdentity{
+		Schema: identity.Schema,
+		Raw:    identity.Raw.Copy(),
+	}
+}
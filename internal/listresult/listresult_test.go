@@ -0,0 +1,46 @@
+package listresult
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/list"
+)
+
+func TestUnitStream_Limit(t *testing.T) {
+	items := []string{"a", "b", "c"}
+	build := func(item string) list.ListResult {
+		return list.ListResult{DisplayName: item}
+	}
+
+	for _, tc := range []struct {
+		limit int64
+		want  int
+	}{
+		{limit: 0, want: 3},
+		{limit: 2, want: 2},
+		{limit: 5, want: 3},
+	} {
+		var got []string
+		for result := range Stream(list.ListRequest{Limit: tc.limit}, items, build) {
+			got = append(got, result.DisplayName)
+		}
+		if len(got) != tc.want {
+			t.Errorf("limit %d: expected %d results, got %q", tc.limit, tc.want, got)
+		}
+	}
+}
+
+func TestUnitStream_StopsWhenTerraformStopsReading(t *testing.T) {
+	built := 0
+	build := func(item string) list.ListResult {
+		built++
+		return list.ListResult{DisplayName: item}
+	}
+
+	for range Stream(list.ListRequest{}, []string{"a", "b", "c"}, build) {
+		break
+	}
+	if built != 1 {
+		t.Errorf("expected one result to be built, got %d", built)
+	}
+}
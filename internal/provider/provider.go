@@ -5,10 +5,20 @@ package provider
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"net/url"
 	"os"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/action"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/list"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
@@ -17,6 +27,10 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/vpsie/govpsie"
+	"github.com/vpsie/terraform-provider-vpsie/internal/apierror"
+	"github.com/vpsie/terraform-provider-vpsie/internal/deletion"
+	"github.com/vpsie/terraform-provider-vpsie/internal/functions"
+	"github.com/vpsie/terraform-provider-vpsie/internal/providerdata"
 	"github.com/vpsie/terraform-provider-vpsie/internal/services/accesstoken"
 	"github.com/vpsie/terraform-provider-vpsie/internal/services/backup"
 	"github.com/vpsie/terraform-provider-vpsie/internal/services/bucket"
@@ -37,11 +51,19 @@ import (
 	"github.com/vpsie/terraform-provider-vpsie/internal/services/sshkey"
 	"github.com/vpsie/terraform-provider-vpsie/internal/services/storage"
 	"github.com/vpsie/terraform-provider-vpsie/internal/services/vpc"
+	"github.com/vpsie/terraform-provider-vpsie/internal/tags"
+	"github.com/vpsie/terraform-provider-vpsie/internal/transport"
 	"golang.org/x/oauth2"
 )
 
 // Ensure VpsieProvider satisfies various provider interfaces.
-var _ provider.Provider = &VpsieProvider{}
+var (
+	_ provider.Provider                       = &VpsieProvider{}
+	_ provider.ProviderWithEphemeralResources = &VpsieProvider{}
+	_ provider.ProviderWithFunctions          = &VpsieProvider{}
+	_ provider.ProviderWithActions            = &VpsieProvider{}
+	_ provider.ProviderWithListResources      = &VpsieProvider{}
+)
 
 const (
 	userAgent = "vpsie-terraform-provider/1.0.0"
@@ -53,11 +75,26 @@ type VpsieProvider struct {
 	// provider is built and ran locally, and "test" when running acceptance
 	// testing.
 	version string
+
+	// client, when set, is used instead of a client built from the
+	// provider configuration.
+	client *govpsie.Client
 }
 
 // VpsieProviderModel describes the provider data model.
 type VpsieProviderModel struct {
-	AccessToken types.String `tfsdk:"access_token"`
+	AccessToken  types.String `tfsdk:"access_token"`
+	ApiURL       types.String `tfsdk:"api_url"`
+	DefaultTags  types.Set    `tfsdk:"default_tags"`
+	MaxRetries   types.Int64  `tfsdk:"max_retries"`
+	RetryMaxWait types.Int64  `tfsdk:"retry_max_wait"`
+	ReadCacheTTL types.Int64  `tfsdk:"read_cache_ttl"`
+
+	MaxConcurrentRequests types.Int64 `tfsdk:"max_concurrent_requests"`
+
+	DeletePassword types.String `tfsdk:"delete_password"`
+	DeleteReason   types.String `tfsdk:"delete_reason"`
+	DeleteNote     types.String `tfsdk:"delete_note"`
 }
 
 func (p *VpsieProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -78,6 +115,77 @@ func (p *VpsieProvider) Schema(ctx context.Context, req provider.SchemaRequest,
 					stringvalidator.LengthAtLeast(1),
 				},
 			},
+			"api_url": schema.StringAttribute{
+				MarkdownDescription: "Base URL of the VPSie API, for example to target a staging region, a white-label deployment or a local test server. " +
+					"Can also be set with the `VPSIE_API_URL` environment variable. Defaults to the public VPSie API.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"max_retries": schema.Int64Attribute{
+				MarkdownDescription: fmt.Sprintf("Maximum number of times an API request is retried after a rate limit (429), server error (5xx) or network error. "+
+					"Only idempotent requests are retried. Set to `0` to disable retries. Defaults to `%d`.", transport.DefaultMaxRetries),
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"retry_max_wait": schema.Int64Attribute{
+				MarkdownDescription: fmt.Sprintf("Maximum number of seconds to wait between two attempts of an API request, including waits requested by a `Retry-After` header. Defaults to `%d`.",
+					int64(transport.DefaultRetryMaxWait/time.Second)),
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"max_concurrent_requests": schema.Int64Attribute{
+				MarkdownDescription: "Maximum number of API requests the provider has in flight at once, across all resources. " +
+					"Requests beyond the limit wait for a free slot, and status polls wait behind requests that change infrastructure. " +
+					"Unlimited when not set.",
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"read_cache_ttl": schema.Int64Attribute{
+				MarkdownDescription: fmt.Sprintf("Number of seconds an API read is reused by other resources of the same run. "+
+					"Concurrent reads of the same list share one request, and any change made through a service drops its cached reads. "+
+					"Set to `0` to disable the cache. Defaults to `%d`.", int64(transport.DefaultCacheTTL/time.Second)),
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"delete_password": schema.StringAttribute{
+				MarkdownDescription: "Password sent to confirm the deletion of a `vpsie_server` that does not set its own `password`. " +
+					"Unlike the resource attribute, it is never written to state. Can also be set with the `VPSIE_DELETE_PASSWORD` environment variable.",
+				Optional:  true,
+				Sensitive: true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"delete_reason": schema.StringAttribute{
+				MarkdownDescription: "Reason sent to the API when a server or bucket is destroyed and the resource does not set its own `delete_reason`. " +
+					"Can also be set with the `VPSIE_DELETE_REASON` environment variable.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"delete_note": schema.StringAttribute{
+				MarkdownDescription: "Note sent to the API when a server or bucket is destroyed and the resource does not set its own `delete_note`.",
+				Optional:            true,
+			},
+			"default_tags": schema.SetAttribute{
+				MarkdownDescription: "Tags added to every `vpsie_server`, in addition to the server's own `tags`. Other resources do not receive them.",
+				Optional:            true,
+				ElementType:         types.StringType,
+				Validators: []validator.Set{
+					setvalidator.ValueStringsAre(stringvalidator.LengthAtLeast(1)),
+				},
+			},
 		},
 	}
 }
@@ -100,10 +208,55 @@ func (p *VpsieProvider) Configure(ctx context.Context, req provider.ConfigureReq
 		)
 	}
 
+	if data.ApiURL.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("api_url"),
+			"Unknown Vpsie API URL",
+			"The provider cannot create the Vpsie API client as there is an unknown configuration value for the Vpsie API URL. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the VPSIE_API_URL environment variable.",
+		)
+	}
+
+	if data.DefaultTags.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("default_tags"),
+			"Unknown Vpsie Default Tags",
+			"The provider cannot apply default tags as there is an unknown configuration value for default_tags. "+
+				"Either target apply the source of the value first or set the value statically in the configuration.",
+		)
+	}
+
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	var defaultTags []string
+	if !data.DefaultTags.IsNull() {
+		resp.Diagnostics.Append(data.DefaultTags.ElementsAs(ctx, &defaultTags, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	providerData := &providerdata.Data{
+		DefaultTags: tags.Merge(nil, defaultTags),
+		Deletion: deletion.Settings{
+			Password: deletion.Pick(data.DeletePassword.ValueString(), os.Getenv("VPSIE_DELETE_PASSWORD")),
+			Reason:   deletion.Pick(data.DeleteReason.ValueString(), os.Getenv("VPSIE_DELETE_REASON")),
+			Note:     data.DeleteNote.ValueString(),
+		},
+	}
+
+	if p.client != nil {
+		providerData.Client = p.client
+		resp.DataSourceData = providerData
+		resp.ResourceData = providerData
+		resp.EphemeralResourceData = providerData
+		resp.ActionData = providerData
+		resp.ListResourceData = providerData
+		return
+	}
+
 	accessToken := os.Getenv("VPSIE_ACCESS_TOKEN")
 
 	if !data.AccessToken.IsNull() {
@@ -124,19 +277,89 @@ func (p *VpsieProvider) Configure(ctx context.Context, req provider.ConfigureReq
 		return
 	}
 
-	tflog.Debug(ctx, "Creating Vpsie client")
+	apiURL := os.Getenv("VPSIE_API_URL")
+
+	if !data.ApiURL.IsNull() {
+		apiURL = data.ApiURL.ValueString()
+	}
+
+	if apiURL != "" {
+		if u, err := url.Parse(apiURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("api_url"),
+				"Invalid Vpsie API URL",
+				fmt.Sprintf("The Vpsie API URL must be an absolute http or https URL such as https://api.vpsie.com, got %q.", apiURL),
+			)
+			return
+		}
+	}
+
+	maxRetries := transport.DefaultMaxRetries
+	if !data.MaxRetries.IsNull() && !data.MaxRetries.IsUnknown() {
+		maxRetries = int(data.MaxRetries.ValueInt64())
+	}
+
+	retryMaxWait := transport.DefaultRetryMaxWait
+	if !data.RetryMaxWait.IsNull() && !data.RetryMaxWait.IsUnknown() {
+		retryMaxWait = time.Duration(data.RetryMaxWait.ValueInt64()) * time.Second
+	}
+
+	readCacheTTL := transport.DefaultCacheTTL
+	if !data.ReadCacheTTL.IsNull() && !data.ReadCacheTTL.IsUnknown() {
+		readCacheTTL = time.Duration(data.ReadCacheTTL.ValueInt64()) * time.Second
+	}
 
-	client := govpsie.NewClient(oauth2.NewClient(context.Background(), nil))
+	tflog.Debug(ctx, "Creating Vpsie client", map[string]any{
+		"max_retries":             maxRetries,
+		"retry_max_wait":          retryMaxWait.String(),
+		"read_cache_ttl":          readCacheTTL.String(),
+		"max_concurrent_requests": data.MaxConcurrentRequests.ValueInt64(),
+	})
+
+	httpClient := oauth2.NewClient(context.Background(), nil)
+
+	// Each retry attempt takes its own slot under max_concurrent_requests,
+	// so backoff waits do not hold one.
+	httpTransport := httpClient.Transport
+	if !data.MaxConcurrentRequests.IsNull() && !data.MaxConcurrentRequests.IsUnknown() {
+		httpTransport = transport.NewLimit(httpTransport, int(data.MaxConcurrentRequests.ValueInt64()))
+	}
+
+	client := govpsie.NewClient(&http.Client{
+		Transport: apierror.NewTransport(
+			transport.NewCache(transport.NewRetry(httpTransport, maxRetries, retryMaxWait), readCacheTTL),
+		),
+		CheckRedirect: httpClient.CheckRedirect,
+		Jar:           httpClient.Jar,
+		Timeout:       httpClient.Timeout,
+	})
 
 	client.SetUserAgent(userAgent)
 	client.SetRequestHeaders(map[string]string{
 		"Vpsie-Auth": accessToken,
 	})
 
+	if apiURL != "" {
+		if err := client.SetBaseURL(apiURL); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("api_url"),
+				"Invalid Vpsie API URL",
+				"The provider cannot use the configured Vpsie API URL: "+err.Error(),
+			)
+			return
+		}
+		tflog.Debug(ctx, "Using custom Vpsie API URL", map[string]any{"api_url": apiURL})
+	}
+
+	providerData.Client = client
+
 	// Make the HashiCups client available during DataSource and Resource
 	// type Configure methods.
-	resp.DataSourceData = client
-	resp.ResourceData = client
+	resp.DataSourceData = providerData
+	resp.ResourceData = providerData
+	resp.EphemeralResourceData = providerData
+	resp.ActionData = providerData
+	resp.ListResourceData = providerData
 
 	tflog.Info(ctx, "Vpsie client created", map[string]any{"success": true})
 }
@@ -169,6 +392,8 @@ func (p *VpsieProvider) Resources(ctx context.Context) []func() resource.Resourc
 		monitoring.NewMonitoringRuleResource,
 		accesstoken.NewAccessTokenResource,
 		firewall.NewFirewallAttachmentResource,
+		firewall.NewFirewallRuleResource,
+		firewall.NewFirewallGroupMembersResource,
 		vpc.NewVpcServerAssignmentResource,
 	}
 }
@@ -202,6 +427,42 @@ func (p *VpsieProvider) DataSources(ctx context.Context) []func() datasource.Dat
 	}
 }
 
+func (p *VpsieProvider) EphemeralResources(ctx context.Context) []func() ephemeral.EphemeralResource {
+	return []func() ephemeral.EphemeralResource{
+		accesstoken.NewAccessTokenEphemeralResource,
+	}
+}
+
+func (p *VpsieProvider) Actions(ctx context.Context) []func() action.Action {
+	return []func() action.Action{
+		server.NewServerRebootAction,
+		server.NewServerPowerAction,
+		snapshot.NewServerSnapshotNowAction,
+		backup.NewServerBackupNowAction,
+	}
+}
+
+func (p *VpsieProvider) ListResources(ctx context.Context) []func() list.ListResource {
+	return []func() list.ListResource{
+		server.NewServerListResource,
+		storage.NewStorageListResource,
+		firewall.NewFirewallListResource,
+		domain.NewDomainListResource,
+		vpc.NewVpcListResource,
+		loadbalancer.NewLoadbalancerListResource,
+		kubernetes.NewKubernetesListResource,
+		bucket.NewBucketListResource,
+	}
+}
+
+func (p *VpsieProvider) Functions(ctx context.Context) []func() function.Function {
+	return []func() function.Function{
+		functions.NewParseDnsRecordIDFunction,
+		functions.NewVpcHostIPFunction,
+		functions.NewBoolFlagFunction,
+	}
+}
+
 func New(version string) func() provider.Provider {
 	return func() provider.Provider {
 		return &VpsieProvider{
@@ -209,3 +470,15 @@ func New(version string) func() provider.Provider {
 		}
 	}
 }
+
+// NewWithClient returns a provider that talks to the API through client
+// regardless of its configuration, such as a client from the fakeapi
+// package. The access token and API URL settings are not required.
+func NewWithClient(version string, client *govpsie.Client) func() provider.Provider {
+	return func() provider.Provider {
+		return &VpsieProvider{
+			version: version,
+			client:  client,
+		}
+	}
+}
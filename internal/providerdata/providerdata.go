@@ -0,0 +1,22 @@
+// Package providerdata defines what the provider hands to its resources,
+// data sources, ephemeral resources, actions and list resources when they
+// are configured.
+package providerdata
+
+import (
+	"github.com/vpsie/govpsie"
+	"github.com/vpsie/terraform-provider-vpsie/internal/deletion"
+)
+
+// Data is the provider data of a configured provider.
+type Data struct {
+	// Client calls the VPSie API.
+	Client *govpsie.Client
+
+	// DefaultTags are the provider's default_tags, sorted and without
+	// duplicates.
+	DefaultTags []string
+
+	// Deletion holds the provider-level deletion settings.
+	Deletion deletion.Settings
+}
@@ -0,0 +1,224 @@
+package accesstoken
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/vpsie/govpsie"
+	"github.com/vpsie/terraform-provider-vpsie/internal/apierror"
+	"github.com/vpsie/terraform-provider-vpsie/internal/providerdata"
+)
+
+const (
+	expirationDateLayout = "2006-01-02"
+
+	// privateIdentifierKey is the private data key holding the identifier
+	// of the token to revoke in Close.
+	privateIdentifierKey = "identifier"
+)
+
+var expirationDateRegexp = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+
+var (
+	_ ephemeral.EphemeralResource              = &accessTokenEphemeralResource{}
+	_ ephemeral.EphemeralResourceWithConfigure = &accessTokenEphemeralResource{}
+	_ ephemeral.EphemeralResourceWithClose     = &accessTokenEphemeralResource{}
+)
+
+type accessTokenEphemeralResource struct {
+	client AccessTokenAPI
+}
+
+type accessTokenEphemeralResourceModel struct {
+	Identifier     types.String `tfsdk:"identifier"`
+	Name           types.String `tfsdk:"name"`
+	AccessToken    types.String `tfsdk:"access_token"`
+	ExpirationDate types.String `tfsdk:"expiration_date"`
+	CreatedOn      types.String `tfsdk:"created_on"`
+}
+
+func NewAccessTokenEphemeralResource() ephemeral.EphemeralResource {
+	return &accessTokenEphemeralResource{}
+}
+
+func (a *accessTokenEphemeralResource) Metadata(_ context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_access_token"
+}
+
+func (a *accessTokenEphemeralResource) Schema(_ context.Context, _ ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Mints a short-lived VPSie access token for the duration of a Terraform run and revokes it when Terraform is done with it. The token is never written to state or plan.",
+		Attributes: map[string]schema.Attribute{
+			"identifier": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The unique identifier of the access token.",
+			},
+			"name": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "The name of the access token. Defaults to a generated name starting with `terraform-`.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"access_token": schema.StringAttribute{
+				Computed:            true,
+				Sensitive:           true,
+				MarkdownDescription: "The generated access token value.",
+			},
+			"expiration_date": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "The expiration date of the access token, in `YYYY-MM-DD` format. Defaults to the next day (UTC). The token is revoked at the end of the run either way.",
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(expirationDateRegexp, "must be a date in YYYY-MM-DD format"),
+				},
+			},
+			"created_on": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The timestamp when the access token was created.",
+			},
+		},
+	}
+}
+
+func (a *accessTokenEphemeralResource) Configure(_ context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*providerdata.Data)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Ephemeral Resource Configure Type",
+			fmt.Sprintf("Expected *providerdata.Data, got %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	a.client = data.Client.AccessToken
+}
+
+func (a *accessTokenEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	var data accessTokenEphemeralResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	secret, err := randomHex(32)
+	if err != nil {
+		resp.Diagnostics.AddError("Error generating access token", err.Error())
+		return
+	}
+
+	if data.Name.IsNull() || data.Name.IsUnknown() {
+		suffix, err := randomHex(4)
+		if err != nil {
+			resp.Diagnostics.AddError("Error generating access token name", err.Error())
+			return
+		}
+		data.Name = types.StringValue("terraform-" + suffix)
+	}
+
+	if data.ExpirationDate.IsNull() || data.ExpirationDate.IsUnknown() {
+		data.ExpirationDate = types.StringValue(time.Now().UTC().AddDate(0, 0, 1).Format(expirationDateLayout))
+	}
+
+	tokens, err := a.client.List(ctx, nil)
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading access tokens", err.Error())
+		return
+	}
+	existing := make(map[string]bool, len(tokens))
+	for _, token := range tokens {
+		existing[token.AccessTokenIdentifier] = true
+	}
+
+	err = a.client.Create(ctx, data.Name.ValueString(), secret, data.ExpirationDate.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Error creating access token", err.Error())
+		return
+	}
+
+	tokens, err = a.client.List(ctx, nil)
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading access token after creation", err.Error())
+		return
+	}
+
+	var token *govpsie.AccessToken
+	for i := range tokens {
+		if tokens[i].Name == data.Name.ValueString() && !existing[tokens[i].AccessTokenIdentifier] {
+			token = &tokens[i]
+			break
+		}
+	}
+	if token == nil {
+		resp.Diagnostics.AddError(
+			"Error reading access token after creation",
+			fmt.Sprintf("access token with name %s not found; it may have to be revoked by hand", data.Name.ValueString()),
+		)
+		return
+	}
+
+	identifier, err := json.Marshal(token.AccessTokenIdentifier)
+	if err != nil {
+		resp.Diagnostics.AddError("Error saving access token identifier", err.Error())
+		return
+	}
+	resp.Diagnostics.Append(resp.Private.SetKey(ctx, privateIdentifierKey, identifier)...)
+
+	data.Identifier = types.StringValue(token.AccessTokenIdentifier)
+	data.AccessToken = types.StringValue(secret)
+	data.CreatedOn = types.StringValue(token.CreatedOn)
+
+	resp.Diagnostics.Append(resp.Result.Set(ctx, &data)...)
+}
+
+// Close revokes the token minted by Open.
+func (a *accessTokenEphemeralResource) Close(ctx context.Context, req ephemeral.CloseRequest, resp *ephemeral.CloseResponse) {
+	raw, diags := req.Private.GetKey(ctx, privateIdentifierKey)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() || raw == nil {
+		return
+	}
+
+	var identifier string
+	if err := json.Unmarshal(raw, &identifier); err != nil {
+		resp.Diagnostics.AddError("Error reading access token identifier", err.Error())
+		return
+	}
+
+	err := a.client.Delete(ctx, identifier)
+	if err != nil {
+		if apierror.IsNotFound(err) {
+			tflog.Debug(ctx, "Access token already revoked", map[string]any{"identifier": identifier})
+			return
+		}
+
+		resp.Diagnostics.AddError(
+			"Error revoking access token",
+			"couldn't revoke access token "+identifier+", unexpected error: "+err.Error(),
+		)
+	}
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
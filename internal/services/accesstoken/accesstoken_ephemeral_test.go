@@ -0,0 +1,67 @@
+package accesstoken_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/vpsie/terraform-provider-vpsie/internal/acctest"
+	"github.com/vpsie/terraform-provider-vpsie/internal/fakeapi"
+)
+
+// TestUnitAccessTokenEphemeral_OpenClose drives the ephemeral resource over
+// the plugin protocol, as Terraform does during a plan, without a CLI.
+func TestUnitAccessTokenEphemeral_OpenClose(t *testing.T) {
+	ctx := context.Background()
+	backend := fakeapi.New()
+	client := backend.Client()
+	server, schemas := acctest.FakeProtoV6Server(t, backend, nil)
+
+	tokenType := schemas.EphemeralResourceSchemas["vpsie_access_token"].ValueType()
+	openResp, err := server.OpenEphemeralResource(ctx, &tfprotov6.OpenEphemeralResourceRequest{
+		TypeName: "vpsie_access_token",
+		Config: acctest.DynamicValue(t, tokenType, acctest.ObjectValue(tokenType, map[string]tftypes.Value{
+			"name": tftypes.NewValue(tftypes.String, "ci-run"),
+		})),
+	})
+	if err != nil || len(openResp.Diagnostics) > 0 {
+		t.Fatalf("OpenEphemeralResource: %v %+v", err, openResp.Diagnostics)
+	}
+
+	result, err := openResp.Result.Unmarshal(tokenType)
+	if err != nil {
+		t.Fatalf("Unmarshal result: %v", err)
+	}
+	var attrs map[string]tftypes.Value
+	if err := result.As(&attrs); err != nil {
+		t.Fatalf("result.As: %v", err)
+	}
+	var secret, expiration string
+	_ = attrs["access_token"].As(&secret)
+	_ = attrs["expiration_date"].As(&expiration)
+	if len(secret) != 64 {
+		t.Errorf("expected a 64 character token, got %q", secret)
+	}
+	if expiration == "" {
+		t.Error("expected a default expiration date")
+	}
+
+	tokens, _ := client.AccessToken.List(ctx, nil)
+	if len(tokens) != 1 || tokens[0].Name != "ci-run" {
+		t.Fatalf("expected one minted token, got %+v", tokens)
+	}
+
+	closeResp, err := server.CloseEphemeralResource(ctx, &tfprotov6.CloseEphemeralResourceRequest{
+		TypeName: "vpsie_access_token",
+		Private:  openResp.Private,
+	})
+	if err != nil || len(closeResp.Diagnostics) > 0 {
+		t.Fatalf("CloseEphemeralResource: %v %+v", err, closeResp.Diagnostics)
+	}
+
+	tokens, _ = client.AccessToken.List(ctx, nil)
+	if len(tokens) != 0 {
+		t.Fatalf("expected the token to be revoked, got %+v", tokens)
+	}
+}
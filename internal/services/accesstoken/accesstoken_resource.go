@@ -7,18 +7,23 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/vpsie/govpsie"
+	"github.com/vpsie/terraform-provider-vpsie/internal/apierror"
+	"github.com/vpsie/terraform-provider-vpsie/internal/providerdata"
 )
 
 var (
 	_ resource.Resource                = &accessTokenResource{}
 	_ resource.ResourceWithConfigure   = &accessTokenResource{}
 	_ resource.ResourceWithImportState = &accessTokenResource{}
+	_ resource.ResourceWithIdentity    = &accessTokenResource{}
 )
 
 type accessTokenResource struct {
@@ -31,6 +36,13 @@ type accessTokenResourceModel struct {
 	AccessToken    types.String `tfsdk:"access_token"`
 	ExpirationDate types.String `tfsdk:"expiration_date"`
 	CreatedOn      types.String `tfsdk:"created_on"`
+
+	AccessTokenWO        types.String `tfsdk:"access_token_wo"`
+	AccessTokenWOVersion types.Int64  `tfsdk:"access_token_wo_version"`
+}
+
+type accessTokenIdentityModel struct {
+	Identifier types.String `tfsdk:"identifier"`
 }
 
 func NewAccessTokenResource() resource.Resource {
@@ -59,16 +71,41 @@ func (a *accessTokenResource) Schema(_ context.Context, _ resource.SchemaRequest
 				},
 			},
 			"access_token": schema.StringAttribute{
-				Required:            true,
+				Optional:            true,
 				Sensitive:           true,
-				MarkdownDescription: "The access token value. Changing this forces a new resource to be created.",
+				MarkdownDescription: "The access token value. Changing this forces a new resource to be created. Exactly one of `access_token` and `access_token_wo` must be set.",
 				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplace(),
+					stringplanmodifier.RequiresReplaceIf(
+						func(ctx context.Context, req planmodifier.StringRequest, resp *stringplanmodifier.RequiresReplaceIfFuncResponse) {
+							// Moving the value to access_token_wo keeps the token.
+							resp.RequiresReplace = !req.ConfigValue.IsNull()
+						},
+						"Changing the access token value forces a new resource to be created.",
+						"Changing the access token value forces a new resource to be created.",
+					),
+				},
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+					stringvalidator.ExactlyOneOf(path.MatchRoot("access_token_wo")),
+					stringvalidator.PreferWriteOnlyAttribute(path.MatchRoot("access_token_wo")),
 				},
+			},
+			"access_token_wo": schema.StringAttribute{
+				Optional:            true,
+				WriteOnly:           true,
+				Sensitive:           true,
+				MarkdownDescription: "Write-only form of `access_token`, which is never stored in state. Requires Terraform 1.11 or later. Change `access_token_wo_version` to replace the token with a new value.",
 				Validators: []validator.String{
 					stringvalidator.LengthAtLeast(1),
 				},
 			},
+			"access_token_wo_version": schema.Int64Attribute{
+				Optional:            true,
+				MarkdownDescription: "Any number; changing it replaces the access token with the current value of `access_token_wo`.",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
 			"expiration_date": schema.StringAttribute{
 				Required:            true,
 				MarkdownDescription: "The expiration date of the access token.",
@@ -87,21 +124,32 @@ func (a *accessTokenResource) Schema(_ context.Context, _ resource.SchemaRequest
 	}
 }
 
+func (a *accessTokenResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"identifier": identityschema.StringAttribute{
+				RequiredForImport: true,
+				Description:       "The unique identifier of the access token.",
+			},
+		},
+	}
+}
+
 func (a *accessTokenResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
 		return
 	}
 
-	client, ok := req.ProviderData.(*govpsie.Client)
+	data, ok := req.ProviderData.(*providerdata.Data)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configuration Type",
-			fmt.Sprintf("Expected *govpsie.Client, got %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *providerdata.Data, got %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 		return
 	}
 
-	a.client = client.AccessToken
+	a.client = data.Client.AccessToken
 }
 
 func (a *accessTokenResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -112,7 +160,19 @@ func (a *accessTokenResource) Create(ctx context.Context, req resource.CreateReq
 		return
 	}
 
-	err := a.client.Create(ctx, plan.Name.ValueString(), plan.AccessToken.ValueString(), plan.ExpirationDate.ValueString())
+	// Write-only values are only available in the configuration.
+	var accessTokenWO types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("access_token_wo"), &accessTokenWO)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	accessToken := plan.AccessToken.ValueString()
+	if !accessTokenWO.IsNull() {
+		accessToken = accessTokenWO.ValueString()
+	}
+
+	err := a.client.Create(ctx, plan.Name.ValueString(), accessToken, plan.ExpirationDate.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError("Error creating access token", err.Error())
 		return
@@ -129,6 +189,8 @@ func (a *accessTokenResource) Create(ctx context.Context, req resource.CreateReq
 
 	diags = resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
+
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, accessTokenIdentityModel{Identifier: plan.Identifier})...)
 }
 
 func (a *accessTokenResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
@@ -163,6 +225,8 @@ func (a *accessTokenResource) Read(ctx context.Context, req resource.ReadRequest
 
 	diags = resp.State.Set(ctx, &state)
 	resp.Diagnostics.Append(diags...)
+
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, accessTokenIdentityModel{Identifier: state.Identifier})...)
 }
 
 func (a *accessTokenResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
@@ -188,13 +252,14 @@ func (a *accessTokenResource) Update(ctx context.Context, req resource.UpdateReq
 
 	state.Name = plan.Name
 	state.ExpirationDate = plan.ExpirationDate
+	state.AccessToken = plan.AccessToken
 
 	diags = resp.State.Set(ctx, &state)
 	resp.Diagnostics.Append(diags...)
 }
 
 func (a *accessTokenResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("identifier"), req, resp)
+	resource.ImportStatePassthroughWithIdentity(ctx, path.Root("identifier"), path.Root("identifier"), req, resp)
 }
 
 func (a *accessTokenResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
@@ -227,5 +292,5 @@ func (a *accessTokenResource) GetTokenByName(ctx context.Context, name string) (
 		}
 	}
 
-	return nil, fmt.Errorf("access token with name %s not found", name)
+	return nil, apierror.NotFoundf("access token with name %s not found", name)
 }
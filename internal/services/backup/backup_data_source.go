@@ -8,7 +8,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 
-	"github.com/vpsie/govpsie"
+	"github.com/vpsie/terraform-provider-vpsie/internal/providerdata"
 )
 
 type backupDataSource struct {
@@ -171,16 +171,16 @@ func (b *backupDataSource) Configure(_ context.Context, req datasource.Configure
 		return
 	}
 
-	client, ok := req.ProviderData.(*govpsie.Client)
+	data, ok := req.ProviderData.(*providerdata.Data)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configuration Type",
-			fmt.Sprintf("Expected *govpsie.Client, got %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *providerdata.Data, got %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	b.client = client.Backup
+	b.client = data.Client.Backup
 }
@@ -3,23 +3,26 @@ package backup
 import (
 	"context"
 	"fmt"
-	"strings"
 
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/vpsie/govpsie"
+	"github.com/vpsie/terraform-provider-vpsie/internal/apierror"
+	"github.com/vpsie/terraform-provider-vpsie/internal/providerdata"
 )
 
 var (
 	_ resource.Resource                = &backupPolicyResource{}
 	_ resource.ResourceWithConfigure   = &backupPolicyResource{}
 	_ resource.ResourceWithImportState = &backupPolicyResource{}
+	_ resource.ResourceWithIdentity    = &backupPolicyResource{}
 )
 
 type backupPolicyResource struct {
@@ -38,6 +41,10 @@ type backupPolicyResourceModel struct {
 	Vms        types.List   `tfsdk:"vms"`
 }
 
+type backupPolicyIdentityModel struct {
+	Identifier types.String `tfsdk:"identifier"`
+}
+
 func NewBackupPolicyResource() resource.Resource {
 	return &backupPolicyResource{}
 }
@@ -124,21 +131,32 @@ func (b *backupPolicyResource) Schema(_ context.Context, _ resource.SchemaReques
 	}
 }
 
+func (b *backupPolicyResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"identifier": identityschema.StringAttribute{
+				RequiredForImport: true,
+				Description:       "The unique identifier of the backup policy.",
+			},
+		},
+	}
+}
+
 func (b *backupPolicyResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
 		return
 	}
 
-	client, ok := req.ProviderData.(*govpsie.Client)
+	data, ok := req.ProviderData.(*providerdata.Data)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configuration Type",
-			fmt.Sprintf("Expected *govpsie.Client, got %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *providerdata.Data, got %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 		return
 	}
 
-	b.client = client.Backup
+	b.client = data.Client.Backup
 }
 
 func (b *backupPolicyResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -185,6 +203,8 @@ func (b *backupPolicyResource) Create(ctx context.Context, req resource.CreateRe
 
 	diags = resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
+
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, backupPolicyIdentityModel{Identifier: plan.Identifier})...)
 }
 
 func (b *backupPolicyResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
@@ -197,7 +217,7 @@ func (b *backupPolicyResource) Read(ctx context.Context, req resource.ReadReques
 
 	policy, err := b.client.GetBackupPolicy(ctx, state.Identifier.ValueString())
 	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
+		if apierror.IsNotFound(err) {
 			resp.State.RemoveResource(ctx)
 			return
 		}
@@ -224,6 +244,8 @@ func (b *backupPolicyResource) Read(ctx context.Context, req resource.ReadReques
 
 	diags = resp.State.Set(ctx, &state)
 	resp.Diagnostics.Append(diags...)
+
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, backupPolicyIdentityModel{Identifier: state.Identifier})...)
 }
 
 func (b *backupPolicyResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
@@ -319,7 +341,7 @@ func (b *backupPolicyResource) Delete(ctx context.Context, req resource.DeleteRe
 }
 
 func (b *backupPolicyResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("identifier"), req, resp)
+	resource.ImportStatePassthroughWithIdentity(ctx, path.Root("identifier"), path.Root("identifier"), req, resp)
 }
 
 func (b *backupPolicyResource) GetPolicyByName(ctx context.Context, name string) (*govpsie.BackupPolicyListDetail, error) {
@@ -334,5 +356,5 @@ func (b *backupPolicyResource) GetPolicyByName(ctx context.Context, name string)
 		}
 	}
 
-	return nil, fmt.Errorf("backup policy with name %s not found", name)
+	return nil, apierror.NotFoundf("backup policy with name %s not found", name)
 }
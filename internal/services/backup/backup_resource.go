@@ -3,11 +3,11 @@ package backup
 import (
 	"context"
 	"fmt"
-	"strings"
 
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
@@ -15,12 +15,15 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/vpsie/govpsie"
+	"github.com/vpsie/terraform-provider-vpsie/internal/apierror"
+	"github.com/vpsie/terraform-provider-vpsie/internal/providerdata"
 )
 
 var (
 	_ resource.Resource                = &backupResource{}
 	_ resource.ResourceWithConfigure   = &backupResource{}
 	_ resource.ResourceWithImportState = &backupResource{}
+	_ resource.ResourceWithIdentity    = &backupResource{}
 )
 
 type backupResource struct {
@@ -44,6 +47,10 @@ type backupResourceModel struct {
 	VMCategory   types.String `tfsdk:"vm_category"`
 }
 
+type backupIdentityModel struct {
+	Identifier types.String `tfsdk:"identifier"`
+}
+
 func NewBackupResource() resource.Resource {
 	return &backupResource{}
 }
@@ -153,22 +160,33 @@ func (s *backupResource) Schema(_ context.Context, _ resource.SchemaRequest, res
 	}
 }
 
+func (b *backupResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"identifier": identityschema.StringAttribute{
+				RequiredForImport: true,
+				Description:       "The unique identifier of the backup.",
+			},
+		},
+	}
+}
+
 func (b *backupResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
 		return
 	}
 
-	client, ok := req.ProviderData.(*govpsie.Client)
+	data, ok := req.ProviderData.(*providerdata.Data)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configuration Type",
-			fmt.Sprintf("Expected *govpsie.Client, got %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *providerdata.Data, got %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	b.client = client.Backup
+	b.client = data.Client.Backup
 }
 
 // Create creates the resource and sets the initial Terraform state.
@@ -214,6 +232,8 @@ func (b *backupResource) Create(ctx context.Context, req resource.CreateRequest,
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, backupIdentityModel{Identifier: plan.Identifier})...)
 }
 
 // Read refreshes the Terraform state with the latest data.
@@ -227,7 +247,7 @@ func (s *backupResource) Read(ctx context.Context, req resource.ReadRequest, res
 
 	backup, err := s.client.Get(ctx, state.Identifier.ValueString())
 	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
+		if apierror.IsNotFound(err) {
 			resp.State.RemoveResource(ctx)
 			return
 		}
@@ -259,6 +279,8 @@ func (s *backupResource) Read(ctx context.Context, req resource.ReadRequest, res
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, backupIdentityModel{Identifier: state.Identifier})...)
 }
 
 // Update updates the resource and sets the updated Terraform state on success.
@@ -314,7 +336,7 @@ func (b *backupResource) Delete(ctx context.Context, req resource.DeleteRequest,
 }
 
 func (b *backupResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("identifier"), req, resp)
+	resource.ImportStatePassthroughWithIdentity(ctx, path.Root("identifier"), path.Root("identifier"), req, resp)
 }
 
 func (b *backupResource) GetBackupByName(ctx context.Context, backupName string) (*govpsie.Backup, error) {
@@ -329,5 +351,5 @@ func (b *backupResource) GetBackupByName(ctx context.Context, backupName string)
 		}
 	}
 
-	return nil, fmt.Errorf("backup with name %s not found", backupName)
+	return nil, apierror.NotFoundf("backup with name %s not found", backupName)
 }
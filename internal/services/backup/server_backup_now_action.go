@@ -0,0 +1,103 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	"github.com/hashicorp/terraform-plugin-framework/action/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/vpsie/terraform-provider-vpsie/internal/providerdata"
+)
+
+var (
+	_ action.Action              = &serverBackupNowAction{}
+	_ action.ActionWithConfigure = &serverBackupNowAction{}
+)
+
+type serverBackupNowAction struct {
+	client BackupAPI
+}
+
+type serverBackupNowActionModel struct {
+	VmIdentifier types.String `tfsdk:"vm_identifier"`
+	Name         types.String `tfsdk:"name"`
+	Note         types.String `tfsdk:"note"`
+}
+
+func NewServerBackupNowAction() action.Action {
+	return &serverBackupNowAction{}
+}
+
+func (a *serverBackupNowAction) Metadata(_ context.Context, req action.MetadataRequest, resp *action.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_server_backup_now"
+}
+
+func (a *serverBackupNowAction) Schema(_ context.Context, _ action.SchemaRequest, resp *action.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Takes a backup of a VPSie server. The backup is not managed by Terraform.",
+		Attributes: map[string]schema.Attribute{
+			"vm_identifier": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "The identifier of the server to back up.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"name": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "The name of the backup. Defaults to `terraform-` followed by the current UTC time.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"note": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "A note to attach to the backup.",
+			},
+		},
+	}
+}
+
+func (a *serverBackupNowAction) Configure(_ context.Context, req action.ConfigureRequest, resp *action.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*providerdata.Data)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Action Configure Type",
+			fmt.Sprintf("Expected *providerdata.Data, got %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	a.client = data.Client.Backup
+}
+
+func (a *serverBackupNowAction) Invoke(ctx context.Context, req action.InvokeRequest, resp *action.InvokeResponse) {
+	var data serverBackupNowActionModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	name := data.Name.ValueString()
+	if data.Name.IsNull() {
+		name = "terraform-" + time.Now().UTC().Format("20060102-150405")
+	}
+
+	err := a.client.CreateBackups(ctx, data.VmIdentifier.ValueString(), name, data.Note.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating backup",
+			"couldn't back up server "+data.VmIdentifier.ValueString()+", unexpected error: "+err.Error(),
+		)
+		return
+	}
+	resp.SendProgress(action.InvokeProgressEvent{Message: "Requested backup " + name + " of server " + data.VmIdentifier.ValueString()})
+}
@@ -0,0 +1,39 @@
+package backup_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/vpsie/govpsie"
+	"github.com/vpsie/terraform-provider-vpsie/internal/acctest"
+	"github.com/vpsie/terraform-provider-vpsie/internal/fakeapi"
+)
+
+func TestUnitServerBackupNowAction(t *testing.T) {
+	ctx := context.Background()
+	backend := fakeapi.New()
+	client := backend.Client()
+	if err := client.Server.CreateServer(ctx, &govpsie.CreateServerRequest{Hostname: "web"}); err != nil {
+		t.Fatalf("CreateServer: %v", err)
+	}
+	servers, _ := client.Server.List(ctx, nil)
+	server, schemas := acctest.FakeProtoV6Server(t, backend, nil)
+
+	_, diags := acctest.InvokeAction(t, server, schemas, "vpsie_server_backup_now", map[string]tftypes.Value{
+		"vm_identifier": tftypes.NewValue(tftypes.String, servers[0].Identifier),
+		"note":          tftypes.NewValue(tftypes.String, "before upgrade"),
+	})
+	if len(diags) > 0 {
+		t.Fatalf("invoke: %s", diags[0].Detail)
+	}
+
+	items, err := client.Backup.List(ctx, nil)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(items) != 1 || !strings.HasPrefix(items[0].Name, "terraform-") {
+		t.Fatalf("expected one backup with a default name, got %+v", items)
+	}
+}
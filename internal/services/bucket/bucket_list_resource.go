@@ -0,0 +1,59 @@
+package bucket
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/list/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/vpsie/govpsie"
+	"github.com/vpsie/terraform-provider-vpsie/internal/listresult"
+)
+
+var (
+	_ list.ListResource              = &bucketListResource{}
+	_ list.ListResourceWithConfigure = &bucketListResource{}
+)
+
+type bucketListResource struct {
+	resource bucketResource
+}
+
+func NewBucketListResource() list.ListResource {
+	return &bucketListResource{}
+}
+
+func (l *bucketListResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	l.resource.Metadata(ctx, req, resp)
+}
+
+func (l *bucketListResource) ListResourceConfigSchema(_ context.Context, _ list.ListResourceSchemaRequest, resp *list.ListResourceSchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Lists the VPSie object storage buckets in the account, for use with `terraform query`.",
+	}
+}
+
+func (l *bucketListResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	l.resource.Configure(ctx, req, resp)
+}
+
+func (l *bucketListResource) List(ctx context.Context, req list.ListRequest, stream *list.ListResultsStream) {
+	buckets, err := l.resource.client.List(ctx, nil)
+	if err != nil {
+		var diags diag.Diagnostics
+		diags.AddError(
+			"Error listing buckets",
+			"Could not list buckets: "+err.Error(),
+		)
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	stream.Results = listresult.Stream(req, buckets, func(bucket govpsie.Bucket) list.ListResult {
+		return listresult.New(ctx, req, &l.resource, bucket.BucketName, bucketIdentityModel{
+			Identifier: types.StringValue(bucket.Identifier),
+		})
+	})
+}
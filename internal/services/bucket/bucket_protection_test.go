@@ -0,0 +1,27 @@
+package bucket_test
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/vpsie/terraform-provider-vpsie/internal/acctest"
+	"github.com/vpsie/terraform-provider-vpsie/internal/fakeapi"
+)
+
+func TestUnitBucketResource_DeletionProtection(t *testing.T) {
+	server, schemas := acctest.FakeProtoV6Server(t, fakeapi.New(), map[string]tftypes.Value{
+		"delete_reason": tftypes.NewValue(tftypes.String, "test"),
+	})
+	config := map[string]tftypes.Value{
+		"bucket_name":   tftypes.NewValue(tftypes.String, "assets"),
+		"project_id":    tftypes.NewValue(tftypes.String, "project-1"),
+		"datacenter_id": tftypes.NewValue(tftypes.String, "dc-1"),
+	}
+
+	created, diags := acctest.ApplyResource(t, server, schemas, "vpsie_bucket", nil, config)
+	if len(diags) > 0 {
+		t.Fatalf("create: %s: %s", diags[0].Summary, diags[0].Detail)
+	}
+
+	acctest.CheckDeletionProtection(t, server, schemas, "vpsie_bucket", created, config)
+}
@@ -3,41 +3,51 @@ package bucket
 import (
 	"context"
 	"fmt"
-	"strings"
 
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/vpsie/govpsie"
+	"github.com/vpsie/terraform-provider-vpsie/internal/apierror"
+	"github.com/vpsie/terraform-provider-vpsie/internal/deletion"
+	"github.com/vpsie/terraform-provider-vpsie/internal/providerdata"
 )
 
 var (
 	_ resource.Resource                = &bucketResource{}
 	_ resource.ResourceWithConfigure   = &bucketResource{}
 	_ resource.ResourceWithImportState = &bucketResource{}
+	_ resource.ResourceWithIdentity    = &bucketResource{}
 )
 
 type bucketResource struct {
-	client BucketAPI
+	client   BucketAPI
+	deletion deletion.Settings
 }
 
 type bucketResourceModel struct {
-	Identifier   types.String `tfsdk:"identifier"`
-	BucketName   types.String `tfsdk:"bucket_name"`
-	ProjectID    types.String `tfsdk:"project_id"`
-	DataCenterID types.String `tfsdk:"datacenter_id"`
-	FileListing  types.Bool   `tfsdk:"file_listing"`
-	AccessKey    types.String `tfsdk:"access_key"`
-	SecretKey    types.String `tfsdk:"secret_key"`
-	EndPoint     types.String `tfsdk:"endpoint"`
-	State        types.String `tfsdk:"state"`
-	CreatedBy    types.String `tfsdk:"created_by"`
-	CreatedOn    types.String `tfsdk:"created_on"`
+	Identifier         types.String `tfsdk:"identifier"`
+	BucketName         types.String `tfsdk:"bucket_name"`
+	ProjectID          types.String `tfsdk:"project_id"`
+	DataCenterID       types.String `tfsdk:"datacenter_id"`
+	FileListing        types.Bool   `tfsdk:"file_listing"`
+	AccessKey          types.String `tfsdk:"access_key"`
+	SecretKey          types.String `tfsdk:"secret_key"`
+	EndPoint           types.String `tfsdk:"endpoint"`
+	State              types.String `tfsdk:"state"`
+	CreatedBy          types.String `tfsdk:"created_by"`
+	CreatedOn          types.String `tfsdk:"created_on"`
+	DeletionProtection types.Bool   `tfsdk:"deletion_protection"`
+}
+
+type bucketIdentityModel struct {
+	Identifier types.String `tfsdk:"identifier"`
 }
 
 func NewBucketResource() resource.Resource {
@@ -137,6 +147,18 @@ func (b *bucketResource) Schema(_ context.Context, _ resource.SchemaRequest, res
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
+			"deletion_protection": deletion.ProtectionAttribute("bucket"),
+		},
+	}
+}
+
+func (b *bucketResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"identifier": identityschema.StringAttribute{
+				RequiredForImport: true,
+				Description:       "The unique identifier of the bucket.",
+			},
 		},
 	}
 }
@@ -146,16 +168,17 @@ func (b *bucketResource) Configure(_ context.Context, req resource.ConfigureRequ
 		return
 	}
 
-	client, ok := req.ProviderData.(*govpsie.Client)
+	data, ok := req.ProviderData.(*providerdata.Data)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configuration Type",
-			fmt.Sprintf("Expected *govpsie.Client, got %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *providerdata.Data, got %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 		return
 	}
 
-	b.client = client.Bucket
+	b.client = data.Client.Bucket
+	b.deletion = data.Deletion
 }
 
 func (b *bucketResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -200,6 +223,8 @@ func (b *bucketResource) Create(ctx context.Context, req resource.CreateRequest,
 
 	diags = resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
+
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, bucketIdentityModel{Identifier: plan.Identifier})...)
 }
 
 func (b *bucketResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
@@ -212,7 +237,7 @@ func (b *bucketResource) Read(ctx context.Context, req resource.ReadRequest, res
 
 	bucket, err := b.client.Get(ctx, state.Identifier.ValueString())
 	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
+		if apierror.IsNotFound(err) {
 			resp.State.RemoveResource(ctx)
 			return
 		}
@@ -233,6 +258,8 @@ func (b *bucketResource) Read(ctx context.Context, req resource.ReadRequest, res
 
 	diags = resp.State.Set(ctx, &state)
 	resp.Diagnostics.Append(diags...)
+
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, bucketIdentityModel{Identifier: state.Identifier})...)
 }
 
 func (b *bucketResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
@@ -258,6 +285,7 @@ func (b *bucketResource) Update(ctx context.Context, req resource.UpdateRequest,
 		}
 		state.FileListing = plan.FileListing
 	}
+	state.DeletionProtection = plan.DeletionProtection
 
 	diags = resp.State.Set(ctx, &state)
 	resp.Diagnostics.Append(diags...)
@@ -271,7 +299,15 @@ func (b *bucketResource) Delete(ctx context.Context, req resource.DeleteRequest,
 		return
 	}
 
-	err := b.client.Delete(ctx, state.Identifier.ValueString(), "terraform-destroy", "terraform-destroy")
+	resp.Diagnostics.Append(deletion.CheckProtection(state.DeletionProtection, "bucket", state.Identifier.ValueString())...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	reason := deletion.Pick(b.deletion.Reason, "terraform-destroy")
+	note := deletion.Pick(b.deletion.Note, "terraform-destroy")
+
+	err := b.client.Delete(ctx, state.Identifier.ValueString(), reason, note)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error deleting bucket",
@@ -282,7 +318,7 @@ func (b *bucketResource) Delete(ctx context.Context, req resource.DeleteRequest,
 }
 
 func (b *bucketResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("identifier"), req, resp)
+	resource.ImportStatePassthroughWithIdentity(ctx, path.Root("identifier"), path.Root("identifier"), req, resp)
 }
 
 func (b *bucketResource) GetBucketByName(ctx context.Context, name string) (*govpsie.Bucket, error) {
@@ -297,5 +333,5 @@ func (b *bucketResource) GetBucketByName(ctx context.Context, name string) (*gov
 		}
 	}
 
-	return nil, fmt.Errorf("bucket with name %s not found", name)
+	return nil, apierror.NotFoundf("bucket with name %s not found", name)
 }
@@ -0,0 +1,49 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/vpsie/terraform-provider-vpsie/internal/acctest"
+	"github.com/vpsie/terraform-provider-vpsie/internal/fakeapi"
+)
+
+func TestUnitDnsRecordImport_IDAndIdentityAgree(t *testing.T) {
+	server, schemas := acctest.FakeProtoV6Server(t, fakeapi.New(), nil)
+
+	byID, diags := acctest.ImportResourceState(t, server, schemas, "vpsie_dns_record", "dom-1/A/www", nil)
+	if len(diags) > 0 {
+		t.Fatalf("import by ID: %s", diags[0].Detail)
+	}
+	byIdentity, diags := acctest.ImportResourceState(t, server, schemas, "vpsie_dns_record", "", map[string]tftypes.Value{
+		"domain_identifier": tftypes.NewValue(tftypes.String, "dom-1"),
+		"type":              tftypes.NewValue(tftypes.String, "A"),
+		"name":              tftypes.NewValue(tftypes.String, "www"),
+	})
+	if len(diags) > 0 {
+		t.Fatalf("import by identity: %s", diags[0].Detail)
+	}
+
+	for _, attr := range []string{"id", "domain_identifier", "type", "name"} {
+		if !byID.State[attr].Equal(byIdentity.State[attr]) {
+			t.Errorf("%s: import by ID gave %s, import by identity gave %s", attr, byID.State[attr], byIdentity.State[attr])
+		}
+	}
+	var id string
+	_ = byIdentity.State["id"].As(&id)
+	if id != "dom-1/A/www" {
+		t.Errorf("expected id dom-1/A/www, got %q", id)
+	}
+}
+
+func TestUnitDnsRecordImport_InvalidID(t *testing.T) {
+	server, schemas := acctest.FakeProtoV6Server(t, fakeapi.New(), nil)
+
+	_, diags := acctest.ImportResourceState(t, server, schemas, "vpsie_dns_record", "dom-1/www", nil)
+	if len(diags) == 0 {
+		t.Fatal("expected an error for an ID without a record type")
+	}
+	if diags[0].Summary != "Invalid Import ID" {
+		t.Errorf("unexpected diagnostic: %s", diags[0].Summary)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
@@ -15,12 +16,14 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/vpsie/govpsie"
+	"github.com/vpsie/terraform-provider-vpsie/internal/providerdata"
 )
 
 var (
 	_ resource.Resource                = &dnsRecordResource{}
 	_ resource.ResourceWithConfigure   = &dnsRecordResource{}
 	_ resource.ResourceWithImportState = &dnsRecordResource{}
+	_ resource.ResourceWithIdentity    = &dnsRecordResource{}
 )
 
 type dnsRecordResource struct {
@@ -36,6 +39,12 @@ type dnsRecordResourceModel struct {
 	TTL              types.Int64  `tfsdk:"ttl"`
 }
 
+type dnsRecordIdentityModel struct {
+	DomainIdentifier types.String `tfsdk:"domain_identifier"`
+	Type             types.String `tfsdk:"type"`
+	Name             types.String `tfsdk:"name"`
+}
+
 func NewDnsRecordResource() resource.Resource {
 	return &dnsRecordResource{}
 }
@@ -101,21 +110,40 @@ func (d *dnsRecordResource) Schema(_ context.Context, _ resource.SchemaRequest,
 	}
 }
 
+func (d *dnsRecordResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"domain_identifier": identityschema.StringAttribute{
+				RequiredForImport: true,
+				Description:       "The identifier of the domain the record belongs to.",
+			},
+			"type": identityschema.StringAttribute{
+				RequiredForImport: true,
+				Description:       "The type of the DNS record.",
+			},
+			"name": identityschema.StringAttribute{
+				RequiredForImport: true,
+				Description:       "The name of the DNS record.",
+			},
+		},
+	}
+}
+
 func (d *dnsRecordResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
 		return
 	}
 
-	client, ok := req.ProviderData.(*govpsie.Client)
+	data, ok := req.ProviderData.(*providerdata.Data)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configuration Type",
-			fmt.Sprintf("Expected *govpsie.Client, got %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *providerdata.Data, got %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 		return
 	}
 
-	d.client = client.Domain
+	d.client = data.Client.Domain
 }
 
 func (d *dnsRecordResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -152,6 +180,8 @@ func (d *dnsRecordResource) Create(ctx context.Context, req resource.CreateReque
 
 	diags = resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
+
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, dnsRecordIdentityModel{DomainIdentifier: plan.DomainIdentifier, Type: plan.Type, Name: plan.Name})...)
 }
 
 func (d *dnsRecordResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
@@ -165,6 +195,8 @@ func (d *dnsRecordResource) Read(ctx context.Context, req resource.ReadRequest,
 	// DNS records don't have a dedicated Get API — state is maintained from Create/Update
 	diags = resp.State.Set(ctx, &state)
 	resp.Diagnostics.Append(diags...)
+
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, dnsRecordIdentityModel{DomainIdentifier: state.DomainIdentifier, Type: state.Type, Name: state.Name})...)
 }
 
 func (d *dnsRecordResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
@@ -222,19 +254,41 @@ func (d *dnsRecordResource) Update(ctx context.Context, req resource.UpdateReque
 }
 
 func (d *dnsRecordResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	parts := strings.Split(req.ID, "/")
+	var identity dnsRecordIdentityModel
+	if req.ID == "" {
+		resp.Diagnostics.Append(req.Identity.Get(ctx, &identity)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	} else {
+		domainIdentifier, recordType, name, err := ParseDnsRecordID(req.ID)
+		if err != nil {
+			resp.Diagnostics.AddError("Invalid Import ID", err.Error())
+			return
+		}
+		identity = dnsRecordIdentityModel{
+			DomainIdentifier: types.StringValue(domainIdentifier),
+			Type:             types.StringValue(recordType),
+			Name:             types.StringValue(name),
+		}
+	}
+
+	id := fmt.Sprintf("%s/%s/%s", identity.DomainIdentifier.ValueString(), identity.Type.ValueString(), identity.Name.ValueString())
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("domain_identifier"), identity.DomainIdentifier)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("type"), identity.Type)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), identity.Name)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+}
+
+// ParseDnsRecordID splits a DNS record ID of the form
+// <domain_identifier>/<type>/<name>, as used by vpsie_dns_record.
+func ParseDnsRecordID(id string) (domainIdentifier, recordType, name string, err error) {
+	parts := strings.Split(id, "/")
 	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
-		resp.Diagnostics.AddError(
-			"Invalid Import ID",
-			fmt.Sprintf("Expected import identifier with format: <domain_identifier>/<type>/<name>. Got: %s", req.ID),
-		)
-		return
+		return "", "", "", fmt.Errorf("expected a DNS record ID with format: <domain_identifier>/<type>/<name>, got: %s", id)
 	}
 
-	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("domain_identifier"), parts[0])...)
-	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("type"), parts[1])...)
-	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), parts[2])...)
-	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	return parts[0], parts[1], parts[2], nil
 }
 
 func (d *dnsRecordResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/list/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/vpsie/govpsie"
+	"github.com/vpsie/terraform-provider-vpsie/internal/listresult"
+)
+
+var (
+	_ list.ListResource              = &domainListResource{}
+	_ list.ListResourceWithConfigure = &domainListResource{}
+)
+
+type domainListResource struct {
+	resource domainResource
+}
+
+func NewDomainListResource() list.ListResource {
+	return &domainListResource{}
+}
+
+func (l *domainListResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	l.resource.Metadata(ctx, req, resp)
+}
+
+func (l *domainListResource) ListResourceConfigSchema(_ context.Context, _ list.ListResourceSchemaRequest, resp *list.ListResourceSchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Lists the VPSie domains in the account, for use with `terraform query`.",
+	}
+}
+
+func (l *domainListResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	l.resource.Configure(ctx, req, resp)
+}
+
+func (l *domainListResource) List(ctx context.Context, req list.ListRequest, stream *list.ListResultsStream) {
+	domains, err := l.resource.client.ListDomains(ctx, &govpsie.ListOptions{Page: 0, PerPage: 50})
+	if err != nil {
+		var diags diag.Diagnostics
+		diags.AddError(
+			"Error listing domains",
+			"Could not list domains: "+err.Error(),
+		)
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	stream.Results = listresult.Stream(req, domains, func(domain govpsie.Domain) list.ListResult {
+		return listresult.New(ctx, req, &l.resource, domain.DomainName, domainIdentityModel{
+			Identifier: types.StringValue(domain.Identifier),
+		})
+	})
+}
@@ -0,0 +1,42 @@
+package domain_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/vpsie/govpsie"
+	"github.com/vpsie/terraform-provider-vpsie/internal/acctest"
+	"github.com/vpsie/terraform-provider-vpsie/internal/fakeapi"
+)
+
+func TestUnitDomainListResource(t *testing.T) {
+	ctx := context.Background()
+	backend := fakeapi.New()
+	client := backend.Client()
+	for _, name := range []string{"example.com", "example.org"} {
+		if err := client.Domain.CreateDomain(ctx, &govpsie.CreateDomainRequest{Domain: name}); err != nil {
+			t.Fatalf("CreateDomain: %v", err)
+		}
+	}
+	server, schemas := acctest.FakeProtoV6Server(t, backend, nil)
+
+	results, diags := acctest.ListResource(t, server, schemas, "vpsie_domain", nil, true)
+	if len(diags) > 0 {
+		t.Fatalf("list: %s", diags[0].Detail)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected two domains, got %d", len(results))
+	}
+	for _, result := range results {
+		var identifier, resourceIdentifier, domainName string
+		_ = result.Identity["identifier"].As(&identifier)
+		_ = result.Resource["identifier"].As(&resourceIdentifier)
+		_ = result.Resource["domain_name"].As(&domainName)
+		if identifier == "" || identifier != resourceIdentifier {
+			t.Errorf("expected the identity to match the resource, got %q and %q", identifier, resourceIdentifier)
+		}
+		if domainName != result.DisplayName {
+			t.Errorf("expected domain_name %q, got %q", result.DisplayName, domainName)
+		}
+	}
+}
@@ -0,0 +1,24 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/vpsie/terraform-provider-vpsie/internal/acctest"
+	"github.com/vpsie/terraform-provider-vpsie/internal/fakeapi"
+)
+
+func TestUnitDomainResource_DeletionProtection(t *testing.T) {
+	server, schemas := acctest.FakeProtoV6Server(t, fakeapi.New(), nil)
+	config := map[string]tftypes.Value{
+		"domain_name":        tftypes.NewValue(tftypes.String, "example.com"),
+		"project_identifier": tftypes.NewValue(tftypes.String, "project-1"),
+	}
+
+	created, diags := acctest.ApplyResource(t, server, schemas, "vpsie_domain", nil, config)
+	if len(diags) > 0 {
+		t.Fatalf("create: %s: %s", diags[0].Summary, diags[0].Detail)
+	}
+
+	acctest.CheckDeletionProtection(t, server, schemas, "vpsie_domain", created, config)
+}
@@ -3,11 +3,11 @@ package domain
 import (
 	"context"
 	"fmt"
-	"strings"
 
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
@@ -15,12 +15,16 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/vpsie/govpsie"
+	"github.com/vpsie/terraform-provider-vpsie/internal/apierror"
+	"github.com/vpsie/terraform-provider-vpsie/internal/deletion"
+	"github.com/vpsie/terraform-provider-vpsie/internal/providerdata"
 )
 
 var (
 	_ resource.Resource                = &domainResource{}
 	_ resource.ResourceWithConfigure   = &domainResource{}
 	_ resource.ResourceWithImportState = &domainResource{}
+	_ resource.ResourceWithIdentity    = &domainResource{}
 )
 
 type domainResource struct {
@@ -28,12 +32,17 @@ type domainResource struct {
 }
 
 type domainResourceModel struct {
-	Identifier        types.String `tfsdk:"identifier"`
-	DomainName        types.String `tfsdk:"domain_name"`
-	NsValidated       types.Int64  `tfsdk:"ns_validated"`
-	CreatedOn         types.String `tfsdk:"created_on"`
-	LastCheck         types.String `tfsdk:"last_check"`
-	ProjectIdentifier types.String `tfsdk:"project_identifier"`
+	Identifier         types.String `tfsdk:"identifier"`
+	DomainName         types.String `tfsdk:"domain_name"`
+	NsValidated        types.Int64  `tfsdk:"ns_validated"`
+	CreatedOn          types.String `tfsdk:"created_on"`
+	LastCheck          types.String `tfsdk:"last_check"`
+	ProjectIdentifier  types.String `tfsdk:"project_identifier"`
+	DeletionProtection types.Bool   `tfsdk:"deletion_protection"`
+}
+
+type domainIdentityModel struct {
+	Identifier types.String `tfsdk:"identifier"`
 }
 
 func NewDomainResource() resource.Resource {
@@ -96,6 +105,18 @@ func (s *domainResource) Schema(_ context.Context, _ resource.SchemaRequest, res
 					stringvalidator.LengthAtLeast(1),
 				},
 			},
+			"deletion_protection": deletion.ProtectionAttribute("domain"),
+		},
+	}
+}
+
+func (d *domainResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"identifier": identityschema.StringAttribute{
+				RequiredForImport: true,
+				Description:       "The unique identifier of the domain.",
+			},
 		},
 	}
 }
@@ -105,17 +126,17 @@ func (d *domainResource) Configure(_ context.Context, req resource.ConfigureRequ
 		return
 	}
 
-	client, ok := req.ProviderData.(*govpsie.Client)
+	data, ok := req.ProviderData.(*providerdata.Data)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configuration Type",
-			fmt.Sprintf("Expected *govpsie.Client, got %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *providerdata.Data, got %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	d.client = client.Domain
+	d.client = data.Client.Domain
 }
 
 // Create creates the resource and sets the initial Terraform state.
@@ -157,6 +178,8 @@ func (d *domainResource) Create(ctx context.Context, req resource.CreateRequest,
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, domainIdentityModel{Identifier: plan.Identifier})...)
 }
 
 // Read refreshes the Terraform state with the latest data.
@@ -168,9 +191,9 @@ func (d *domainResource) Read(ctx context.Context, req resource.ReadRequest, res
 		return
 	}
 
-	domain, err := d.GetDomainByName(ctx, state.DomainName.ValueString())
+	domain, err := d.GetDomainByIdentifier(ctx, state.Identifier.ValueString())
 	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
+		if apierror.IsNotFound(err) {
 			resp.State.RemoveResource(ctx)
 			return
 		}
@@ -183,6 +206,7 @@ func (d *domainResource) Read(ctx context.Context, req resource.ReadRequest, res
 
 	// Overwrite items with refreshed state
 
+	state.Identifier = types.StringValue(domain.Identifier)
 	state.DomainName = types.StringValue(domain.DomainName)
 	state.CreatedOn = types.StringValue(domain.CreatedOn)
 	state.NsValidated = types.Int64Value(int64(domain.NsValidated))
@@ -193,10 +217,23 @@ func (d *domainResource) Read(ctx context.Context, req resource.ReadRequest, res
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, domainIdentityModel{Identifier: state.Identifier})...)
 }
 
 // Update updates the resource and sets the updated Terraform state on success.
 func (d *domainResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// Every other attribute forces a new domain, so only
+	// deletion_protection can change here.
+	var plan domainResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
 }
 
 // Delete deletes the resource and removes the Terraform state on success.
@@ -208,6 +245,11 @@ func (d *domainResource) Delete(ctx context.Context, req resource.DeleteRequest,
 		return
 	}
 
+	resp.Diagnostics.Append(deletion.CheckProtection(state.DeletionProtection, "domain", state.Identifier.ValueString())...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	err := d.client.DeleteDomain(ctx, state.Identifier.ValueString(), "terraform delete", "terraform delete")
 	if err != nil {
 		resp.Diagnostics.AddError(
@@ -220,7 +262,7 @@ func (d *domainResource) Delete(ctx context.Context, req resource.DeleteRequest,
 }
 
 func (d *domainResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("identifier"), req, resp)
+	resource.ImportStatePassthroughWithIdentity(ctx, path.Root("identifier"), path.Root("identifier"), req, resp)
 }
 
 func (d *domainResource) GetDomainByName(ctx context.Context, domainName string) (*govpsie.Domain, error) {
@@ -235,5 +277,20 @@ func (d *domainResource) GetDomainByName(ctx context.Context, domainName string)
 		}
 	}
 
-	return nil, fmt.Errorf("domain with name %s not found", domainName)
+	return nil, apierror.NotFoundf("domain with name %s not found", domainName)
+}
+
+func (d *domainResource) GetDomainByIdentifier(ctx context.Context, identifier string) (*govpsie.Domain, error) {
+	domains, err := d.client.ListDomains(ctx, &govpsie.ListOptions{Page: 0, PerPage: 50})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, domain := range domains {
+		if identifier == domain.Identifier {
+			return &domain, nil
+		}
+	}
+
+	return nil, apierror.NotFoundf("domain with identifier %s not found", identifier)
 }
@@ -8,18 +8,21 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/vpsie/govpsie"
+	"github.com/vpsie/terraform-provider-vpsie/internal/providerdata"
 )
 
 var (
 	_ resource.Resource                = &reverseDnsResource{}
 	_ resource.ResourceWithConfigure   = &reverseDnsResource{}
 	_ resource.ResourceWithImportState = &reverseDnsResource{}
+	_ resource.ResourceWithIdentity    = &reverseDnsResource{}
 )
 
 type reverseDnsResource struct {
@@ -34,6 +37,11 @@ type reverseDnsResourceModel struct {
 	HostName         types.String `tfsdk:"hostname"`
 }
 
+type reverseDnsIdentityModel struct {
+	VmIdentifier types.String `tfsdk:"vm_identifier"`
+	IP           types.String `tfsdk:"ip"`
+}
+
 func NewReverseDnsResource() resource.Resource {
 	return &reverseDnsResource{}
 }
@@ -91,21 +99,36 @@ func (r *reverseDnsResource) Schema(_ context.Context, _ resource.SchemaRequest,
 	}
 }
 
+func (r *reverseDnsResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"vm_identifier": identityschema.StringAttribute{
+				RequiredForImport: true,
+				Description:       "The identifier of the server the IP belongs to.",
+			},
+			"ip": identityschema.StringAttribute{
+				RequiredForImport: true,
+				Description:       "The IP address the PTR record is set on.",
+			},
+		},
+	}
+}
+
 func (r *reverseDnsResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
 		return
 	}
 
-	client, ok := req.ProviderData.(*govpsie.Client)
+	data, ok := req.ProviderData.(*providerdata.Data)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configuration Type",
-			fmt.Sprintf("Expected *govpsie.Client, got %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *providerdata.Data, got %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 		return
 	}
 
-	r.client = client.Domain
+	r.client = data.Client.Domain
 }
 
 func (r *reverseDnsResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -133,6 +156,8 @@ func (r *reverseDnsResource) Create(ctx context.Context, req resource.CreateRequ
 
 	diags = resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
+
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, reverseDnsIdentityModel{VmIdentifier: plan.VmIdentifier, IP: plan.IP})...)
 }
 
 func (r *reverseDnsResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
@@ -165,6 +190,8 @@ func (r *reverseDnsResource) Read(ctx context.Context, req resource.ReadRequest,
 
 	diags = resp.State.Set(ctx, &state)
 	resp.Diagnostics.Append(diags...)
+
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, reverseDnsIdentityModel{VmIdentifier: state.VmIdentifier, IP: state.IP})...)
 }
 
 func (r *reverseDnsResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
@@ -193,18 +220,30 @@ func (r *reverseDnsResource) Update(ctx context.Context, req resource.UpdateRequ
 }
 
 func (r *reverseDnsResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	parts := strings.Split(req.ID, "/")
-	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
-		resp.Diagnostics.AddError(
-			"Invalid Import ID",
-			fmt.Sprintf("Expected import identifier with format: <vm_identifier>/<ip>. Got: %s", req.ID),
-		)
-		return
+	var identity reverseDnsIdentityModel
+	if req.ID == "" {
+		resp.Diagnostics.Append(req.Identity.Get(ctx, &identity)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	} else {
+		parts := strings.Split(req.ID, "/")
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			resp.Diagnostics.AddError(
+				"Invalid Import ID",
+				fmt.Sprintf("Expected import identifier with format: <vm_identifier>/<ip>. Got: %s", req.ID),
+			)
+			return
+		}
+		identity = reverseDnsIdentityModel{
+			VmIdentifier: types.StringValue(parts[0]),
+			IP:           types.StringValue(parts[1]),
+		}
 	}
 
-	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("vm_identifier"), parts[0])...)
-	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("ip"), parts[1])...)
-	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("vm_identifier"), identity.VmIdentifier)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("ip"), identity.IP)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), fmt.Sprintf("%s/%s", identity.VmIdentifier.ValueString(), identity.IP.ValueString()))...)
 }
 
 func (r *reverseDnsResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
@@ -7,18 +7,21 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/vpsie/govpsie"
+	"github.com/vpsie/terraform-provider-vpsie/internal/providerdata"
 )
 
 var (
 	_ resource.Resource                = &fipResource{}
 	_ resource.ResourceWithConfigure   = &fipResource{}
 	_ resource.ResourceWithImportState = &fipResource{}
+	_ resource.ResourceWithIdentity    = &fipResource{}
 )
 
 type fipResource struct {
@@ -34,6 +37,10 @@ type fipResourceModel struct {
 	IP           types.String `tfsdk:"ip"`
 }
 
+type fipIdentityModel struct {
+	ID types.String `tfsdk:"id"`
+}
+
 func NewFipResource() resource.Resource {
 	return &fipResource{}
 }
@@ -94,22 +101,33 @@ func (f *fipResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *
 	}
 }
 
+func (f *fipResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"id": identityschema.StringAttribute{
+				RequiredForImport: true,
+				Description:       "The unique identifier of the floating IP.",
+			},
+		},
+	}
+}
+
 func (f *fipResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
 		return
 	}
 
-	client, ok := req.ProviderData.(*govpsie.Client)
+	data, ok := req.ProviderData.(*providerdata.Data)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configuration Type",
-			fmt.Sprintf("Expected *govpsie.Client, got %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *providerdata.Data, got %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 		return
 	}
 
-	f.client = client.Fip
-	f.ipClient = client.IP
+	f.client = data.Client.Fip
+	f.ipClient = data.Client.IP
 }
 
 func (f *fipResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -163,6 +181,8 @@ func (f *fipResource) Create(ctx context.Context, req resource.CreateRequest, re
 
 	diags = resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
+
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, fipIdentityModel{ID: plan.ID})...)
 }
 
 func (f *fipResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
@@ -179,10 +199,13 @@ func (f *fipResource) Read(ctx context.Context, req resource.ReadRequest, resp *
 		return
 	}
 
+	// An imported floating IP only knows its ID until the first read.
 	found := false
 	for _, ip := range allIPs {
-		if ip.IP == state.IP.ValueString() {
-			state.ID = types.StringValue(fmt.Sprintf("%d", ip.ID))
+		id := fmt.Sprintf("%d", ip.ID)
+		if ip.IP == state.IP.ValueString() || (state.IP.IsNull() && id == state.ID.ValueString()) {
+			state.ID = types.StringValue(id)
+			state.IP = types.StringValue(ip.IP)
 			found = true
 			break
 		}
@@ -195,6 +218,8 @@ func (f *fipResource) Read(ctx context.Context, req resource.ReadRequest, resp *
 
 	diags = resp.State.Set(ctx, &state)
 	resp.Diagnostics.Append(diags...)
+
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, fipIdentityModel{ID: state.ID})...)
 }
 
 func (f *fipResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
@@ -202,7 +227,7 @@ func (f *fipResource) Update(ctx context.Context, req resource.UpdateRequest, re
 }
 
 func (f *fipResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	resource.ImportStatePassthroughWithIdentity(ctx, path.Root("id"), path.Root("id"), req, resp)
 }
 
 func (f *fipResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
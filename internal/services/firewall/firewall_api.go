@@ -13,6 +13,7 @@ type FirewallAPI interface {
 	List(ctx context.Context, options *govpsie.ListOptions) ([]govpsie.FirewallGroupListData, error)
 	Get(ctx context.Context, fwGroupId string) (*govpsie.FirewallGroupDetailData, error)
 	Delete(ctx context.Context, fwGroupId string) error
+	Update(ctx context.Context, fwGroupReq *govpsie.FirewallUpdateReq, fwGroupId string) error
 	AttachToVpsie(ctx context.Context, groupId, vmId string) error
 	DetachFromVpsie(ctx context.Context, groupId, vmId string) error
 }
@@ -8,18 +8,20 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
-	"github.com/vpsie/govpsie"
+	"github.com/vpsie/terraform-provider-vpsie/internal/providerdata"
 )
 
 var (
 	_ resource.Resource                = &firewallAttachmentResource{}
 	_ resource.ResourceWithConfigure   = &firewallAttachmentResource{}
 	_ resource.ResourceWithImportState = &firewallAttachmentResource{}
+	_ resource.ResourceWithIdentity    = &firewallAttachmentResource{}
 )
 
 type firewallAttachmentResource struct {
@@ -32,6 +34,11 @@ type firewallAttachmentResourceModel struct {
 	VmIdentifier types.String `tfsdk:"vm_identifier"`
 }
 
+type firewallAttachmentIdentityModel struct {
+	GroupID      types.String `tfsdk:"group_id"`
+	VmIdentifier types.String `tfsdk:"vm_identifier"`
+}
+
 func NewFirewallAttachmentResource() resource.Resource {
 	return &firewallAttachmentResource{}
 }
@@ -75,21 +82,36 @@ func (f *firewallAttachmentResource) Schema(_ context.Context, _ resource.Schema
 	}
 }
 
+func (f *firewallAttachmentResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"group_id": identityschema.StringAttribute{
+				RequiredForImport: true,
+				Description:       "The identifier of the firewall group.",
+			},
+			"vm_identifier": identityschema.StringAttribute{
+				RequiredForImport: true,
+				Description:       "The identifier of the server the firewall group is attached to.",
+			},
+		},
+	}
+}
+
 func (f *firewallAttachmentResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
 		return
 	}
 
-	client, ok := req.ProviderData.(*govpsie.Client)
+	data, ok := req.ProviderData.(*providerdata.Data)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configuration Type",
-			fmt.Sprintf("Expected *govpsie.Client, got %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *providerdata.Data, got %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 		return
 	}
 
-	f.client = client.FirewallGroup
+	f.client = data.Client.FirewallGroup
 }
 
 func (f *firewallAttachmentResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -110,6 +132,8 @@ func (f *firewallAttachmentResource) Create(ctx context.Context, req resource.Cr
 
 	diags = resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
+
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, firewallAttachmentIdentityModel{GroupID: plan.GroupID, VmIdentifier: plan.VmIdentifier})...)
 }
 
 func (f *firewallAttachmentResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
@@ -141,6 +165,8 @@ func (f *firewallAttachmentResource) Read(ctx context.Context, req resource.Read
 
 	diags = resp.State.Set(ctx, &state)
 	resp.Diagnostics.Append(diags...)
+
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, firewallAttachmentIdentityModel{GroupID: state.GroupID, VmIdentifier: state.VmIdentifier})...)
 }
 
 func (f *firewallAttachmentResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
@@ -148,18 +174,30 @@ func (f *firewallAttachmentResource) Update(ctx context.Context, req resource.Up
 }
 
 func (f *firewallAttachmentResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	parts := strings.Split(req.ID, "/")
-	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
-		resp.Diagnostics.AddError(
-			"Invalid Import ID",
-			fmt.Sprintf("Expected import identifier with format: <group_id>/<vm_identifier>. Got: %s", req.ID),
-		)
-		return
+	var identity firewallAttachmentIdentityModel
+	if req.ID == "" {
+		resp.Diagnostics.Append(req.Identity.Get(ctx, &identity)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	} else {
+		parts := strings.Split(req.ID, "/")
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			resp.Diagnostics.AddError(
+				"Invalid Import ID",
+				fmt.Sprintf("Expected import identifier with format: <group_id>/<vm_identifier>. Got: %s", req.ID),
+			)
+			return
+		}
+		identity = firewallAttachmentIdentityModel{
+			GroupID:      types.StringValue(parts[0]),
+			VmIdentifier: types.StringValue(parts[1]),
+		}
 	}
 
-	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("group_id"), parts[0])...)
-	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("vm_identifier"), parts[1])...)
-	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("group_id"), identity.GroupID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("vm_identifier"), identity.VmIdentifier)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), fmt.Sprintf("%s/%s", identity.GroupID.ValueString(), identity.VmIdentifier.ValueString()))...)
 }
 
 func (f *firewallAttachmentResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
@@ -0,0 +1,331 @@
+package firewall
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/vpsie/govpsie"
+	"github.com/vpsie/terraform-provider-vpsie/internal/apierror"
+	"github.com/vpsie/terraform-provider-vpsie/internal/providerdata"
+)
+
+var (
+	_ resource.Resource                = &firewallGroupMembersResource{}
+	_ resource.ResourceWithConfigure   = &firewallGroupMembersResource{}
+	_ resource.ResourceWithImportState = &firewallGroupMembersResource{}
+	_ resource.ResourceWithIdentity    = &firewallGroupMembersResource{}
+)
+
+type firewallGroupMembersResource struct {
+	client FirewallAPI
+}
+
+type firewallGroupMembersResourceModel struct {
+	ID            types.String `tfsdk:"id"`
+	GroupID       types.String `tfsdk:"group_id"`
+	VmIdentifiers types.Set    `tfsdk:"vm_identifiers"`
+}
+
+type firewallGroupMembersIdentityModel struct {
+	GroupID types.String `tfsdk:"group_id"`
+}
+
+func NewFirewallGroupMembersResource() resource.Resource {
+	return &firewallGroupMembersResource{}
+}
+
+func (f *firewallGroupMembersResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_firewall_group_members"
+}
+
+func (f *firewallGroupMembersResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Manages the complete set of VMs attached to a firewall group on the VPSie platform. " +
+			"The configured set is authoritative: VMs attached to the group outside of this resource are detached on the next apply. " +
+			"Do not use it together with `vpsie_firewall_attachment` for the same group.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The ID of the resource, equal to `group_id`.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"group_id": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "The identifier of the firewall group. Changing this forces a new resource.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"vm_identifiers": schema.SetAttribute{
+				Required:            true,
+				ElementType:         types.StringType,
+				MarkdownDescription: "The identifiers of every VM that should be attached to the firewall group. An empty set detaches all VMs.",
+				Validators: []validator.Set{
+					setvalidator.ValueStringsAre(stringvalidator.LengthAtLeast(1)),
+				},
+			},
+		},
+	}
+}
+
+func (f *firewallGroupMembersResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"group_id": identityschema.StringAttribute{
+				RequiredForImport: true,
+				Description:       "The identifier of the firewall group.",
+			},
+		},
+	}
+}
+
+func (f *firewallGroupMembersResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*providerdata.Data)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configuration Type",
+			fmt.Sprintf("Expected *providerdata.Data, got %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	f.client = data.Client.FirewallGroup
+}
+
+func (f *firewallGroupMembersResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan firewallGroupMembersResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(f.converge(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, firewallGroupMembersIdentityModel{GroupID: plan.GroupID})...)
+}
+
+func (f *firewallGroupMembersResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state firewallGroupMembersResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	fwGroup, err := f.client.Get(ctx, state.GroupID.ValueString())
+	if err != nil {
+		if apierror.IsNotFound(err) {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+
+		resp.Diagnostics.AddError("Error reading firewall group", err.Error())
+		return
+	}
+
+	members, diags := types.SetValueFrom(ctx, types.StringType, firewallGroupVmIdentifiers(fwGroup))
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	state.ID = state.GroupID
+	state.VmIdentifiers = members
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, firewallGroupMembersIdentityModel{GroupID: state.GroupID})...)
+}
+
+func (f *firewallGroupMembersResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan firewallGroupMembersResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(f.converge(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (f *firewallGroupMembersResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state firewallGroupMembersResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	fwGroup, err := f.client.Get(ctx, state.GroupID.ValueString())
+	if err != nil {
+		if apierror.IsNotFound(err) {
+			return
+		}
+
+		resp.Diagnostics.AddError("Error reading firewall group", err.Error())
+		return
+	}
+
+	for _, vmIdentifier := range firewallGroupVmIdentifiers(fwGroup) {
+		if err := f.client.DetachFromVpsie(ctx, state.GroupID.ValueString(), vmIdentifier); err != nil {
+			resp.Diagnostics.AddError(
+				"Error detaching firewall from VM",
+				fmt.Sprintf("couldn't detach firewall group %s from VM %s, unexpected error: %s", state.GroupID.ValueString(), vmIdentifier, err),
+			)
+		}
+	}
+}
+
+func (f *firewallGroupMembersResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	groupID := types.StringValue(req.ID)
+	if req.ID == "" {
+		var identity firewallGroupMembersIdentityModel
+		resp.Diagnostics.Append(req.Identity.Get(ctx, &identity)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		groupID = identity.GroupID
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("group_id"), groupID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), groupID)...)
+}
+
+// converge attaches and detaches VMs until the group's members match
+// m.VmIdentifiers, then stores the membership reported by the API in m.
+func (f *firewallGroupMembersResource) converge(ctx context.Context, m *firewallGroupMembersResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+	groupID := m.GroupID.ValueString()
+
+	var want []string
+	diags.Append(m.VmIdentifiers.ElementsAs(ctx, &want, false)...)
+	if diags.HasError() {
+		return diags
+	}
+
+	fwGroup, err := f.client.Get(ctx, groupID)
+	if err != nil {
+		diags.AddError("Error reading firewall group", err.Error())
+		return diags
+	}
+
+	attach, detach := diffFirewallGroupMembers(want, firewallGroupVmIdentifiers(fwGroup))
+
+	for _, vmIdentifier := range detach {
+		tflog.Debug(ctx, "Detaching VM from firewall group", map[string]any{"group_id": groupID, "vm_identifier": vmIdentifier})
+		if err := f.client.DetachFromVpsie(ctx, groupID, vmIdentifier); err != nil {
+			diags.AddError(
+				"Error detaching firewall from VM",
+				fmt.Sprintf("couldn't detach firewall group %s from VM %s, unexpected error: %s", groupID, vmIdentifier, err),
+			)
+		}
+	}
+
+	for _, vmIdentifier := range attach {
+		tflog.Debug(ctx, "Attaching VM to firewall group", map[string]any{"group_id": groupID, "vm_identifier": vmIdentifier})
+		if err := f.client.AttachToVpsie(ctx, groupID, vmIdentifier); err != nil {
+			diags.AddError(
+				"Error attaching firewall to VM",
+				fmt.Sprintf("couldn't attach firewall group %s to VM %s, unexpected error: %s", groupID, vmIdentifier, err),
+			)
+		}
+	}
+
+	if diags.HasError() {
+		return diags
+	}
+
+	fwGroup, err = f.client.Get(ctx, groupID)
+	if err != nil {
+		diags.AddError("Error reading firewall group", err.Error())
+		return diags
+	}
+
+	attach, detach = diffFirewallGroupMembers(want, firewallGroupVmIdentifiers(fwGroup))
+	if len(attach) > 0 || len(detach) > 0 {
+		diags.AddError(
+			"Firewall group members did not converge",
+			fmt.Sprintf("After applying changes, firewall group %s is still missing VMs %v and still has extra VMs %v.", groupID, attach, detach),
+		)
+		return diags
+	}
+
+	m.ID = m.GroupID
+	return diags
+}
+
+// firewallGroupVmIdentifiers returns the sorted identifiers of the VMs
+// attached to a firewall group.
+func firewallGroupVmIdentifiers(fwGroup *govpsie.FirewallGroupDetailData) []string {
+	identifiers := make([]string, 0, len(fwGroup.Vms))
+	for _, vm := range fwGroup.Vms {
+		identifiers = append(identifiers, vm.Identifier)
+	}
+	sort.Strings(identifiers)
+
+	return identifiers
+}
+
+// diffFirewallGroupMembers returns the VMs in want that are not attached and
+// the attached VMs that are not in want.
+func diffFirewallGroupMembers(want, have []string) (attach, detach []string) {
+	wanted := make(map[string]bool, len(want))
+	for _, id := range want {
+		wanted[id] = true
+	}
+
+	attached := make(map[string]bool, len(have))
+	for _, id := range have {
+		attached[id] = true
+		if !wanted[id] {
+			detach = append(detach, id)
+		}
+	}
+
+	for _, id := range want {
+		if !attached[id] {
+			attach = append(attach, id)
+		}
+	}
+
+	sort.Strings(attach)
+	sort.Strings(detach)
+
+	return attach, detach
+}
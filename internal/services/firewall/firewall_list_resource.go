@@ -0,0 +1,59 @@
+package firewall
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/list/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/vpsie/govpsie"
+	"github.com/vpsie/terraform-provider-vpsie/internal/listresult"
+)
+
+var (
+	_ list.ListResource              = &firewallListResource{}
+	_ list.ListResourceWithConfigure = &firewallListResource{}
+)
+
+type firewallListResource struct {
+	resource firewallResource
+}
+
+func NewFirewallListResource() list.ListResource {
+	return &firewallListResource{}
+}
+
+func (l *firewallListResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	l.resource.Metadata(ctx, req, resp)
+}
+
+func (l *firewallListResource) ListResourceConfigSchema(_ context.Context, _ list.ListResourceSchemaRequest, resp *list.ListResourceSchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Lists the VPSie firewall groups in the account, for use with `terraform query`.",
+	}
+}
+
+func (l *firewallListResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	l.resource.Configure(ctx, req, resp)
+}
+
+func (l *firewallListResource) List(ctx context.Context, req list.ListRequest, stream *list.ListResultsStream) {
+	firewalls, err := l.resource.client.List(ctx, nil)
+	if err != nil {
+		var diags diag.Diagnostics
+		diags.AddError(
+			"Error listing firewalls",
+			"Could not list firewalls: "+err.Error(),
+		)
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	stream.Results = listresult.Stream(req, firewalls, func(firewall govpsie.FirewallGroupListData) list.ListResult {
+		return listresult.New(ctx, req, &l.resource, firewall.GroupName, firewallIdentityModel{
+			Identifier: types.StringValue(firewall.Identifier),
+		})
+	})
+}
@@ -3,24 +3,36 @@ package firewall
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/vpsie/govpsie"
+	"github.com/vpsie/terraform-provider-vpsie/internal/apierror"
+	"github.com/vpsie/terraform-provider-vpsie/internal/providerdata"
 )
 
 var (
-	_ resource.Resource                = &firewallResource{}
-	_ resource.ResourceWithConfigure   = &firewallResource{}
-	_ resource.ResourceWithImportState = &firewallResource{}
+	_ resource.Resource                   = &firewallResource{}
+	_ resource.ResourceWithConfigure      = &firewallResource{}
+	_ resource.ResourceWithImportState    = &firewallResource{}
+	_ resource.ResourceWithIdentity       = &firewallResource{}
+	_ resource.ResourceWithValidateConfig = &firewallResource{}
+	_ resource.ResourceWithModifyPlan     = &firewallResource{}
 )
 
 type firewallResource struct {
@@ -39,8 +51,51 @@ type firewallResourceModel struct {
 	Vms           types.Int64  `tfsdk:"vms"`
 	CreatedBy     types.Int64  `tfsdk:"created_by"`
 
-	Rules   []FirewallRules `tfsdk:"rules"`
-	VmsData []VmsData       `tfsdk:"vms_data"`
+	Rules   types.List `tfsdk:"rules"`
+	VmsData types.List `tfsdk:"vms_data"`
+}
+
+// firewallRuleAttrTypes mirrors commonResource and is used to build the
+// rules list value stored in state.
+var firewallRuleAttrTypes = map[string]attr.Type{
+	"id":         types.Int64Type,
+	"group_id":   types.Int64Type,
+	"user_id":    types.Int64Type,
+	"action":     types.StringType,
+	"type":       types.StringType,
+	"comment":    types.StringType,
+	"dest":       types.ListType{ElemType: types.StringType},
+	"dport":      types.StringType,
+	"proto":      types.StringType,
+	"source":     types.ListType{ElemType: types.StringType},
+	"sport":      types.StringType,
+	"enable":     types.Int64Type,
+	"iface":      types.StringType,
+	"log":        types.StringType,
+	"macro":      types.StringType,
+	"identifier": types.StringType,
+	"created_on": types.StringType,
+	"updated_on": types.StringType,
+}
+
+var firewallRulesObjectType = types.ObjectType{
+	AttrTypes: map[string]attr.Type{
+		"in_bound":  types.ListType{ElemType: types.ObjectType{AttrTypes: firewallRuleAttrTypes}},
+		"out_bound": types.ListType{ElemType: types.ObjectType{AttrTypes: firewallRuleAttrTypes}},
+	},
+}
+
+var firewallVmsDataObjectType = types.ObjectType{
+	AttrTypes: map[string]attr.Type{
+		"hostname":   types.StringType,
+		"identifier": types.StringType,
+		"fullname":   types.StringType,
+		"category":   types.StringType,
+	},
+}
+
+type firewallIdentityModel struct {
+	Identifier types.String `tfsdk:"identifier"`
 }
 
 func NewFirewallResource() resource.Resource {
@@ -55,140 +110,94 @@ var commonResource map[string]schema.Attribute = map[string]schema.Attribute{
 	"id": schema.Int64Attribute{
 		Computed:            true,
 		MarkdownDescription: "The numeric ID of the firewall rule.",
-		PlanModifiers: []planmodifier.Int64{
-			int64planmodifier.UseStateForUnknown(),
-		},
 	},
 	"group_id": schema.Int64Attribute{
 		Computed:            true,
 		MarkdownDescription: "The ID of the firewall group this rule belongs to.",
-		PlanModifiers: []planmodifier.Int64{
-			int64planmodifier.UseStateForUnknown(),
-		},
 	},
 	"user_id": schema.Int64Attribute{
 		Computed:            true,
 		MarkdownDescription: "The ID of the user who owns the rule.",
-		PlanModifiers: []planmodifier.Int64{
-			int64planmodifier.UseStateForUnknown(),
-		},
 	},
 	"action": schema.StringAttribute{
+		Optional:            true,
 		Computed:            true,
-		MarkdownDescription: "The action to take when the rule matches (e.g., ACCEPT, DROP).",
-		PlanModifiers: []planmodifier.String{
-			stringplanmodifier.UseStateForUnknown(),
-		},
+		MarkdownDescription: "The action to take when the rule matches: `ACCEPT`, `DROP` or `REJECT`.",
 	},
 	"type": schema.StringAttribute{
+		Optional:            true,
 		Computed:            true,
-		MarkdownDescription: "The direction type of the rule (e.g., in, out).",
-		PlanModifiers: []planmodifier.String{
-			stringplanmodifier.UseStateForUnknown(),
-		},
+		MarkdownDescription: "The direction type of the rule (e.g., in, out). Defaults to the direction of the list the rule is declared in.",
 	},
 	"comment": schema.StringAttribute{
+		Optional:            true,
 		Computed:            true,
 		MarkdownDescription: "A comment describing the firewall rule.",
-		PlanModifiers: []planmodifier.String{
-			stringplanmodifier.UseStateForUnknown(),
-		},
 	},
 	"dest": schema.ListAttribute{
+		Optional:            true,
 		Computed:            true,
-		MarkdownDescription: "The destination addresses for the firewall rule.",
-		PlanModifiers: []planmodifier.List{
-			listplanmodifier.UseStateForUnknown(),
-		},
-		ElementType: types.ListType{
-			ElemType: types.StringType,
-		},
+		MarkdownDescription: "The destination IPv4 or IPv6 addresses, CIDR blocks or address ranges for the firewall rule. IPv4 and IPv6 cannot be mixed in one rule.",
+		ElementType:         types.StringType,
 	},
 	"dport": schema.StringAttribute{
+		Optional:            true,
 		Computed:            true,
-		MarkdownDescription: "The destination port or port range for the rule.",
-		PlanModifiers: []planmodifier.String{
-			stringplanmodifier.UseStateForUnknown(),
-		},
+		MarkdownDescription: "The destination ports for the rule, as a comma separated list of ports and ranges (e.g., `80,443,8000:8080`). Not allowed with ICMP.",
 	},
 	"proto": schema.StringAttribute{
+		Optional:            true,
 		Computed:            true,
-		MarkdownDescription: "The protocol for the rule (e.g., tcp, udp, icmp).",
-		PlanModifiers: []planmodifier.String{
-			stringplanmodifier.UseStateForUnknown(),
-		},
+		MarkdownDescription: "The protocol for the rule (e.g., tcp, udp, icmp, icmpv6).",
 	},
 	"source": schema.ListAttribute{
+		Optional:            true,
 		Computed:            true,
-		MarkdownDescription: "The source addresses for the firewall rule.",
-		PlanModifiers: []planmodifier.List{
-			listplanmodifier.UseStateForUnknown(),
-		},
-		ElementType: types.ListType{
-			ElemType: types.StringType,
-		},
+		MarkdownDescription: "The source IPv4 or IPv6 addresses, CIDR blocks or address ranges for the firewall rule. IPv4 and IPv6 cannot be mixed in one rule.",
+		ElementType:         types.StringType,
 	},
 	"sport": schema.StringAttribute{
+		Optional:            true,
 		Computed:            true,
-		MarkdownDescription: "The source port or port range for the rule.",
-		PlanModifiers: []planmodifier.String{
-			stringplanmodifier.UseStateForUnknown(),
-		},
+		MarkdownDescription: "The source ports for the rule, as a comma separated list of ports and ranges. Not allowed with ICMP.",
 	},
 	"enable": schema.Int64Attribute{
+		Optional:            true,
 		Computed:            true,
-		MarkdownDescription: "Whether the rule is enabled (1 = enabled, 0 = disabled).",
-		PlanModifiers: []planmodifier.Int64{
-			int64planmodifier.UseStateForUnknown(),
-		},
+		MarkdownDescription: "Whether the rule is enabled (1 = enabled, 0 = disabled). Defaults to `1`.",
 	},
 	"iface": schema.StringAttribute{
 		Computed:            true,
 		MarkdownDescription: "The network interface the rule applies to.",
-		PlanModifiers: []planmodifier.String{
-			stringplanmodifier.UseStateForUnknown(),
-		},
 	},
 	"log": schema.StringAttribute{
 		Computed:            true,
 		MarkdownDescription: "The logging level for the rule.",
-		PlanModifiers: []planmodifier.String{
-			stringplanmodifier.UseStateForUnknown(),
-		},
 	},
 	"macro": schema.StringAttribute{
+		Optional:            true,
 		Computed:            true,
-		MarkdownDescription: "The macro name for predefined rule sets.",
-		PlanModifiers: []planmodifier.String{
-			stringplanmodifier.UseStateForUnknown(),
-		},
+		MarkdownDescription: "The macro name for predefined rule sets (e.g., SSH, HTTP, PostgreSQL).",
 	},
 	"identifier": schema.StringAttribute{
 		Computed:            true,
 		MarkdownDescription: "The unique identifier of the firewall rule.",
-		PlanModifiers: []planmodifier.String{
-			stringplanmodifier.UseStateForUnknown(),
-		},
 	},
 	"created_on": schema.StringAttribute{
 		Computed:            true,
 		MarkdownDescription: "The timestamp when the rule was created.",
-		PlanModifiers: []planmodifier.String{
-			stringplanmodifier.UseStateForUnknown(),
-		},
 	},
 	"updated_on": schema.StringAttribute{
 		Computed:            true,
 		MarkdownDescription: "The timestamp when the rule was last updated.",
-		PlanModifiers: []planmodifier.String{
-			stringplanmodifier.UseStateForUnknown(),
-		},
 	},
 }
 
 func (g *firewallResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
-		MarkdownDescription: "Manages a firewall group on the VPSie platform.",
+		MarkdownDescription: "Manages a firewall group on the VPSie platform. Rules added to `rules` are applied to the existing group in place, " +
+			"so attached VMs keep their firewall group. The API cannot remove a rule from a group, so a plan that removes or changes a rule fails; " +
+			"replace the group explicitly with `terraform apply -replace` to recreate it, which detaches it from its VMs.",
 		Attributes: map[string]schema.Attribute{
 			"id": schema.Int64Attribute{
 				Computed:            true,
@@ -207,76 +216,68 @@ func (g *firewallResource) Schema(_ context.Context, _ resource.SchemaRequest, r
 					stringvalidator.LengthAtLeast(1),
 				},
 			},
-			"group_id": schema.Int64Attribute{
-				Computed:            true,
-				MarkdownDescription: "The internal group ID of the firewall.",
-				PlanModifiers: []planmodifier.Int64{
-					int64planmodifier.UseStateForUnknown(),
-				},
-			},
-			"user_id": schema.Int64Attribute{
-				Computed:            true,
-				MarkdownDescription: "The ID of the user who owns the firewall group.",
-				PlanModifiers: []planmodifier.Int64{
-					int64planmodifier.UseStateForUnknown(),
-				},
-			},
-			"action": schema.StringAttribute{
+			"user_name": schema.StringAttribute{
 				Computed:            true,
-				MarkdownDescription: "The default action for the firewall group.",
+				MarkdownDescription: "The username of the firewall group owner.",
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
-			"type": schema.StringAttribute{
+			"identifier": schema.StringAttribute{
 				Computed:            true,
-				MarkdownDescription: "The type of the firewall group.",
+				MarkdownDescription: "The unique identifier of the firewall group.",
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
-			"hostname": schema.StringAttribute{
+			"created_on": schema.StringAttribute{
 				Computed:            true,
-				MarkdownDescription: "The hostname associated with the firewall group.",
+				MarkdownDescription: "The timestamp when the firewall group was created.",
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
-			"identifier": schema.StringAttribute{
+			"updated_on": schema.StringAttribute{
 				Computed:            true,
-				MarkdownDescription: "The unique identifier of the firewall group.",
-				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.UseStateForUnknown(),
-				},
+				MarkdownDescription: "The timestamp when the firewall group was last updated.",
 			},
-			"fullname": schema.StringAttribute{
+			"inbound_count": schema.Int64Attribute{
 				Computed:            true,
-				MarkdownDescription: "The full name of the firewall group owner.",
-				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.UseStateForUnknown(),
-				},
+				MarkdownDescription: "The number of inbound rules in the firewall group.",
 			},
-			"category": schema.StringAttribute{
+			"outbound_count": schema.Int64Attribute{
 				Computed:            true,
-				MarkdownDescription: "The category of the firewall group.",
-				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.UseStateForUnknown(),
+				MarkdownDescription: "The number of outbound rules in the firewall group.",
+			},
+			"vms": schema.Int64Attribute{
+				Computed:            true,
+				MarkdownDescription: "The number of VMs attached to the firewall group.",
+			},
+			"created_by": schema.Int64Attribute{
+				Computed:            true,
+				MarkdownDescription: "The ID of the user who created the firewall group.",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
 				},
 			},
 			"rules": schema.ListNestedAttribute{
+				Optional:            true,
 				Computed:            true,
-				MarkdownDescription: "The list of firewall rules in the group.",
+				MarkdownDescription: "The list of firewall rules in the group. When set, the configured rules are authoritative for the group; when omitted, the rules applied to the group are only read. Adding a rule updates the group in place, while removing or changing one fails the plan.",
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.UseStateForUnknown(),
+				},
 				NestedObject: schema.NestedAttributeObject{
 					Attributes: map[string]schema.Attribute{
 						"in_bound": schema.ListNestedAttribute{
-							Computed:            true,
+							Optional:            true,
 							MarkdownDescription: "The list of inbound firewall rules.",
 							NestedObject: schema.NestedAttributeObject{
 								Attributes: commonResource,
 							},
 						},
 						"out_bound": schema.ListNestedAttribute{
-							Computed:            true,
+							Optional:            true,
 							MarkdownDescription: "The list of outbound firewall rules.",
 							NestedObject: schema.NestedAttributeObject{
 								Attributes: commonResource,
@@ -293,30 +294,18 @@ func (g *firewallResource) Schema(_ context.Context, _ resource.SchemaRequest, r
 						"hostname": schema.StringAttribute{
 							Computed:            true,
 							MarkdownDescription: "The hostname of the attached VM.",
-							PlanModifiers: []planmodifier.String{
-								stringplanmodifier.UseStateForUnknown(),
-							},
 						},
 						"identifier": schema.StringAttribute{
 							Computed:            true,
 							MarkdownDescription: "The unique identifier of the attached VM.",
-							PlanModifiers: []planmodifier.String{
-								stringplanmodifier.UseStateForUnknown(),
-							},
 						},
 						"fullname": schema.StringAttribute{
 							Computed:            true,
 							MarkdownDescription: "The full name of the attached VM.",
-							PlanModifiers: []planmodifier.String{
-								stringplanmodifier.UseStateForUnknown(),
-							},
 						},
 						"category": schema.StringAttribute{
 							Computed:            true,
 							MarkdownDescription: "The category of the attached VM.",
-							PlanModifiers: []planmodifier.String{
-								stringplanmodifier.UseStateForUnknown(),
-							},
 						},
 					},
 				},
@@ -325,22 +314,101 @@ func (g *firewallResource) Schema(_ context.Context, _ resource.SchemaRequest, r
 	}
 }
 
+func (g *firewallResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"identifier": identityschema.StringAttribute{
+				RequiredForImport: true,
+				Description:       "The unique identifier of the firewall group.",
+			},
+		},
+	}
+}
+
 func (g *firewallResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
 		return
 	}
 
-	client, ok := req.ProviderData.(*govpsie.Client)
+	data, ok := req.ProviderData.(*providerdata.Data)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configuration Type",
-			fmt.Sprintf("Expected *govpsie.Client, got %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *providerdata.Data, got %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	g.client = client.FirewallGroup
+	g.client = data.Client.FirewallGroup
+}
+
+// ValidateConfig rejects malformed rules at plan time instead of leaving them
+// to fail against the API halfway through an apply.
+func (g *firewallResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var rules types.List
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("rules"), &rules)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(validateFirewallRulesConfig(ctx, rules)...)
+}
+
+// ModifyPlan fails the plan when the configured rules drop or change a rule
+// applied to it. The API can add a rule to a group but not remove one, and
+// replacing the group would detach it from every VM in vms_data, so only
+// additions are planned.
+func (g *firewallResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var stateRules, configRules types.List
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("rules"), &stateRules)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("rules"), &configRules)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Rules that are not known yet are compared in Update, which fails
+	// before pushing anything when a rule would have to be removed.
+	if configRules.IsNull() || !rulesKnown(ctx, req.Config) {
+		return
+	}
+
+	applied, diags := expandFirewallRules(ctx, stateRules)
+	resp.Diagnostics.Append(diags...)
+	desired, diags := expandFirewallRules(ctx, configRules)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if removed := missingFirewallRules(applied, desired); len(removed) > 0 {
+		var identifier types.String
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("identifier"), &identifier)...)
+		resp.Diagnostics.AddAttributeError(
+			path.Root("rules"),
+			"Firewall rules cannot be removed in place",
+			fmt.Sprintf("the configuration removes or changes %d rule(s) of firewall %s: %s. "+
+				"The API cannot remove a rule from a group, and replacing the group would detach it from every VM it is attached to. "+
+				"Keep the rule, or recreate the group with terraform apply -replace and attach its VMs again.",
+				len(removed), identifier.ValueString(), describeFirewallRules(removed)),
+		)
+	}
+}
+
+// rulesKnown reports whether every value of the configured rules is known.
+func rulesKnown(ctx context.Context, config tfsdk.Config) bool {
+	rules, _, err := tftypes.WalkAttributePath(config.Raw, tftypes.NewAttributePath().WithAttributeName("rules"))
+	if err != nil {
+		tflog.Debug(ctx, "Couldn't read configured firewall rules", map[string]any{"error": err.Error()})
+		return false
+	}
+
+	value, ok := rules.(tftypes.Value)
+	return ok && value.IsFullyKnown()
 }
 
 // Create creates the resource and sets the initial Terraform state.
@@ -351,56 +419,14 @@ func (g *firewallResource) Create(ctx context.Context, req resource.CreateReques
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	var rulesToCreate []govpsie.FirewallUpdateReq
-	for _, rule := range plan.Rules {
-		for _, inBound := range rule.InBound {
-			dest := []string{}
-			source := []string{}
-			for _, d := range inBound.Dest.Elements() {
-				dest = append(dest, d.String())
-			}
-			for _, s := range inBound.Source.Elements() {
-				source = append(source, s.String())
-			}
-			rulesToCreate = append(rulesToCreate, govpsie.FirewallUpdateReq{
-				Action:  inBound.Action.String(),
-				Type:    inBound.Type.String(),
-				Comment: inBound.Comment.String(),
-				Dest:    dest,
-				Dport:   inBound.Dport.String(),
-				Proto:   inBound.Proto.String(),
-				Source:  source,
-				Sport:   inBound.Sport.String(),
-				Enable:  inBound.Enable.ValueInt64(),
-				Macro:   inBound.Macro.String(),
-			})
-		}
 
-		for _, outBound := range rule.OutBound {
-			dest := []string{}
-			source := []string{}
-			for _, d := range outBound.Dest.Elements() {
-				dest = append(dest, d.String())
-			}
-			for _, s := range outBound.Source.Elements() {
-				source = append(source, s.String())
-			}
-			rulesToCreate = append(rulesToCreate, govpsie.FirewallUpdateReq{
-				Action:  outBound.Action.String(),
-				Type:    outBound.Type.String(),
-				Comment: outBound.Comment.String(),
-				Dest:    dest,
-				Dport:   outBound.Dport.String(),
-				Proto:   outBound.Proto.String(),
-				Source:  source,
-				Sport:   outBound.Sport.String(),
-				Enable:  outBound.Enable.ValueInt64(),
-				Macro:   outBound.Macro.String(),
-			})
-		}
+	rulesToCreate, diags := expandFirewallRules(ctx, plan.Rules)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	err := g.client.Create(ctx, plan.GroupName.String(), rulesToCreate)
+	err := g.client.Create(ctx, plan.GroupName.ValueString(), rulesToCreate)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error creating firewall",
@@ -409,7 +435,7 @@ func (g *firewallResource) Create(ctx context.Context, req resource.CreateReques
 		return
 	}
 
-	firewall, err := g.GetFirewallGroupByName(ctx, plan.GroupName.String())
+	firewall, err := g.GetFirewallGroupByName(ctx, plan.GroupName.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error reading firewall",
@@ -418,90 +444,10 @@ func (g *firewallResource) Create(ctx context.Context, req resource.CreateReques
 		return
 	}
 
-	var rules []FirewallRules
-	for _, rule := range firewall.Rules {
-		var inBound []InBoundFirewallRules
-		var outBound []OutBoundFirewallRules
-		for _, in := range rule.InBound {
-
-			dest := []types.String{}
-			source := []types.String{}
-			for _, d := range in.Dest {
-				dest = append(dest, types.StringValue(d))
-			}
-			for _, s := range in.Source {
-				source = append(source, types.StringValue(s))
-			}
-
-			destList, destDiags := types.ListValueFrom(ctx, types.StringType, dest)
-			resp.Diagnostics.Append(destDiags...)
-			sourceList, sourceDiags := types.ListValueFrom(ctx, types.StringType, source)
-			resp.Diagnostics.Append(sourceDiags...)
-			if resp.Diagnostics.HasError() {
-				return
-			}
-
-			inBound = append(inBound, InBoundFirewallRules{
-				Action:  types.StringValue(in.Action),
-				Type:    types.StringValue(in.Type),
-				Comment: types.StringValue(in.Comment),
-				Dest:    destList,
-				Dport:   types.StringValue(in.Dport),
-				Proto:   types.StringValue(in.Proto),
-				Source:  sourceList,
-				Sport:   types.StringValue(in.Sport),
-				Enable:  types.Int64Value(in.Enable),
-				Macro:   types.StringValue(in.Macro),
-			})
-		}
-		for _, out := range rule.OutBound {
-
-			dest := []types.String{}
-			source := []types.String{}
-			for _, d := range out.Dest {
-				dest = append(dest, types.StringValue(d))
-			}
-			for _, s := range out.Source {
-				source = append(source, types.StringValue(s))
-			}
-
-			destList, destDiags := types.ListValueFrom(ctx, types.StringType, dest)
-			resp.Diagnostics.Append(destDiags...)
-			sourceList, sourceDiags := types.ListValueFrom(ctx, types.StringType, source)
-			resp.Diagnostics.Append(sourceDiags...)
-			if resp.Diagnostics.HasError() {
-				return
-			}
-
-			outBound = append(outBound, OutBoundFirewallRules{
-				Action:  types.StringValue(out.Action),
-				Type:    types.StringValue(out.Type),
-				Comment: types.StringValue(out.Comment),
-				Dest:    destList,
-				Dport:   types.StringValue(out.Dport),
-				Proto:   types.StringValue(out.Proto),
-				Source:  sourceList,
-				Sport:   types.StringValue(out.Sport),
-				Enable:  types.Int64Value(out.Enable),
-				Macro:   types.StringValue(out.Macro),
-			})
-		}
-		rules = append(rules, FirewallRules{
-			InBound:  inBound,
-			OutBound: outBound,
-		})
-
-	}
-
-	var vmsData []VmsData
-	for _, vm := range firewall.VmsData {
-		vmsData = append(vmsData, VmsData{
-			Hostname:   types.StringValue(vm.Hostname),
-			Identifier: types.StringValue(vm.Identifier),
-			Fullname:   types.StringValue(vm.Fullname),
-			Category:   types.StringValue(vm.Category),
-		})
-
+	rules, diags := flattenFirewallRules(ctx, firewall.Rules)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
 	plan.ID = types.Int64Value(firewall.ID)
@@ -515,13 +461,20 @@ func (g *firewallResource) Create(ctx context.Context, req resource.CreateReques
 	plan.Vms = types.Int64Value(firewall.Vms)
 	plan.CreatedBy = types.Int64Value(firewall.CreatedBy)
 	plan.Rules = rules
-	plan.VmsData = vmsData
+
+	plan.VmsData, diags = flattenVmsData(ctx, firewall.VmsData)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
 	diags = resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, firewallIdentityModel{Identifier: plan.Identifier})...)
 }
 
 // Read refreshes the Terraform state with the latest data.
@@ -533,8 +486,12 @@ func (g *firewallResource) Read(ctx context.Context, req resource.ReadRequest, r
 		return
 	}
 
-	firewall, err := g.client.Get(ctx, state.ID.String())
+	firewall, err := g.client.Get(ctx, state.Identifier.ValueString())
 	if err != nil {
+		if apierror.IsNotFound(err) {
+			resp.State.RemoveResource(ctx)
+			return
+		}
 		resp.Diagnostics.AddError(
 			"Error reading firewall",
 			"couldn't read firewall, unexpected error: "+err.Error(),
@@ -542,115 +499,121 @@ func (g *firewallResource) Read(ctx context.Context, req resource.ReadRequest, r
 		return
 	}
 
-	var rules []FirewallRules
-	for _, rule := range firewall.Rules {
-		var inBound []InBoundFirewallRules
-		var outBound []OutBoundFirewallRules
-		for _, in := range rule.InBound {
+	resp.Diagnostics.Append(state.refresh(ctx, firewall)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-			dest := []types.String{}
-			source := []types.String{}
-			for _, d := range in.Dest {
-				dest = append(dest, types.StringValue(d))
-			}
-			for _, s := range in.Source {
-				source = append(source, types.StringValue(s))
-			}
-
-			destList, destDiags := types.ListValueFrom(ctx, types.StringType, dest)
-			resp.Diagnostics.Append(destDiags...)
-			sourceList, sourceDiags := types.ListValueFrom(ctx, types.StringType, source)
-			resp.Diagnostics.Append(sourceDiags...)
-			if resp.Diagnostics.HasError() {
-				return
-			}
-
-			inBound = append(inBound, InBoundFirewallRules{
-				Action:  types.StringValue(in.Action),
-				Type:    types.StringValue(in.Type),
-				Comment: types.StringValue(in.Comment),
-				Dest:    destList,
-				Dport:   types.StringValue(in.Dport),
-				Proto:   types.StringValue(in.Proto),
-				Source:  sourceList,
-				Sport:   types.StringValue(in.Sport),
-				Enable:  types.Int64Value(in.Enable),
-				Macro:   types.StringValue(in.Macro),
-			})
-		}
-		for _, out := range rule.OutBound {
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-			dest := []types.String{}
-			source := []types.String{}
-			for _, d := range out.Dest {
-				dest = append(dest, types.StringValue(d))
-			}
-			for _, s := range out.Source {
-				source = append(source, types.StringValue(s))
-			}
-
-			destList, destDiags := types.ListValueFrom(ctx, types.StringType, dest)
-			resp.Diagnostics.Append(destDiags...)
-			sourceList, sourceDiags := types.ListValueFrom(ctx, types.StringType, source)
-			resp.Diagnostics.Append(sourceDiags...)
-			if resp.Diagnostics.HasError() {
-				return
-			}
-
-			outBound = append(outBound, OutBoundFirewallRules{
-				Action:  types.StringValue(out.Action),
-				Type:    types.StringValue(out.Type),
-				Comment: types.StringValue(out.Comment),
-				Dest:    destList,
-				Dport:   types.StringValue(out.Dport),
-				Proto:   types.StringValue(out.Proto),
-				Source:  sourceList,
-				Sport:   types.StringValue(out.Sport),
-				Enable:  types.Int64Value(out.Enable),
-				Macro:   types.StringValue(out.Macro),
-			})
-		}
-		rules = append(rules, FirewallRules{
-			InBound:  inBound,
-			OutBound: outBound,
-		})
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, firewallIdentityModel{Identifier: state.Identifier})...)
+}
 
+// Update updates the resource and sets the updated Terraform state on success.
+//
+// The configured rules are compared with the rules currently applied to the
+// group, and only the rules missing from the group are pushed, so the group
+// keeps its identifier and its VM attachments. ModifyPlan fails the plan
+// when a rule has to go away.
+func (g *firewallResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan firewallResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	var vmsData []VmsData
-	for _, vm := range firewall.Vms {
-		vmsData = append(vmsData, VmsData{
-			Hostname:   types.StringValue(vm.Hostname),
-			Identifier: types.StringValue(vm.Identifier),
-			Fullname:   types.StringValue(vm.Fullname),
-			Category:   types.StringValue(vm.Category),
-		})
+	var configRules types.List
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("rules"), &configRules)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
+	var state firewallResourceModel
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	state.ID = types.Int64Value(firewall.Group.ID)
-	state.UserName = types.StringValue(firewall.Group.UserName)
-	state.GroupName = types.StringValue(firewall.Group.GroupName)
-	state.Identifier = types.StringValue(firewall.Group.Identifier)
-	state.CreatedOn = types.StringValue(firewall.Group.CreatedOn)
-	state.UpdatedOn = types.StringValue(firewall.Group.UpdatedOn)
-	state.InboundCount = types.Int64Value(firewall.Group.InboundCount)
-	state.OutboundCount = types.Int64Value(firewall.Group.OutboundCount)
-	state.Vms = types.Int64Value(firewall.Group.Vms)
-	state.CreatedBy = types.Int64Value(firewall.Group.CreatedBy)
-	state.Rules = rules
-	state.VmsData = vmsData
+	groupIdentifier := state.Identifier.ValueString()
 
-	diags = resp.State.Set(ctx, &state)
+	current, err := g.client.Get(ctx, groupIdentifier)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error reading firewall",
+			"couldn't read firewall "+groupIdentifier+", unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	desired, diags := expandFirewallRules(ctx, configRules)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
-}
 
-// Update updates the resource and sets the updated Terraform state on success.
-func (g *firewallResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// Without configured rules the group's rules are only read. Otherwise a
+	// rule that is applied but not configured, such as one added outside
+	// Terraform since the plan, cannot be removed, so nothing is pushed.
+	var toPush []govpsie.FirewallUpdateReq
+	if !configRules.IsNull() {
+		live := firewallRulesFromAPI(current.Rules)
+		if extra := missingFirewallRules(live, desired); len(extra) > 0 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("rules"),
+				"Firewall rules cannot be removed in place",
+				fmt.Sprintf("firewall %s has %d rule(s) that are not in the configuration: %s. "+
+					"The API cannot remove a rule from a group and no rule was added. Add the rule to the configuration, "+
+					"or recreate the group with terraform apply -replace and attach its VMs again.",
+					groupIdentifier, len(extra), describeFirewallRules(extra)),
+			)
+			return
+		}
+
+		toPush = missingFirewallRules(desired, live)
+	}
+
+	for i := range toPush {
+		tflog.Debug(ctx, "Pushing firewall group rule", map[string]any{
+			"group":  groupIdentifier,
+			"type":   toPush[i].Type,
+			"action": toPush[i].Action,
+			"proto":  toPush[i].Proto,
+			"dport":  toPush[i].Dport,
+		})
+
+		err := g.client.Update(ctx, &toPush[i], groupIdentifier)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error updating firewall",
+				"couldn't update firewall "+groupIdentifier+", unexpected error: "+err.Error(),
+			)
+			return
+		}
+	}
+
+	firewall, err := g.client.Get(ctx, groupIdentifier)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error reading firewall",
+			"couldn't read firewall "+groupIdentifier+", unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	plan.Identifier = state.Identifier
+	resp.Diagnostics.Append(plan.refresh(ctx, firewall)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
+	diags = resp.State.Set(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
 }
 
 // Delete deletes the resource and removes the Terraform state on success.
@@ -662,7 +625,7 @@ func (f *firewallResource) Delete(ctx context.Context, req resource.DeleteReques
 		return
 	}
 
-	err := f.client.Delete(ctx, state.ID.String())
+	err := f.client.Delete(ctx, state.Identifier.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error deleting firewall",
@@ -673,7 +636,7 @@ func (f *firewallResource) Delete(ctx context.Context, req resource.DeleteReques
 }
 
 func (f *firewallResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("identifier"), req, resp)
+	resource.ImportStatePassthroughWithIdentity(ctx, path.Root("identifier"), path.Root("identifier"), req, resp)
 }
 
 func (f *firewallResource) GetFirewallGroupByName(ctx context.Context, name string) (*govpsie.FirewallGroupListData, error) {
@@ -688,5 +651,264 @@ func (f *firewallResource) GetFirewallGroupByName(ctx context.Context, name stri
 		}
 	}
 
-	return nil, fmt.Errorf("firewall group not found: %s", name)
+	return nil, apierror.NotFoundf("firewall group not found: %s", name)
+}
+
+// refresh overwrites the model with the group details returned by the API.
+func (m *firewallResourceModel) refresh(ctx context.Context, firewall *govpsie.FirewallGroupDetailData) diag.Diagnostics {
+	rules, diags := flattenFirewallRules(ctx, firewall.Rules)
+	if diags.HasError() {
+		return diags
+	}
+
+	m.ID = types.Int64Value(firewall.Group.ID)
+	m.UserName = types.StringValue(firewall.Group.UserName)
+	m.GroupName = types.StringValue(firewall.Group.GroupName)
+	m.Identifier = types.StringValue(firewall.Group.Identifier)
+	m.CreatedOn = types.StringValue(firewall.Group.CreatedOn)
+	m.UpdatedOn = types.StringValue(firewall.Group.UpdatedOn)
+	m.InboundCount = types.Int64Value(firewall.Group.InboundCount)
+	m.OutboundCount = types.Int64Value(firewall.Group.OutboundCount)
+	m.Vms = types.Int64Value(firewall.Group.Vms)
+	m.CreatedBy = types.Int64Value(firewall.Group.CreatedBy)
+	m.Rules = rules
+
+	m.VmsData, diags = flattenVmsData(ctx, firewall.Vms)
+
+	return diags
+}
+
+// expandFirewallRules converts the configured rules into the request shape
+// accepted by the firewall group API. Rules without an explicit type take the
+// direction of the list they are declared in.
+func expandFirewallRules(ctx context.Context, list types.List) ([]govpsie.FirewallUpdateReq, diag.Diagnostics) {
+	expanded := []govpsie.FirewallUpdateReq{}
+	if list.IsNull() || list.IsUnknown() {
+		return expanded, nil
+	}
+
+	var rules []FirewallRules
+	diags := list.ElementsAs(ctx, &rules, false)
+	if diags.HasError() {
+		return expanded, diags
+	}
+
+	for _, rule := range rules {
+		for _, in := range rule.InBound {
+			req, d := expandFirewallRule(ctx, in, "in")
+			diags.Append(d...)
+			expanded = append(expanded, req)
+		}
+		for _, out := range rule.OutBound {
+			req, d := expandFirewallRule(ctx, InBoundFirewallRules(out), "out")
+			diags.Append(d...)
+			expanded = append(expanded, req)
+		}
+	}
+
+	return expanded, diags
+}
+
+func expandFirewallRule(ctx context.Context, rule InBoundFirewallRules, direction string) (govpsie.FirewallUpdateReq, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	dest := []string{}
+	if !rule.Dest.IsNull() && !rule.Dest.IsUnknown() {
+		diags.Append(rule.Dest.ElementsAs(ctx, &dest, false)...)
+	}
+
+	source := []string{}
+	if !rule.Source.IsNull() && !rule.Source.IsUnknown() {
+		diags.Append(rule.Source.ElementsAs(ctx, &source, false)...)
+	}
+
+	ruleType := rule.Type.ValueString()
+	if ruleType == "" {
+		ruleType = direction
+	}
+
+	enable := int64(1)
+	if !rule.Enable.IsNull() && !rule.Enable.IsUnknown() {
+		enable = rule.Enable.ValueInt64()
+	}
+
+	return govpsie.FirewallUpdateReq{
+		Action:  rule.Action.ValueString(),
+		Type:    ruleType,
+		Comment: rule.Comment.ValueString(),
+		Dest:    dest,
+		Dport:   rule.Dport.ValueString(),
+		Proto:   rule.Proto.ValueString(),
+		Source:  source,
+		Sport:   rule.Sport.ValueString(),
+		Enable:  enable,
+		Macro:   rule.Macro.ValueString(),
+	}, diags
+}
+
+// firewallRulesFromAPI converts the rules applied to a group into the request
+// shape so they can be compared with the configured rules.
+func firewallRulesFromAPI(rules []govpsie.FirewallRules) []govpsie.FirewallUpdateReq {
+	converted := []govpsie.FirewallUpdateReq{}
+
+	for _, rule := range rules {
+		for _, in := range rule.InBound {
+			converted = append(converted, firewallRuleRequest(in))
+		}
+		for _, out := range rule.OutBound {
+			converted = append(converted, firewallRuleRequest(govpsie.InBoundFirewallRules(out)))
+		}
+	}
+
+	return converted
+}
+
+func firewallRuleRequest(rule govpsie.InBoundFirewallRules) govpsie.FirewallUpdateReq {
+	return govpsie.FirewallUpdateReq{
+		Action:  rule.Action,
+		Type:    rule.Type,
+		Comment: rule.Comment,
+		Dest:    rule.Dest,
+		Dport:   rule.Dport,
+		Proto:   rule.Proto,
+		Source:  rule.Source,
+		Sport:   rule.Sport,
+		Enable:  rule.Enable,
+		Macro:   rule.Macro,
+	}
+}
+
+// missingFirewallRules returns the rules in want that have no counterpart in
+// have. Duplicate rules are matched one to one.
+func missingFirewallRules(want, have []govpsie.FirewallUpdateReq) []govpsie.FirewallUpdateReq {
+	available := make(map[string]int, len(have))
+	for _, rule := range have {
+		available[firewallRuleKey(rule)]++
+	}
+
+	var missing []govpsie.FirewallUpdateReq
+	for _, rule := range want {
+		key := firewallRuleKey(rule)
+		if available[key] > 0 {
+			available[key]--
+			continue
+		}
+		missing = append(missing, rule)
+	}
+
+	return missing
+}
+
+func firewallRuleKey(rule govpsie.FirewallUpdateReq) string {
+	return strings.Join([]string{
+		strings.ToLower(rule.Type),
+		strings.ToUpper(rule.Action),
+		strings.ToLower(rule.Proto),
+		rule.Dport,
+		rule.Sport,
+		strings.Join(rule.Source, ","),
+		strings.Join(rule.Dest, ","),
+		rule.Macro,
+		rule.Comment,
+		fmt.Sprint(rule.Enable),
+	}, "|")
+}
+
+func describeFirewallRules(rules []govpsie.FirewallUpdateReq) string {
+	described := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		described = append(described, fmt.Sprintf("%s %s %s dport=%q", rule.Type, rule.Action, rule.Proto, rule.Dport))
+	}
+
+	return strings.Join(described, ", ")
+}
+
+// flattenFirewallRules converts the rules returned by the API into the
+// Terraform list value stored in state.
+func flattenFirewallRules(ctx context.Context, rules []govpsie.FirewallRules) (types.List, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	flattened := []FirewallRules{}
+
+	for _, rule := range rules {
+		var inBound []InBoundFirewallRules
+		var outBound []OutBoundFirewallRules
+
+		for _, in := range rule.InBound {
+			r, d := flattenFirewallRule(ctx, in)
+			diags.Append(d...)
+			inBound = append(inBound, r)
+		}
+		for _, out := range rule.OutBound {
+			r, d := flattenFirewallRule(ctx, govpsie.InBoundFirewallRules(out))
+			diags.Append(d...)
+			outBound = append(outBound, OutBoundFirewallRules(r))
+		}
+
+		flattened = append(flattened, FirewallRules{
+			InBound:  inBound,
+			OutBound: outBound,
+		})
+	}
+
+	if diags.HasError() {
+		return types.ListNull(firewallRulesObjectType), diags
+	}
+
+	list, d := types.ListValueFrom(ctx, firewallRulesObjectType, flattened)
+	diags.Append(d...)
+
+	return list, diags
+}
+
+func flattenFirewallRule(ctx context.Context, rule govpsie.InBoundFirewallRules) (InBoundFirewallRules, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	dest := rule.Dest
+	if dest == nil {
+		dest = []string{}
+	}
+	source := rule.Source
+	if source == nil {
+		source = []string{}
+	}
+
+	destList, d := types.ListValueFrom(ctx, types.StringType, dest)
+	diags.Append(d...)
+	sourceList, d := types.ListValueFrom(ctx, types.StringType, source)
+	diags.Append(d...)
+
+	return InBoundFirewallRules{
+		ID:         types.Int64Value(rule.ID),
+		GroupID:    types.Int64Value(rule.GroupID),
+		UserID:     types.Int64Value(rule.UserID),
+		Action:     types.StringValue(rule.Action),
+		Type:       types.StringValue(rule.Type),
+		Comment:    types.StringValue(rule.Comment),
+		Dest:       destList,
+		Dport:      types.StringValue(rule.Dport),
+		Proto:      types.StringValue(rule.Proto),
+		Source:     sourceList,
+		Sport:      types.StringValue(rule.Sport),
+		Enable:     types.Int64Value(rule.Enable),
+		Iface:      types.StringValue(rule.Iface),
+		Log:        types.StringValue(rule.Log),
+		Macro:      types.StringValue(rule.Macro),
+		Identifier: types.StringValue(rule.Identifier),
+		CreatedOn:  types.StringValue(rule.CreatedOn.String()),
+		UpdatedOn:  types.StringValue(rule.UpdatedOn.String()),
+	}, diags
+}
+
+func flattenVmsData(ctx context.Context, vms []govpsie.VmsData) (types.List, diag.Diagnostics) {
+	flattened := []VmsData{}
+	for _, vm := range vms {
+		flattened = append(flattened, VmsData{
+			Hostname:   types.StringValue(vm.Hostname),
+			Identifier: types.StringValue(vm.Identifier),
+			Fullname:   types.StringValue(vm.Fullname),
+			Category:   types.StringValue(vm.Category),
+		})
+	}
+
+	return types.ListValueFrom(ctx, firewallVmsDataObjectType, flattened)
 }
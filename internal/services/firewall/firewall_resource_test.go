@@ -5,6 +5,10 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/vpsie/govpsie"
 )
 
@@ -14,6 +18,7 @@ type mockFirewallAPI struct {
 	ListFn            func(ctx context.Context, options *govpsie.ListOptions) ([]govpsie.FirewallGroupListData, error)
 	GetFn             func(ctx context.Context, fwGroupId string) (*govpsie.FirewallGroupDetailData, error)
 	DeleteFn          func(ctx context.Context, fwGroupId string) error
+	UpdateFn          func(ctx context.Context, fwGroupReq *govpsie.FirewallUpdateReq, fwGroupId string) error
 	AttachToVpsieFn   func(ctx context.Context, groupId, vmId string) error
 	DetachFromVpsieFn func(ctx context.Context, groupId, vmId string) error
 }
@@ -34,6 +39,10 @@ func (m *mockFirewallAPI) Delete(ctx context.Context, fwGroupId string) error {
 	return m.DeleteFn(ctx, fwGroupId)
 }
 
+func (m *mockFirewallAPI) Update(ctx context.Context, fwGroupReq *govpsie.FirewallUpdateReq, fwGroupId string) error {
+	return m.UpdateFn(ctx, fwGroupReq, fwGroupId)
+}
+
 func (m *mockFirewallAPI) AttachToVpsie(ctx context.Context, groupId, vmId string) error {
 	return m.AttachToVpsieFn(ctx, groupId, vmId)
 }
@@ -59,6 +68,9 @@ func TestUnitFirewallAPI_MockSatisfiesInterface(t *testing.T) {
 		DeleteFn: func(ctx context.Context, fwGroupId string) error {
 			return nil
 		},
+		UpdateFn: func(ctx context.Context, fwGroupReq *govpsie.FirewallUpdateReq, fwGroupId string) error {
+			return nil
+		},
 		AttachToVpsieFn: func(ctx context.Context, groupId, vmId string) error {
 			return nil
 		},
@@ -255,3 +267,233 @@ func TestUnitFirewallAPI_CreateErrorPropagation(t *testing.T) {
 		t.Fatalf("expected error message 'API error: quota exceeded', got %q", err.Error())
 	}
 }
+
+func TestUnitFirewallRules_MissingFirewallRules(t *testing.T) {
+	ssh := govpsie.FirewallUpdateReq{Action: "ACCEPT", Type: "in", Proto: "tcp", Dport: "22", Enable: 1}
+	http := govpsie.FirewallUpdateReq{Action: "ACCEPT", Type: "in", Proto: "tcp", Dport: "80", Enable: 1}
+	https := govpsie.FirewallUpdateReq{Action: "ACCEPT", Type: "in", Proto: "tcp", Dport: "443", Enable: 1}
+
+	tests := []struct {
+		name      string
+		want      []govpsie.FirewallUpdateReq
+		have      []govpsie.FirewallUpdateReq
+		expectLen int
+		expect    string
+	}{
+		{
+			name:      "nothing missing",
+			want:      []govpsie.FirewallUpdateReq{ssh, http},
+			have:      []govpsie.FirewallUpdateReq{http, ssh},
+			expectLen: 0,
+		},
+		{
+			name:      "changed port",
+			want:      []govpsie.FirewallUpdateReq{ssh, https},
+			have:      []govpsie.FirewallUpdateReq{ssh, http},
+			expectLen: 1,
+			expect:    "443",
+		},
+		{
+			name:      "duplicate rules are matched one to one",
+			want:      []govpsie.FirewallUpdateReq{ssh, ssh},
+			have:      []govpsie.FirewallUpdateReq{ssh},
+			expectLen: 1,
+			expect:    "22",
+		},
+		{
+			name:      "case differences are ignored",
+			want:      []govpsie.FirewallUpdateReq{{Action: "accept", Type: "IN", Proto: "TCP", Dport: "22", Enable: 1}},
+			have:      []govpsie.FirewallUpdateReq{ssh},
+			expectLen: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			missing := missingFirewallRules(tt.want, tt.have)
+			if len(missing) != tt.expectLen {
+				t.Fatalf("expected %d missing rules, got %d: %+v", tt.expectLen, len(missing), missing)
+			}
+			if tt.expectLen > 0 && missing[0].Dport != tt.expect {
+				t.Fatalf("expected missing rule with dport %q, got %q", tt.expect, missing[0].Dport)
+			}
+		})
+	}
+}
+
+func TestUnitFirewallRules_ExpandDefaultsDirectionAndEnable(t *testing.T) {
+	ctx := t.Context()
+
+	rules, diags := flattenFirewallRules(ctx, []govpsie.FirewallRules{
+		{
+			InBound:  []govpsie.InBoundFirewallRules{{Action: "ACCEPT", Proto: "tcp", Dport: "22", Enable: 1}},
+			OutBound: []govpsie.OutBoundFirewallRules{{Action: "DROP", Proto: "udp", Dport: "53", Enable: 0}},
+		},
+	})
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+
+	expanded, diags := expandFirewallRules(ctx, rules)
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if len(expanded) != 2 {
+		t.Fatalf("expected 2 rules, got %d", len(expanded))
+	}
+	if expanded[0].Type != "in" || expanded[1].Type != "out" {
+		t.Fatalf("expected types in/out, got %q/%q", expanded[0].Type, expanded[1].Type)
+	}
+	if expanded[1].Enable != 0 {
+		t.Fatalf("expected explicit enable 0 to be kept, got %d", expanded[1].Enable)
+	}
+}
+
+func TestUnitFirewallRule_FindCreatedFirewallRule(t *testing.T) {
+	existing := govpsie.InBoundFirewallRules{Identifier: "rule-1", Action: "ACCEPT", Type: "in", Proto: "tcp", Dport: "22", Enable: 1}
+	created := govpsie.InBoundFirewallRules{Identifier: "rule-2", Action: "ACCEPT", Type: "in", Proto: "tcp", Dport: "22", Enable: 1}
+	other := govpsie.OutBoundFirewallRules{Identifier: "rule-3", Action: "DROP", Type: "out", Proto: "udp", Dport: "53", Enable: 1}
+
+	before := []govpsie.FirewallRules{{InBound: []govpsie.InBoundFirewallRules{existing}}}
+	after := []govpsie.FirewallRules{{
+		InBound:  []govpsie.InBoundFirewallRules{existing, created},
+		OutBound: []govpsie.OutBoundFirewallRules{other},
+	}}
+
+	rule, err := findCreatedFirewallRule(before, after, govpsie.FirewallUpdateReq{Action: "ACCEPT", Type: "in", Proto: "tcp", Dport: "22", Enable: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rule.Identifier != "rule-2" {
+		t.Fatalf("expected rule-2, got %q", rule.Identifier)
+	}
+
+	if _, err := findCreatedFirewallRule(before, before, govpsie.FirewallUpdateReq{Action: "ACCEPT", Type: "in", Proto: "tcp", Dport: "22", Enable: 1}); err == nil {
+		t.Fatal("expected error when no new rule was added, got nil")
+	}
+
+	if found, ok := findFirewallRule(after, "rule-3"); !ok || found.Type != "out" {
+		t.Fatalf("expected outbound rule-3 to be found, got %+v", found)
+	}
+}
+
+func TestUnitFirewallRule_Validate(t *testing.T) {
+	addrs := func(values ...string) types.List {
+		elems := make([]attr.Value, len(values))
+		for i, v := range values {
+			elems[i] = types.StringValue(v)
+		}
+		return types.ListValueMust(types.StringType, elems)
+	}
+
+	rule := func(mutate func(*InBoundFirewallRules)) InBoundFirewallRules {
+		r := InBoundFirewallRules{
+			Action: types.StringValue("ACCEPT"),
+			Type:   types.StringNull(),
+			Proto:  types.StringValue("tcp"),
+			Dport:  types.StringValue("22"),
+			Sport:  types.StringNull(),
+			Macro:  types.StringNull(),
+			Source: addrs("10.0.0.0/8"),
+			Dest:   types.ListNull(types.StringType),
+		}
+		if mutate != nil {
+			mutate(&r)
+		}
+		return r
+	}
+
+	tests := []struct {
+		name      string
+		rule      InBoundFirewallRules
+		wantError string
+	}{
+		{name: "valid", rule: rule(nil)},
+		{name: "valid port list and ranges", rule: rule(func(r *InBoundFirewallRules) { r.Dport = types.StringValue("80,443,8000:8080,9000-9100") })},
+		{name: "valid ipv6", rule: rule(func(r *InBoundFirewallRules) { r.Source = addrs("2001:db8::/32"); r.Dest = addrs("2001:db8::1") })},
+		{name: "unknown values skipped", rule: rule(func(r *InBoundFirewallRules) {
+			r.Dport = types.StringUnknown()
+			r.Source = types.ListUnknown(types.StringType)
+		})},
+		{name: "bad action", rule: rule(func(r *InBoundFirewallRules) { r.Action = types.StringValue("ALLOW") }), wantError: "rules[0].in_bound[1].action"},
+		{name: "type mismatch", rule: rule(func(r *InBoundFirewallRules) { r.Type = types.StringValue("out") }), wantError: "rules[0].in_bound[1].type"},
+		{name: "bad cidr", rule: rule(func(r *InBoundFirewallRules) { r.Source = addrs("10.0.0.0/33") }), wantError: "rules[0].in_bound[1].source"},
+		{name: "family mismatch", rule: rule(func(r *InBoundFirewallRules) { r.Dest = addrs("2001:db8::1") }), wantError: "rules[0].in_bound[1].dest"},
+		{name: "reversed port range", rule: rule(func(r *InBoundFirewallRules) { r.Dport = types.StringValue("2000:1000") }), wantError: "rules[0].in_bound[1].dport"},
+		{name: "malformed port range", rule: rule(func(r *InBoundFirewallRules) { r.Sport = types.StringValue("1000-") }), wantError: "rules[0].in_bound[1].sport"},
+		{name: "port out of range", rule: rule(func(r *InBoundFirewallRules) { r.Dport = types.StringValue("70000") }), wantError: "rules[0].in_bound[1].dport"},
+		{name: "port with icmp", rule: rule(func(r *InBoundFirewallRules) { r.Proto = types.StringValue("icmp") }), wantError: "rules[0].in_bound[1].dport"},
+		{name: "icmp with ipv6", rule: rule(func(r *InBoundFirewallRules) {
+			r.Proto = types.StringValue("icmp")
+			r.Dport = types.StringNull()
+			r.Source = addrs("2001:db8::/32")
+		}), wantError: "rules[0].in_bound[1].proto"},
+		{name: "unknown macro", rule: rule(func(r *InBoundFirewallRules) { r.Macro = types.StringValue("Postgres") }), wantError: "rules[0].in_bound[1].macro"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rulePath := path.Root("rules").AtListIndex(0).AtName("in_bound").AtListIndex(1)
+			diags := validateFirewallRule(tt.rule, "in", rulePath)
+
+			if tt.wantError == "" {
+				if diags.HasError() {
+					t.Fatalf("unexpected diagnostics: %v", diags)
+				}
+				return
+			}
+
+			if diags.ErrorsCount() != 1 {
+				t.Fatalf("expected 1 error, got %v", diags)
+			}
+			withPath, ok := diags.Errors()[0].(diag.DiagnosticWithPath)
+			if !ok || withPath.Path().String() != tt.wantError {
+				t.Fatalf("expected error at %s, got %v", tt.wantError, diags)
+			}
+		})
+	}
+}
+
+func TestUnitFirewallGroupMembers_Converge(t *testing.T) {
+	ctx := t.Context()
+
+	attached := map[string]bool{"vm-1": true, "vm-out-of-band": true}
+	var calls []string
+
+	r := &firewallGroupMembersResource{client: &mockFirewallAPI{
+		GetFn: func(ctx context.Context, fwGroupId string) (*govpsie.FirewallGroupDetailData, error) {
+			group := &govpsie.FirewallGroupDetailData{}
+			for id := range attached {
+				group.Vms = append(group.Vms, govpsie.VmsData{Identifier: id})
+			}
+			return group, nil
+		},
+		AttachToVpsieFn: func(ctx context.Context, groupId, vmId string) error {
+			calls = append(calls, "attach "+vmId)
+			attached[vmId] = true
+			return nil
+		},
+		DetachFromVpsieFn: func(ctx context.Context, groupId, vmId string) error {
+			calls = append(calls, "detach "+vmId)
+			delete(attached, vmId)
+			return nil
+		},
+	}}
+
+	want, diags := types.SetValueFrom(ctx, types.StringType, []string{"vm-1", "vm-2"})
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+
+	model := firewallGroupMembersResourceModel{GroupID: types.StringValue("group-1"), VmIdentifiers: want}
+	if diags := r.converge(ctx, &model); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+
+	if fmt.Sprint(calls) != "[detach vm-out-of-band attach vm-2]" {
+		t.Fatalf("unexpected calls: %v", calls)
+	}
+	if model.ID.ValueString() != "group-1" {
+		t.Fatalf("expected ID group-1, got %q", model.ID.ValueString())
+	}
+}
@@ -0,0 +1,607 @@
+package firewall
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/vpsie/govpsie"
+	"github.com/vpsie/terraform-provider-vpsie/internal/apierror"
+	"github.com/vpsie/terraform-provider-vpsie/internal/providerdata"
+)
+
+var (
+	_ resource.Resource                   = &firewallRuleResource{}
+	_ resource.ResourceWithConfigure      = &firewallRuleResource{}
+	_ resource.ResourceWithImportState    = &firewallRuleResource{}
+	_ resource.ResourceWithIdentity       = &firewallRuleResource{}
+	_ resource.ResourceWithValidateConfig = &firewallRuleResource{}
+	_ resource.ResourceWithModifyPlan     = &firewallRuleResource{}
+)
+
+type firewallRuleResource struct {
+	client FirewallAPI
+}
+
+type firewallRuleResourceModel struct {
+	ID              types.String `tfsdk:"id"`
+	GroupIdentifier types.String `tfsdk:"group_identifier"`
+	Identifier      types.String `tfsdk:"identifier"`
+	Action          types.String `tfsdk:"action"`
+	Type            types.String `tfsdk:"type"`
+	Comment         types.String `tfsdk:"comment"`
+	Dest            types.List   `tfsdk:"dest"`
+	Dport           types.String `tfsdk:"dport"`
+	Proto           types.String `tfsdk:"proto"`
+	Source          types.List   `tfsdk:"source"`
+	Sport           types.String `tfsdk:"sport"`
+	Enable          types.Int64  `tfsdk:"enable"`
+	Iface           types.String `tfsdk:"iface"`
+	Log             types.String `tfsdk:"log"`
+	Macro           types.String `tfsdk:"macro"`
+	CreatedOn       types.String `tfsdk:"created_on"`
+	UpdatedOn       types.String `tfsdk:"updated_on"`
+
+	AllowOrphanOnDelete types.Bool `tfsdk:"allow_orphan_on_delete"`
+}
+
+type firewallRuleIdentityModel struct {
+	GroupIdentifier types.String `tfsdk:"group_identifier"`
+	Identifier      types.String `tfsdk:"identifier"`
+}
+
+func NewFirewallRuleResource() resource.Resource {
+	return &firewallRuleResource{}
+}
+
+func (f *firewallRuleResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_firewall_rule"
+}
+
+func (f *firewallRuleResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Manages a single rule inside an existing firewall group on the VPSie platform. " +
+			"Use it with a `vpsie_firewall` that does not set `rules`, so that several configurations can add rules to a shared group. " +
+			"The VPSie API does not expose deleting individual rules, so destroying or replacing this resource fails unless `allow_orphan_on_delete` is set, " +
+			"in which case the rule is only removed from state and stays applied to the group.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The composite ID of the firewall rule (group_identifier/identifier).",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"group_identifier": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "The identifier of the firewall group the rule belongs to. Changing this forces a new resource.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"identifier": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The unique identifier of the firewall rule.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"action": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "The action to take when the rule matches: `ACCEPT`, `DROP` or `REJECT`. Changing this forces a new resource.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"type": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "The direction of the rule, either `in` or `out`. Changing this forces a new resource.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.OneOf("in", "out"),
+				},
+			},
+			"comment": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString(""),
+				MarkdownDescription: "A comment describing the firewall rule. Changing this forces a new resource.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"dest": schema.ListAttribute{
+				Optional:            true,
+				Computed:            true,
+				ElementType:         types.StringType,
+				Default:             listdefault.StaticValue(types.ListValueMust(types.StringType, []attr.Value{})),
+				MarkdownDescription: "The destination IPv4 or IPv6 addresses, CIDR blocks or address ranges for the firewall rule. IPv4 and IPv6 cannot be mixed in one rule. Changing this forces a new resource.",
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.RequiresReplace(),
+				},
+			},
+			"dport": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString(""),
+				MarkdownDescription: "The destination ports for the rule, as a comma separated list of ports and ranges (e.g., `80,443,8000:8080`). Not allowed with ICMP. Changing this forces a new resource.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"proto": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString(""),
+				MarkdownDescription: "The protocol for the rule (e.g., tcp, udp, icmp, icmpv6). Changing this forces a new resource.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"source": schema.ListAttribute{
+				Optional:            true,
+				Computed:            true,
+				ElementType:         types.StringType,
+				Default:             listdefault.StaticValue(types.ListValueMust(types.StringType, []attr.Value{})),
+				MarkdownDescription: "The source IPv4 or IPv6 addresses, CIDR blocks or address ranges for the firewall rule. IPv4 and IPv6 cannot be mixed in one rule. Changing this forces a new resource.",
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.RequiresReplace(),
+				},
+			},
+			"sport": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString(""),
+				MarkdownDescription: "The source ports for the rule, as a comma separated list of ports and ranges. Not allowed with ICMP. Changing this forces a new resource.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"enable": schema.Int64Attribute{
+				Optional:            true,
+				Computed:            true,
+				Default:             int64default.StaticInt64(1),
+				MarkdownDescription: "Whether the rule is enabled (1 = enabled, 0 = disabled). Defaults to `1`. Changing this forces a new resource.",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"iface": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The network interface the rule applies to.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"log": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The logging level for the rule.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"macro": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString(""),
+				MarkdownDescription: "The macro name for predefined rule sets (e.g., SSH, HTTP, PostgreSQL). Changing this forces a new resource.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"created_on": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The timestamp when the rule was created.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"updated_on": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The timestamp when the rule was last updated.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"allow_orphan_on_delete": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+				MarkdownDescription: "Whether destroying or replacing the rule may leave it applied to the group, since the API cannot remove it. Otherwise destroying or replacing the rule fails. Set it in a separate apply before the change that removes the rule. Defaults to `false`.",
+			},
+		},
+	}
+}
+
+func (f *firewallRuleResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"group_identifier": identityschema.StringAttribute{
+				RequiredForImport: true,
+				Description:       "The identifier of the firewall group the rule belongs to.",
+			},
+			"identifier": identityschema.StringAttribute{
+				RequiredForImport: true,
+				Description:       "The unique identifier of the firewall rule.",
+			},
+		},
+	}
+}
+
+func (f *firewallRuleResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*providerdata.Data)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configuration Type",
+			fmt.Sprintf("Expected *providerdata.Data, got %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	f.client = data.Client.FirewallGroup
+}
+
+func (f *firewallRuleResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config firewallRuleResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(validateFirewallRule(InBoundFirewallRules{
+		Action: config.Action,
+		Type:   config.Type,
+		Dest:   config.Dest,
+		Dport:  config.Dport,
+		Proto:  config.Proto,
+		Source: config.Source,
+		Sport:  config.Sport,
+		Macro:  config.Macro,
+	}, "", path.Empty())...)
+}
+
+func (f *firewallRuleResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan firewallRuleResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	groupIdentifier := plan.GroupIdentifier.ValueString()
+
+	before, err := f.client.Get(ctx, groupIdentifier)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error reading firewall",
+			"couldn't read firewall "+groupIdentifier+", unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	rule := InBoundFirewallRules{
+		Action:  plan.Action,
+		Type:    plan.Type,
+		Comment: plan.Comment,
+		Dest:    plan.Dest,
+		Dport:   plan.Dport,
+		Proto:   plan.Proto,
+		Source:  plan.Source,
+		Sport:   plan.Sport,
+		Enable:  plan.Enable,
+		Macro:   plan.Macro,
+	}
+
+	ruleReq, diags := expandFirewallRule(ctx, rule, plan.Type.ValueString())
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err = f.client.Update(ctx, &ruleReq, groupIdentifier)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating firewall rule",
+			"couldn't add rule to firewall "+groupIdentifier+", unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	after, err := f.client.Get(ctx, groupIdentifier)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error reading firewall",
+			"couldn't read firewall "+groupIdentifier+", unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	created, err := findCreatedFirewallRule(before.Rules, after.Rules, ruleReq)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating firewall rule",
+			"couldn't find the rule added to firewall "+groupIdentifier+": "+err.Error(),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(plan.refresh(ctx, created)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, firewallRuleIdentityModel{GroupIdentifier: plan.GroupIdentifier, Identifier: plan.Identifier})...)
+}
+
+func (f *firewallRuleResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state firewallRuleResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	fwGroup, err := f.client.Get(ctx, state.GroupIdentifier.ValueString())
+	if err != nil {
+		if apierror.IsNotFound(err) {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError(
+			"Error reading firewall",
+			"couldn't read firewall "+state.GroupIdentifier.ValueString()+", unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	rule, found := findFirewallRule(fwGroup.Rules, state.Identifier.ValueString())
+	if !found {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	resp.Diagnostics.Append(state.refresh(ctx, rule)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, firewallRuleIdentityModel{GroupIdentifier: state.GroupIdentifier, Identifier: state.Identifier})...)
+}
+
+// ModifyPlan rejects a plan that replaces the rule unless orphaning it was
+// allowed beforehand, since the replaced rule would stay applied.
+func (f *firewallRuleResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var state, plan firewallRuleResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if firewallRuleReplaced(state, plan) {
+		resp.Diagnostics.Append(checkFirewallRuleOrphan(state, "replaced")...)
+	}
+}
+
+// firewallRuleReplaced reports whether plan changes an attribute that forces
+// a new rule.
+func firewallRuleReplaced(state, plan firewallRuleResourceModel) bool {
+	return !state.GroupIdentifier.Equal(plan.GroupIdentifier) ||
+		!state.Action.Equal(plan.Action) ||
+		!state.Type.Equal(plan.Type) ||
+		!state.Comment.Equal(plan.Comment) ||
+		!state.Dest.Equal(plan.Dest) ||
+		!state.Dport.Equal(plan.Dport) ||
+		!state.Proto.Equal(plan.Proto) ||
+		!state.Source.Equal(plan.Source) ||
+		!state.Sport.Equal(plan.Sport) ||
+		!state.Enable.Equal(plan.Enable) ||
+		!state.Macro.Equal(plan.Macro)
+}
+
+// Update only records allow_orphan_on_delete, since every other configurable
+// attribute forces a new rule.
+func (f *firewallRuleResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan firewallRuleResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Delete removes the rule from state when allow_orphan_on_delete is set. The
+// firewall group API has no call for removing a single rule, so the rule
+// stays in the group until it is removed from the panel or the group is
+// replaced. Otherwise Delete fails rather than leave the rule open silently.
+// A rule that is already gone, with or without its group, is deleted.
+func (f *firewallRuleResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state firewallRuleResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	fwGroup, err := f.client.Get(ctx, state.GroupIdentifier.ValueString())
+	if err != nil {
+		if apierror.IsNotFound(err) {
+			return
+		}
+		resp.Diagnostics.AddError(
+			"Error reading firewall",
+			"couldn't read firewall "+state.GroupIdentifier.ValueString()+", unexpected error: "+err.Error(),
+		)
+		return
+	}
+	if _, found := findFirewallRule(fwGroup.Rules, state.Identifier.ValueString()); !found {
+		return
+	}
+
+	resp.Diagnostics.Append(checkFirewallRuleOrphan(state, "destroyed")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.AddWarning(
+		"Firewall rule left in group",
+		fmt.Sprintf("The VPSie API does not support deleting individual firewall rules. Rule %s was removed from the Terraform state "+
+			"but is still applied to firewall group %s.", state.Identifier.ValueString(), state.GroupIdentifier.ValueString()),
+	)
+}
+
+// checkFirewallRuleOrphan returns an error unless the rule in state may be
+// left applied to its group when it is destroyed or replaced.
+func checkFirewallRuleOrphan(state firewallRuleResourceModel, action string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if !state.AllowOrphanOnDelete.ValueBool() {
+		diags.AddError(
+			"Firewall rule cannot be removed",
+			fmt.Sprintf("Rule %s cannot be %s because the VPSie API cannot remove a rule from firewall group %s, so it would stay applied. "+
+				"Replace the group to remove the rule, or set allow_orphan_on_delete = true and apply that change first to leave it in the group.",
+				state.Identifier.ValueString(), action, state.GroupIdentifier.ValueString()),
+		)
+	}
+
+	return diags
+}
+
+func (f *firewallRuleResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	var identity firewallRuleIdentityModel
+	if req.ID == "" {
+		resp.Diagnostics.Append(req.Identity.Get(ctx, &identity)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	} else {
+		parts := strings.Split(req.ID, "/")
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			resp.Diagnostics.AddError(
+				"Invalid Import ID",
+				fmt.Sprintf("Expected import identifier with format: <group_identifier>/<rule_identifier>. Got: %s", req.ID),
+			)
+			return
+		}
+		identity = firewallRuleIdentityModel{
+			GroupIdentifier: types.StringValue(parts[0]),
+			Identifier:      types.StringValue(parts[1]),
+		}
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("group_identifier"), identity.GroupIdentifier)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("identifier"), identity.Identifier)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), fmt.Sprintf("%s/%s", identity.GroupIdentifier.ValueString(), identity.Identifier.ValueString()))...)
+}
+
+// refresh overwrites the model with the rule returned by the API.
+func (m *firewallRuleResourceModel) refresh(ctx context.Context, rule *govpsie.InBoundFirewallRules) diag.Diagnostics {
+	flattened, diags := flattenFirewallRule(ctx, *rule)
+	if diags.HasError() {
+		return diags
+	}
+
+	m.ID = types.StringValue(fmt.Sprintf("%s/%s", m.GroupIdentifier.ValueString(), rule.Identifier))
+	m.Identifier = flattened.Identifier
+	m.Action = flattened.Action
+	m.Type = flattened.Type
+	m.Comment = flattened.Comment
+	m.Dest = flattened.Dest
+	m.Dport = flattened.Dport
+	m.Proto = flattened.Proto
+	m.Source = flattened.Source
+	m.Sport = flattened.Sport
+	m.Enable = flattened.Enable
+	m.Iface = flattened.Iface
+	m.Log = flattened.Log
+	m.Macro = flattened.Macro
+	m.CreatedOn = flattened.CreatedOn
+	m.UpdatedOn = flattened.UpdatedOn
+
+	return diags
+}
+
+// groupFirewallRules returns the inbound and outbound rules of a group as a
+// single slice.
+func groupFirewallRules(rules []govpsie.FirewallRules) []govpsie.InBoundFirewallRules {
+	var all []govpsie.InBoundFirewallRules
+	for _, rule := range rules {
+		all = append(all, rule.InBound...)
+		for _, out := range rule.OutBound {
+			all = append(all, govpsie.InBoundFirewallRules(out))
+		}
+	}
+
+	return all
+}
+
+func findFirewallRule(rules []govpsie.FirewallRules, identifier string) (*govpsie.InBoundFirewallRules, bool) {
+	for _, rule := range groupFirewallRules(rules) {
+		if rule.Identifier == identifier {
+			return &rule, true
+		}
+	}
+
+	return nil, false
+}
+
+// findCreatedFirewallRule returns the rule that appeared in the group after a
+// rule was pushed and that matches the pushed content.
+func findCreatedFirewallRule(before, after []govpsie.FirewallRules, req govpsie.FirewallUpdateReq) (*govpsie.InBoundFirewallRules, error) {
+	existing := make(map[string]bool)
+	for _, rule := range groupFirewallRules(before) {
+		existing[rule.Identifier] = true
+	}
+
+	key := firewallRuleKey(req)
+	for _, rule := range groupFirewallRules(after) {
+		if existing[rule.Identifier] {
+			continue
+		}
+		if firewallRuleKey(firewallRuleRequest(rule)) == key {
+			return &rule, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no new rule matching %s", describeFirewallRules([]govpsie.FirewallUpdateReq{req}))
+}
@@ -0,0 +1,111 @@
+package firewall_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/vpsie/terraform-provider-vpsie/internal/acctest"
+	"github.com/vpsie/terraform-provider-vpsie/internal/fakeapi"
+)
+
+func TestUnitFirewallRuleResource_RefusesToOrphanRule(t *testing.T) {
+	ctx := context.Background()
+	backend := fakeapi.New()
+	server, schemas := acctest.FakeProtoV6Server(t, backend, nil)
+
+	client := backend.Client()
+	if err := client.FirewallGroup.Create(ctx, "shared", nil); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	groups, _ := client.FirewallGroup.List(ctx, nil)
+	groupIdentifier := groups[0].Identifier
+
+	config := map[string]tftypes.Value{
+		"group_identifier": tftypes.NewValue(tftypes.String, groupIdentifier),
+		"action":           tftypes.NewValue(tftypes.String, "ACCEPT"),
+		"type":             tftypes.NewValue(tftypes.String, "in"),
+		"proto":            tftypes.NewValue(tftypes.String, "tcp"),
+		"dport":            tftypes.NewValue(tftypes.String, "22"),
+	}
+	rule, diags := acctest.ApplyResource(t, server, schemas, "vpsie_firewall_rule", nil, config)
+	if len(diags) > 0 {
+		t.Fatalf("create: %s: %s", diags[0].Summary, diags[0].Detail)
+	}
+
+	// Dropping dport from the configuration opens every port, so it has to
+	// show up as a change.
+	withoutPort := map[string]tftypes.Value{}
+	for name, v := range config {
+		if name != "dport" {
+			withoutPort[name] = v
+		}
+	}
+	plan := acctest.PlanResource(t, server, schemas, "vpsie_firewall_rule", rule, withoutPort)
+	dport := tftypes.NewAttributePath().WithAttributeName("dport")
+	replaced := false
+	for _, p := range plan.RequiresReplace {
+		replaced = replaced || p.Equal(dport)
+	}
+	if !replaced {
+		t.Errorf("expected removing dport to replace the rule, got %v", plan.RequiresReplace)
+	}
+	if len(plan.Diagnostics) == 0 || plan.Diagnostics[0].Summary != "Firewall rule cannot be removed" {
+		t.Errorf("expected the replacement to be refused at plan time, got %v", plan.Diagnostics)
+	}
+
+	diags = acctest.DestroyResource(t, server, schemas, "vpsie_firewall_rule", rule)
+	if len(diags) == 0 || diags[0].Summary != "Firewall rule cannot be removed" {
+		t.Fatalf("expected destroy to be refused, got %v", diags)
+	}
+
+	config["allow_orphan_on_delete"] = tftypes.NewValue(tftypes.Bool, true)
+	rule, diags = acctest.ApplyResource(t, server, schemas, "vpsie_firewall_rule", rule, config)
+	if len(diags) > 0 {
+		t.Fatalf("update: %s: %s", diags[0].Summary, diags[0].Detail)
+	}
+
+	diags = acctest.DestroyResource(t, server, schemas, "vpsie_firewall_rule", rule)
+	for _, d := range diags {
+		if d.Severity == tfprotov6.DiagnosticSeverityError {
+			t.Fatalf("destroy: %s: %s", d.Summary, d.Detail)
+		}
+	}
+	if len(diags) == 0 || diags[0].Summary != "Firewall rule left in group" {
+		t.Errorf("expected a warning that the rule stays applied, got %v", diags)
+	}
+}
+
+func TestUnitFirewallRuleResource_DeleteWithGroupGone(t *testing.T) {
+	ctx := context.Background()
+	backend := fakeapi.New()
+	server, schemas := acctest.FakeProtoV6Server(t, backend, nil)
+
+	client := backend.Client()
+	if err := client.FirewallGroup.Create(ctx, "shared", nil); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	groups, _ := client.FirewallGroup.List(ctx, nil)
+	groupIdentifier := groups[0].Identifier
+
+	rule, diags := acctest.ApplyResource(t, server, schemas, "vpsie_firewall_rule", nil, map[string]tftypes.Value{
+		"group_identifier": tftypes.NewValue(tftypes.String, groupIdentifier),
+		"action":           tftypes.NewValue(tftypes.String, "ACCEPT"),
+		"type":             tftypes.NewValue(tftypes.String, "in"),
+		"proto":            tftypes.NewValue(tftypes.String, "tcp"),
+		"dport":            tftypes.NewValue(tftypes.String, "22"),
+	})
+	if len(diags) > 0 {
+		t.Fatalf("create: %s: %s", diags[0].Summary, diags[0].Detail)
+	}
+
+	if err := client.FirewallGroup.Delete(ctx, groupIdentifier); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	diags = acctest.DestroyResource(t, server, schemas, "vpsie_firewall_rule", rule)
+	if len(diags) > 0 {
+		t.Errorf("expected a rule whose group is gone to be destroyed, got %s: %s", diags[0].Summary, diags[0].Detail)
+	}
+}
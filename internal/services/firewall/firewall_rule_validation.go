@@ -0,0 +1,283 @@
+package firewall
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var firewallRuleActions = []string{"ACCEPT", "DROP", "REJECT"}
+
+var firewallRuleProtocols = []string{"tcp", "udp", "icmp", "icmpv6", "ipv6-icmp", "gre", "esp", "ah", "sctp"}
+
+// firewallRuleMacros lists the predefined rule sets accepted by the VPSie
+// firewall.
+var firewallRuleMacros = []string{
+	"Amanda", "Auth", "BGP", "BitTorrent", "BitTorrent32", "CVS", "Ceph", "Citrix",
+	"DAAP", "DCC", "DHCPfwd", "DHCPv6", "DNS", "Distcc", "FTP", "Finger", "GNUnet",
+	"GRE", "Git", "HKP", "HTTP", "HTTPS", "ICPV2", "ICQ", "IMAP", "IMAPS", "IPIP",
+	"IPsec", "IPsecah", "IPsecnat", "IRC", "Jetdirect", "L2TP", "LDAP", "LDAPS",
+	"MDNS", "MSNP", "MSSQL", "Mail", "Munin", "MySQL", "NNTP", "NNTPS", "NTP",
+	"NeighborDiscovery", "OSPF", "OpenVPN", "PCA", "PMG", "POP3", "POP3S", "PPtP",
+	"Ping", "PostgreSQL", "Printer", "RDP", "RIP", "RNDC", "Razor", "Rdate", "Rsync",
+	"SANE", "SMB", "SMBswat", "SMTP", "SMTPS", "SNMP", "SPAMD", "SSH", "SVN", "SixXS",
+	"Squid", "Submission", "Syslog", "TFTP", "Telnet", "Tinc", "Traceroute", "VNC",
+	"VNCL", "Web", "Webcache", "Webmin", "Whois",
+}
+
+// firewallRulesConfig is the shape of one element of the rules attribute
+// when read from configuration, where either list may still be unknown.
+type firewallRulesConfig struct {
+	InBound  types.List `tfsdk:"in_bound"`
+	OutBound types.List `tfsdk:"out_bound"`
+}
+
+// validateFirewallRulesConfig checks every rule configured in a firewall
+// group's rules attribute.
+func validateFirewallRulesConfig(ctx context.Context, rules types.List) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if rules.IsNull() || rules.IsUnknown() {
+		return diags
+	}
+
+	var groups []firewallRulesConfig
+	diags.Append(rules.ElementsAs(ctx, &groups, false)...)
+	if diags.HasError() {
+		return diags
+	}
+
+	for i, group := range groups {
+		directions := []struct {
+			name  string
+			attr  string
+			rules types.List
+		}{
+			{name: "in", attr: "in_bound", rules: group.InBound},
+			{name: "out", attr: "out_bound", rules: group.OutBound},
+		}
+
+		for _, direction := range directions {
+			if direction.rules.IsNull() || direction.rules.IsUnknown() {
+				continue
+			}
+
+			var list []InBoundFirewallRules
+			diags.Append(direction.rules.ElementsAs(ctx, &list, false)...)
+			if diags.HasError() {
+				return diags
+			}
+
+			for j, rule := range list {
+				rulePath := path.Root("rules").AtListIndex(i).AtName(direction.attr).AtListIndex(j)
+				diags.Append(validateFirewallRule(rule, direction.name, rulePath)...)
+			}
+		}
+	}
+
+	return diags
+}
+
+// validateFirewallRule checks the configured values of a single rule. When
+// direction is set, the rule type must match it. Errors are reported against
+// the attributes below rulePath.
+func validateFirewallRule(rule InBoundFirewallRules, direction string, rulePath path.Path) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	addError := func(attr, format string, args ...any) {
+		detail := fmt.Sprintf(format, args...)
+		if prefix := rulePath.String(); prefix != "" {
+			detail = prefix + ": " + detail
+		}
+		diags.AddAttributeError(rulePath.AtName(attr), "Invalid Firewall Rule", detail)
+	}
+
+	if isKnown(rule.Action) && !containsFold(firewallRuleActions, rule.Action.ValueString()) {
+		addError("action", "action %q is not supported, expected one of %s.",
+			rule.Action.ValueString(), strings.Join(firewallRuleActions, ", "))
+	}
+
+	if direction != "" && isKnown(rule.Type) && rule.Type.ValueString() != "" && rule.Type.ValueString() != direction {
+		addError("type", "type %q does not match the %s_bound list the rule is declared in, expected %q.",
+			rule.Type.ValueString(), direction, direction)
+	}
+
+	proto := ""
+	if isKnown(rule.Proto) {
+		proto = strings.ToLower(rule.Proto.ValueString())
+		if proto != "" && !containsFold(firewallRuleProtocols, proto) {
+			addError("proto", "proto %q is not supported, expected one of %s.",
+				rule.Proto.ValueString(), strings.Join(firewallRuleProtocols, ", "))
+		}
+	}
+	icmp := proto == "icmp" || proto == "icmpv6" || proto == "ipv6-icmp"
+
+	for _, port := range []struct {
+		attr  string
+		value types.String
+	}{
+		{attr: "dport", value: rule.Dport},
+		{attr: "sport", value: rule.Sport},
+	} {
+		if !isKnown(port.value) || port.value.ValueString() == "" {
+			continue
+		}
+		if icmp {
+			addError(port.attr, "%s cannot be set when proto is %q.", port.attr, proto)
+			continue
+		}
+		if err := validateFirewallPorts(port.value.ValueString()); err != nil {
+			addError(port.attr, "%s %q is invalid: %s.", port.attr, port.value.ValueString(), err)
+		}
+	}
+
+	if isKnown(rule.Macro) && rule.Macro.ValueString() != "" && !containsFold(firewallRuleMacros, rule.Macro.ValueString()) {
+		addError("macro", "macro %q is not a known firewall macro.", rule.Macro.ValueString())
+	}
+
+	// Addresses of both families cannot be mixed in a single rule, and ICMP
+	// variants only apply to their own family.
+	families := map[int]string{}
+	for _, addrs := range []struct {
+		attr  string
+		value types.List
+	}{
+		{attr: "source", value: rule.Source},
+		{attr: "dest", value: rule.Dest},
+	} {
+		if addrs.value.IsNull() || addrs.value.IsUnknown() {
+			continue
+		}
+
+		for k, elem := range addrs.value.Elements() {
+			value, ok := elem.(types.String)
+			if !ok || !isKnown(value) {
+				continue
+			}
+
+			family, err := firewallAddressFamily(value.ValueString())
+			if err != nil {
+				addError(addrs.attr, "%s[%d] %q is invalid: %s.", addrs.attr, k, value.ValueString(), err)
+				continue
+			}
+
+			if _, seen := families[family]; !seen {
+				families[family] = fmt.Sprintf("%s[%d] %q", addrs.attr, k, value.ValueString())
+			}
+			if other, mixed := families[otherFamily(family)]; mixed {
+				addError(addrs.attr, "%s[%d] %q is an IPv%d address but %s is IPv%d; a rule cannot mix IPv4 and IPv6 addresses.",
+					addrs.attr, k, value.ValueString(), family, other, otherFamily(family))
+			}
+		}
+	}
+
+	if _, ok := families[6]; ok && proto == "icmp" {
+		addError("proto", "proto \"icmp\" only applies to IPv4, use \"icmpv6\" for IPv6 addresses.")
+	}
+	if _, ok := families[4]; ok && (proto == "icmpv6" || proto == "ipv6-icmp") {
+		addError("proto", "proto %q only applies to IPv6, use \"icmp\" for IPv4 addresses.", proto)
+	}
+
+	return diags
+}
+
+// validateFirewallPorts accepts a comma separated list of ports and port
+// ranges, with ranges written as either 1000:2000 or 1000-2000.
+func validateFirewallPorts(value string) error {
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			return fmt.Errorf("empty port in list")
+		}
+
+		bounds := strings.FieldsFunc(part, func(r rune) bool { return r == ':' || r == '-' })
+		if len(bounds) == 0 || len(bounds) > 2 || strings.Count(part, ":")+strings.Count(part, "-") != len(bounds)-1 {
+			return fmt.Errorf("%q is not a port or port range", part)
+		}
+
+		ports := make([]int, len(bounds))
+		for i, bound := range bounds {
+			port, err := strconv.Atoi(bound)
+			if err != nil || port < 1 || port > 65535 {
+				return fmt.Errorf("%q is not a port between 1 and 65535", bound)
+			}
+			ports[i] = port
+		}
+
+		if len(ports) == 2 && ports[0] > ports[1] {
+			return fmt.Errorf("range %q starts after it ends", part)
+		}
+	}
+
+	return nil
+}
+
+// firewallAddressFamily returns 4 or 6 for an address, CIDR block or
+// address range.
+func firewallAddressFamily(value string) (int, error) {
+	value = strings.TrimSpace(value)
+
+	if strings.Contains(value, "/") {
+		prefix, err := netip.ParsePrefix(value)
+		if err != nil {
+			return 0, fmt.Errorf("not a valid CIDR block")
+		}
+		return addrFamily(prefix.Addr()), nil
+	}
+
+	if first, last, ok := strings.Cut(value, "-"); ok {
+		from, err := netip.ParseAddr(first)
+		if err != nil {
+			return 0, fmt.Errorf("not a valid address range")
+		}
+		to, err := netip.ParseAddr(last)
+		if err != nil {
+			return 0, fmt.Errorf("not a valid address range")
+		}
+		if addrFamily(from) != addrFamily(to) {
+			return 0, fmt.Errorf("address range mixes IPv4 and IPv6")
+		}
+		if to.Less(from) {
+			return 0, fmt.Errorf("address range starts after it ends")
+		}
+		return addrFamily(from), nil
+	}
+
+	addr, err := netip.ParseAddr(value)
+	if err != nil {
+		return 0, fmt.Errorf("not a valid IP address or CIDR block")
+	}
+	return addrFamily(addr), nil
+}
+
+func addrFamily(addr netip.Addr) int {
+	if addr.Unmap().Is4() {
+		return 4
+	}
+	return 6
+}
+
+func otherFamily(family int) int {
+	if family == 4 {
+		return 6
+	}
+	return 4
+}
+
+func isKnown(value types.String) bool {
+	return !value.IsNull() && !value.IsUnknown()
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
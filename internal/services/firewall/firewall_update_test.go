@@ -0,0 +1,155 @@
+package firewall_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/vpsie/govpsie"
+	"github.com/vpsie/terraform-provider-vpsie/internal/acctest"
+	"github.com/vpsie/terraform-provider-vpsie/internal/fakeapi"
+)
+
+// testFirewallConfig returns a vpsie_firewall configuration with one
+// inbound TCP rule accepting each of ports.
+func testFirewallConfig(schemas *tfprotov6.GetProviderSchemaResponse, ports ...string) map[string]tftypes.Value {
+	firewallType := schemas.ResourceSchemas["vpsie_firewall"].ValueType().(tftypes.Object)
+	rulesType := firewallType.AttributeTypes["rules"].(tftypes.List)
+	directionsType := rulesType.ElementType.(tftypes.Object)
+	ruleListType := directionsType.AttributeTypes["in_bound"].(tftypes.List)
+
+	var inBound []tftypes.Value
+	for _, port := range ports {
+		inBound = append(inBound, acctest.ObjectValue(ruleListType.ElementType, map[string]tftypes.Value{
+			"action": tftypes.NewValue(tftypes.String, "ACCEPT"),
+			"proto":  tftypes.NewValue(tftypes.String, "tcp"),
+			"dport":  tftypes.NewValue(tftypes.String, port),
+		}))
+	}
+
+	return map[string]tftypes.Value{
+		"group_name": tftypes.NewValue(tftypes.String, "web"),
+		"rules": tftypes.NewValue(rulesType, []tftypes.Value{
+			acctest.ObjectValue(directionsType, map[string]tftypes.Value{
+				"in_bound": tftypes.NewValue(ruleListType, inBound),
+			}),
+		}),
+	}
+}
+
+func testFirewallCreate(t *testing.T, server tfprotov6.ProviderServer, schemas *tfprotov6.GetProviderSchemaResponse, ports ...string) *acctest.ResourceState {
+	t.Helper()
+
+	created, diags := acctest.ApplyResource(t, server, schemas, "vpsie_firewall", nil, testFirewallConfig(schemas, ports...))
+	if len(diags) > 0 {
+		t.Fatalf("create: %s: %s", diags[0].Summary, diags[0].Detail)
+	}
+	return created
+}
+
+func TestUnitFirewallResource_UpdateAddsRule(t *testing.T) {
+	backend := fakeapi.New()
+	server, schemas := acctest.FakeProtoV6Server(t, backend, nil)
+	created := testFirewallCreate(t, server, schemas, "22")
+
+	updated, diags := acctest.ApplyResource(t, server, schemas, "vpsie_firewall", created, testFirewallConfig(schemas, "22", "443"))
+	if len(diags) > 0 {
+		t.Fatalf("update: %s: %s", diags[0].Summary, diags[0].Detail)
+	}
+
+	if n := backend.Calls("FirewallGroup.Update"); n != 1 {
+		t.Errorf("expected one rule to be pushed, got %d", n)
+	}
+	if !updated.State["identifier"].Equal(created.State["identifier"]) {
+		t.Errorf("expected the group to keep identifier %s, got %s", created.State["identifier"], updated.State["identifier"])
+	}
+	if !updated.State["inbound_count"].Equal(tftypes.NewValue(tftypes.Number, 2)) {
+		t.Errorf("expected 2 inbound rules, got %s", updated.State["inbound_count"])
+	}
+}
+
+func TestUnitFirewallResource_ChangedRuleFailsPlan(t *testing.T) {
+	backend := fakeapi.New()
+	server, schemas := acctest.FakeProtoV6Server(t, backend, nil)
+	created := testFirewallCreate(t, server, schemas, "22")
+
+	plan := acctest.PlanResource(t, server, schemas, "vpsie_firewall", created, testFirewallConfig(schemas, "2222"))
+	if len(plan.Diagnostics) == 0 || plan.Diagnostics[0].Summary != "Firewall rules cannot be removed in place" {
+		t.Fatalf("expected changing a rule's port to fail the plan, got %v", plan.Diagnostics)
+	}
+	if len(plan.RequiresReplace) > 0 {
+		t.Errorf("expected the group not to be replaced, got %v", plan.RequiresReplace)
+	}
+}
+
+func TestUnitFirewallResource_UpdateRefusesUnexpectedRule(t *testing.T) {
+	ctx := context.Background()
+	backend := fakeapi.New()
+	server, schemas := acctest.FakeProtoV6Server(t, backend, nil)
+	created := testFirewallCreate(t, server, schemas, "22")
+
+	var identifier string
+	_ = created.State["identifier"].As(&identifier)
+
+	// A rule added outside Terraform after the plan cannot be removed.
+	plan := acctest.PlanResource(t, server, schemas, "vpsie_firewall", created, testFirewallConfig(schemas, "22", "443"))
+	if len(plan.Diagnostics) > 0 || len(plan.RequiresReplace) > 0 {
+		t.Fatalf("expected an in-place plan, got %v and replacement for %v", plan.Diagnostics, plan.RequiresReplace)
+	}
+	err := backend.Client().FirewallGroup.Update(ctx, &govpsie.FirewallUpdateReq{
+		Action: "ACCEPT",
+		Type:   "in",
+		Proto:  "tcp",
+		Dport:  "3306",
+		Enable: 1,
+	}, identifier)
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	firewallType := schemas.ResourceSchemas["vpsie_firewall"].ValueType()
+	resp, err := server.ApplyResourceChange(ctx, &tfprotov6.ApplyResourceChangeRequest{
+		TypeName:        "vpsie_firewall",
+		PriorState:      acctest.DynamicValue(t, firewallType, tftypes.NewValue(firewallType, created.State)),
+		PlannedState:    plan.PlannedState,
+		Config:          acctest.DynamicValue(t, firewallType, acctest.ObjectValue(firewallType, testFirewallConfig(schemas, "22", "443"))),
+		PlannedIdentity: plan.PlannedIdentity,
+	})
+	if err != nil {
+		t.Fatalf("ApplyResourceChange: %v", err)
+	}
+	if len(resp.Diagnostics) == 0 || resp.Diagnostics[0].Summary != "Firewall rules cannot be removed in place" {
+		t.Fatalf("expected the update to be refused, got %v", resp.Diagnostics)
+	}
+
+	group, err := backend.Client().FirewallGroup.Get(ctx, identifier)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	for _, rule := range group.Rules[0].InBound {
+		if rule.Dport == "443" {
+			t.Error("expected no rule to be opened when the update is refused")
+		}
+	}
+}
+
+func TestUnitFirewallResource_ReadRemovesDeletedGroup(t *testing.T) {
+	backend := fakeapi.New()
+	server, schemas := acctest.FakeProtoV6Server(t, backend, nil)
+	created := testFirewallCreate(t, server, schemas, "22")
+
+	var identifier string
+	_ = created.State["identifier"].As(&identifier)
+	if err := backend.Client().FirewallGroup.Delete(context.Background(), identifier); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	read, diags := acctest.ReadResource(t, server, schemas, "vpsie_firewall", created)
+	if len(diags) > 0 {
+		t.Fatalf("read: %s: %s", diags[0].Summary, diags[0].Detail)
+	}
+	if read != nil {
+		t.Errorf("expected a group deleted outside Terraform to be removed from state, got %v", read.State)
+	}
+}
@@ -3,11 +3,12 @@ package gateway
 import (
 	"context"
 	"fmt"
-	"strings"
+	"strconv"
 
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
@@ -15,12 +16,15 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/vpsie/govpsie"
+	"github.com/vpsie/terraform-provider-vpsie/internal/apierror"
+	"github.com/vpsie/terraform-provider-vpsie/internal/providerdata"
 )
 
 var (
 	_ resource.Resource                = &gatewayResource{}
 	_ resource.ResourceWithConfigure   = &gatewayResource{}
 	_ resource.ResourceWithImportState = &gatewayResource{}
+	_ resource.ResourceWithIdentity    = &gatewayResource{}
 )
 
 type gatewayResource struct {
@@ -48,6 +52,10 @@ type gatewayResourceModel struct {
 	AttachedVms          []AttachedVM `tfsdk:"attached_vms"`
 }
 
+type gatewayIdentityModel struct {
+	ID types.Int64 `tfsdk:"id"`
+}
+
 func NewGatewayResource() resource.Resource {
 	return &gatewayResource{}
 }
@@ -208,22 +216,33 @@ func (g *gatewayResource) Schema(_ context.Context, _ resource.SchemaRequest, re
 	}
 }
 
+func (g *gatewayResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"id": identityschema.Int64Attribute{
+				RequiredForImport: true,
+				Description:       "The unique numeric identifier of the gateway.",
+			},
+		},
+	}
+}
+
 func (g *gatewayResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
 		return
 	}
 
-	client, ok := req.ProviderData.(*govpsie.Client)
+	data, ok := req.ProviderData.(*providerdata.Data)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configuration Type",
-			fmt.Sprintf("Expected *govpsie.Client, got %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *providerdata.Data, got %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	g.client = client.Gateway
+	g.client = data.Client.Gateway
 }
 
 // Create creates the resource and sets the initial Terraform state.
@@ -266,6 +285,8 @@ func (g *gatewayResource) Create(ctx context.Context, req resource.CreateRequest
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, gatewayIdentityModel{ID: plan.ID})...)
 }
 
 // Read refreshes the Terraform state with the latest data.
@@ -279,7 +300,7 @@ func (g *gatewayResource) Read(ctx context.Context, req resource.ReadRequest, re
 
 	gateway, err := g.client.Get(ctx, state.ID.ValueInt64())
 	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
+		if apierror.IsNotFound(err) {
 			resp.State.RemoveResource(ctx)
 			return
 		}
@@ -325,6 +346,8 @@ func (g *gatewayResource) Read(ctx context.Context, req resource.ReadRequest, re
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, gatewayIdentityModel{ID: state.ID})...)
 }
 
 // Update updates the resource and sets the updated Terraform state on success.
@@ -438,7 +461,25 @@ func (g *gatewayResource) Delete(ctx context.Context, req resource.DeleteRequest
 }
 
 func (g *gatewayResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("identifier"), req, resp)
+	if req.ID == "" {
+		var identity gatewayIdentityModel
+		resp.Diagnostics.Append(req.Identity.Get(ctx, &identity)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), identity.ID)...)
+		return
+	}
+
+	id, err := strconv.ParseInt(req.ID, 10, 64)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Invalid import ID",
+			fmt.Sprintf("expected the numeric ID of a gateway, got: %s", req.ID),
+		)
+		return
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
 }
 
 func (g *gatewayResource) CreateAndReturnGateway(ctx context.Context, ipType, dcIdentifier string) (*govpsie.Gateway, error) {
@@ -474,5 +515,5 @@ func (g *gatewayResource) CreateAndReturnGateway(ctx context.Context, ipType, dc
 		}
 	}
 
-	return nil, fmt.Errorf("gateway not found")
+	return nil, apierror.NotFoundf("gateway not found")
 }
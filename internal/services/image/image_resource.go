@@ -3,13 +3,13 @@ package image
 import (
 	"context"
 	"fmt"
-	"strings"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
@@ -17,12 +17,16 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/vpsie/govpsie"
+	"github.com/vpsie/terraform-provider-vpsie/internal/apierror"
+	"github.com/vpsie/terraform-provider-vpsie/internal/providerdata"
+	"github.com/vpsie/terraform-provider-vpsie/internal/waiter"
 )
 
 var (
 	_ resource.Resource                = &imageResource{}
 	_ resource.ResourceWithConfigure   = &imageResource{}
 	_ resource.ResourceWithImportState = &imageResource{}
+	_ resource.ResourceWithIdentity    = &imageResource{}
 )
 
 type imageResource struct {
@@ -47,6 +51,10 @@ type imageResourceModel struct {
 	Timeouts       timeouts.Value `tfsdk:"timeouts"`
 }
 
+type imageIdentityModel struct {
+	Identifier types.String `tfsdk:"identifier"`
+}
+
 func NewImageResource() resource.Resource {
 	return &imageResource{}
 }
@@ -172,22 +180,33 @@ func (i *imageResource) Schema(ctx context.Context, _ resource.SchemaRequest, re
 	}
 }
 
+func (i *imageResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"identifier": identityschema.StringAttribute{
+				RequiredForImport: true,
+				Description:       "The unique identifier of the image.",
+			},
+		},
+	}
+}
+
 func (i *imageResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
 		return
 	}
 
-	client, ok := req.ProviderData.(*govpsie.Client)
+	data, ok := req.ProviderData.(*providerdata.Data)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configuration Type",
-			fmt.Sprintf("Expected *govpsie.Client, got %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *providerdata.Data, got %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	i.client = client.Image
+	i.client = data.Client.Image
 }
 
 // Create creates the resource and sets the initial Terraform state.
@@ -213,53 +232,41 @@ func (i *imageResource) Create(ctx context.Context, req resource.CreateRequest,
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, createTimeout)
-	defer cancel()
-
-	for {
-		// Check if the context has expired
-		if ctx.Err() != nil {
-			resp.Diagnostics.AddError("error waiting for resource to become ready", ctx.Err().Error())
-			return
-		}
-
-		// Check resource status here using provider's API or SDK
-		image, ready, err := i.checkResourceStatus(ctx, plan.ImageLabel.ValueString())
-		if err != nil {
-			//  return fmt.Errorf("Timeout waiting for resource to become ready")
-			resp.Diagnostics.AddError("Error checking status of resource", err.Error())
-			return
-		}
-
-		if ready {
-			plan.ID = types.Int64Value(int64(image.ID))
-			plan.Identifier = types.StringValue(image.Identifier)
-			plan.UserID = types.Int64Value(int64(image.UserID))
-			plan.DataCenterID = types.Int64Value(int64(image.DatacenterID))
-			plan.ImageSize = types.Int64Value(image.ImageSize)
-			plan.OriginalName = types.StringValue(image.OriginalName)
-			plan.FetchedFromUrl = types.StringValue(image.FetchedFromURL)
-			plan.ImageHash = types.StringValue(image.ImageHash)
-			plan.ImageLabel = types.StringValue(image.ImageLabel)
-			plan.CreatedOn = types.StringValue(image.CreatedOn.String())
-			plan.Deleted = types.Int64Value(int64(image.Deleted))
-			plan.DcName = types.StringValue(image.DcName)
-			plan.DcIdentifier = types.StringValue(image.DcIdentifier)
-			plan.CreatedBy = types.StringValue(image.CreatedBy)
-
-			diags = resp.State.Set(ctx, plan)
-			resp.Diagnostics.Append(diags...)
-			if resp.Diagnostics.HasError() {
-				return
-			}
-
-			return
-		}
+	image, err := (&waiter.Conf[govpsie.CustomImage]{
+		Pending: []string{waiter.StateCreating},
+		Target:  []string{waiter.StateCreated},
+		Refresh: waiter.Appeared(func(ctx context.Context) (*govpsie.CustomImage, bool, error) {
+			return i.checkResourceStatus(ctx, plan.ImageLabel.ValueString())
+		}),
+		Timeout: createTimeout,
+	}).Wait(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Error waiting for image to become ready", err.Error())
+		return
+	}
 
-		// Wait for a delay before retrying
-		time.Sleep(5 * time.Second)
+	plan.ID = types.Int64Value(int64(image.ID))
+	plan.Identifier = types.StringValue(image.Identifier)
+	plan.UserID = types.Int64Value(int64(image.UserID))
+	plan.DataCenterID = types.Int64Value(int64(image.DatacenterID))
+	plan.ImageSize = types.Int64Value(image.ImageSize)
+	plan.OriginalName = types.StringValue(image.OriginalName)
+	plan.FetchedFromUrl = types.StringValue(image.FetchedFromURL)
+	plan.ImageHash = types.StringValue(image.ImageHash)
+	plan.ImageLabel = types.StringValue(image.ImageLabel)
+	plan.CreatedOn = types.StringValue(image.CreatedOn.String())
+	plan.Deleted = types.Int64Value(int64(image.Deleted))
+	plan.DcName = types.StringValue(image.DcName)
+	plan.DcIdentifier = types.StringValue(image.DcIdentifier)
+	plan.CreatedBy = types.StringValue(image.CreatedBy)
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, imageIdentityModel{Identifier: plan.Identifier})...)
 }
 
 // Read refreshes the Terraform state with the latest data.
@@ -273,7 +280,7 @@ func (i *imageResource) Read(ctx context.Context, req resource.ReadRequest, resp
 
 	image, err := i.client.GetImage(ctx, state.Identifier.ValueString())
 	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
+		if apierror.IsNotFound(err) {
 			resp.State.RemoveResource(ctx)
 			return
 		}
@@ -306,6 +313,8 @@ func (i *imageResource) Read(ctx context.Context, req resource.ReadRequest, resp
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, imageIdentityModel{Identifier: state.Identifier})...)
 }
 
 // Update updates the resource and sets the updated Terraform state on success.
@@ -339,7 +348,7 @@ func (i *imageResource) Delete(ctx context.Context, req resource.DeleteRequest,
 }
 
 func (i *imageResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("identifier"), req, resp)
+	resource.ImportStatePassthroughWithIdentity(ctx, path.Root("identifier"), path.Root("identifier"), req, resp)
 }
 
 func (i *imageResource) checkResourceStatus(ctx context.Context, imageLabel string) (*govpsie.CustomImage, bool, error) {
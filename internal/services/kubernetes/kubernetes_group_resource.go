@@ -3,11 +3,11 @@ package kubernetes
 import (
 	"context"
 	"fmt"
-	"strings"
 
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
@@ -15,12 +15,15 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/vpsie/govpsie"
+	"github.com/vpsie/terraform-provider-vpsie/internal/apierror"
+	"github.com/vpsie/terraform-provider-vpsie/internal/providerdata"
 )
 
 var (
 	_ resource.Resource                = &kubernetesGroupResource{}
 	_ resource.ResourceWithConfigure   = &kubernetesGroupResource{}
 	_ resource.ResourceWithImportState = &kubernetesGroupResource{}
+	_ resource.ResourceWithIdentity    = &kubernetesGroupResource{}
 )
 
 type kubernetesGroupResource struct {
@@ -52,6 +55,10 @@ type kubernetesGroupResourceModel struct {
 }
 
 // NewKubernetesGroupDataSource is a helper function to create the data source.
+type kubernetesGroupIdentityModel struct {
+	Identifier types.String `tfsdk:"identifier"`
+}
+
 func NewKubernetesGroupResource() resource.Resource {
 	return &kubernetesGroupResource{}
 }
@@ -217,22 +224,33 @@ func (k *kubernetesGroupResource) Schema(ctx context.Context, _ resource.SchemaR
 
 }
 
+func (k *kubernetesGroupResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"identifier": identityschema.StringAttribute{
+				RequiredForImport: true,
+				Description:       "The unique identifier of the Kubernetes node group.",
+			},
+		},
+	}
+}
+
 func (k *kubernetesGroupResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
 		return
 	}
 
-	client, ok := req.ProviderData.(*govpsie.Client)
+	data, ok := req.ProviderData.(*providerdata.Data)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configuration Type",
-			fmt.Sprintf("Expected *govpsie.Client, got %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *providerdata.Data, got %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	k.client = client.K8s
+	k.client = data.Client.K8s
 }
 
 // Create creates the resource and sets the initial Terraform state.
@@ -288,6 +306,8 @@ func (k *kubernetesGroupResource) Create(ctx context.Context, req resource.Creat
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, kubernetesGroupIdentityModel{Identifier: plan.Identifier})...)
 }
 
 // Read refreshes the Terraform state with the latest data.
@@ -301,7 +321,7 @@ func (k *kubernetesGroupResource) Read(ctx context.Context, req resource.ReadReq
 
 	k8sGroup, err := k.GetKubernetesGroupByIdentifier(ctx, state.Identifier.ValueString())
 	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
+		if apierror.IsNotFound(err) {
 			resp.State.RemoveResource(ctx)
 			return
 		}
@@ -340,6 +360,8 @@ func (k *kubernetesGroupResource) Read(ctx context.Context, req resource.ReadReq
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, kubernetesGroupIdentityModel{Identifier: state.Identifier})...)
 }
 
 // Update updates the resource and sets the updated Terraform state on success.
@@ -409,7 +431,7 @@ func (k *kubernetesGroupResource) Delete(ctx context.Context, req resource.Delet
 }
 
 func (k *kubernetesGroupResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("identifier"), req, resp)
+	resource.ImportStatePassthroughWithIdentity(ctx, path.Root("identifier"), path.Root("identifier"), req, resp)
 }
 
 func (k *kubernetesGroupResource) GetKubernetesGroupByName(ctx context.Context, name string) (*govpsie.K8sGroup, error) {
@@ -432,7 +454,7 @@ func (k *kubernetesGroupResource) GetKubernetesGroupByName(ctx context.Context,
 
 	}
 
-	return nil, fmt.Errorf("KUBERNETES GROUP NOT FOUND: %s", name)
+	return nil, apierror.NotFoundf("KUBERNETES GROUP NOT FOUND: %s", name)
 }
 
 func (k *kubernetesGroupResource) GetKubernetesGroupByIdentifier(ctx context.Context, identifier string) (*govpsie.K8sGroup, error) {
@@ -456,5 +478,5 @@ func (k *kubernetesGroupResource) GetKubernetesGroupByIdentifier(ctx context.Con
 
 	}
 
-	return nil, fmt.Errorf("KUBERNETES GROUP NOT FOUND: %s", identifier)
+	return nil, apierror.NotFoundf("KUBERNETES GROUP NOT FOUND: %s", identifier)
 }
@@ -0,0 +1,59 @@
+package kubernetes
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/list/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/vpsie/govpsie"
+	"github.com/vpsie/terraform-provider-vpsie/internal/listresult"
+)
+
+var (
+	_ list.ListResource              = &kubernetesListResource{}
+	_ list.ListResourceWithConfigure = &kubernetesListResource{}
+)
+
+type kubernetesListResource struct {
+	resource kubernetesResource
+}
+
+func NewKubernetesListResource() list.ListResource {
+	return &kubernetesListResource{}
+}
+
+func (l *kubernetesListResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	l.resource.Metadata(ctx, req, resp)
+}
+
+func (l *kubernetesListResource) ListResourceConfigSchema(_ context.Context, _ list.ListResourceSchemaRequest, resp *list.ListResourceSchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Lists the VPSie Kubernetes clusters in the account, for use with `terraform query`.",
+	}
+}
+
+func (l *kubernetesListResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	l.resource.Configure(ctx, req, resp)
+}
+
+func (l *kubernetesListResource) List(ctx context.Context, req list.ListRequest, stream *list.ListResultsStream) {
+	clusters, err := l.resource.client.List(ctx, nil)
+	if err != nil {
+		var diags diag.Diagnostics
+		diags.AddError(
+			"Error listing Kubernetes clusters",
+			"Could not list Kubernetes clusters: "+err.Error(),
+		)
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	stream.Results = listresult.Stream(req, clusters, func(cluster govpsie.ListK8s) list.ListResult {
+		return listresult.New(ctx, req, &l.resource, cluster.ClusterName, kubernetesIdentityModel{
+			Identifier: types.StringValue(cluster.Identifier),
+		})
+	})
+}
@@ -0,0 +1,19 @@
+package kubernetes_test
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/vpsie/terraform-provider-vpsie/internal/acctest"
+	"github.com/vpsie/terraform-provider-vpsie/internal/fakeapi"
+)
+
+func TestUnitKubernetesResource_DeletionProtection(t *testing.T) {
+	backend := fakeapi.New()
+	server, schemas := acctest.FakeProtoV6Server(t, backend, map[string]tftypes.Value{
+		"delete_reason": tftypes.NewValue(tftypes.String, "test"),
+	})
+	current := testKubernetesState(t, backend, server, schemas)
+
+	acctest.CheckDeletionProtection(t, server, schemas, "vpsie_kubernetes", current, map[string]tftypes.Value{})
+}
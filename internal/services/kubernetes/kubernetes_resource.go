@@ -3,24 +3,29 @@ package kubernetes
 import (
 	"context"
 	"fmt"
-	"strings"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/vpsie/govpsie"
+	"github.com/vpsie/terraform-provider-vpsie/internal/apierror"
+	"github.com/vpsie/terraform-provider-vpsie/internal/deletion"
+	"github.com/vpsie/terraform-provider-vpsie/internal/providerdata"
+	"github.com/vpsie/terraform-provider-vpsie/internal/waiter"
 )
 
 var (
 	_ resource.Resource                = &kubernetesResource{}
 	_ resource.ResourceWithConfigure   = &kubernetesResource{}
 	_ resource.ResourceWithImportState = &kubernetesResource{}
+	_ resource.ResourceWithIdentity    = &kubernetesResource{}
 )
 
 type kubernetesResource struct {
@@ -50,6 +55,7 @@ type kubernetesResourceModel struct {
 	VpcId              types.Int64    `tfsdk:"vpc_id"`
 	KuberVer           types.Int64    `tfsdk:"kuber_ver"`
 	ProjectIdentifier  types.String   `tfsdk:"project_identifier"`
+	DeletionProtection types.Bool     `tfsdk:"deletion_protection"`
 }
 
 type Node struct {
@@ -64,6 +70,42 @@ type Node struct {
 	CreatedOn    types.String `tfsdk:"created_on"`
 }
 
+type kubernetesIdentityModel struct {
+	Identifier types.String `tfsdk:"identifier"`
+}
+
+// refresh copies the attributes the API reports for a cluster into m.
+func (m *kubernetesResourceModel) refresh(k8s *govpsie.K8s) {
+	m.Identifier = types.StringValue(k8s.Identifier)
+	m.ClusterName = types.StringValue(k8s.ClusterName)
+	m.Color = types.StringValue(k8s.Color)
+	m.MasterCount = types.Int64Value(int64(k8s.Count))
+	m.CreatedOn = types.StringValue(k8s.CreatedOn)
+	m.UpdatedOn = types.StringValue(k8s.UpdatedOn)
+	m.CreatedBy = types.StringValue(k8s.CreatedBy)
+	m.NickName = types.StringValue(k8s.NickName)
+	m.Cpu = types.Int64Value(int64(k8s.Cpu))
+	m.Ram = types.Int64Value(int64(k8s.Ram))
+	m.Traffic = types.Int64Value(int64(k8s.Traffic))
+	m.Price = types.Float64Value(k8s.Price)
+
+	nodes := []Node{}
+	for _, node := range k8s.Nodes {
+		nodes = append(nodes, Node{
+			Id:           types.Int64Value(int64(node.Id)),
+			UserId:       types.Int64Value(int64(node.UserId)),
+			HostName:     types.StringValue(node.HostName),
+			DefaultIP:    types.StringValue(node.DefaultIP),
+			PrivateIP:    types.StringValue(node.PrivateIP),
+			NodeType:     types.Int64Value(int64(node.NodeType)),
+			NodeId:       types.Int64Value(int64(node.NodeId)),
+			DatacenterId: types.Int64Value(int64(node.DatacenterId)),
+			CreatedOn:    types.StringValue(node.CreatedOn),
+		})
+	}
+	m.Nodes = nodes
+}
+
 func NewKubernetesResource() resource.Resource {
 	return &kubernetesResource{}
 }
@@ -83,13 +125,6 @@ func (k *kubernetesResource) Schema(ctx context.Context, _ resource.SchemaReques
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
-			"id": schema.Int64Attribute{
-				Computed:            true,
-				MarkdownDescription: "The numeric ID of the Kubernetes cluster.",
-				PlanModifiers: []planmodifier.Int64{
-					int64planmodifier.UseStateForUnknown(),
-				},
-			},
 			"cluster_name": schema.StringAttribute{
 				Computed:            true,
 				MarkdownDescription: "The name of the Kubernetes cluster.",
@@ -114,9 +149,6 @@ func (k *kubernetesResource) Schema(ctx context.Context, _ resource.SchemaReques
 			"updated_on": schema.StringAttribute{
 				Computed:            true,
 				MarkdownDescription: "The timestamp when the cluster was last updated.",
-				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.UseStateForUnknown(),
-				},
 			},
 			"created_by": schema.StringAttribute{
 				Computed:            true,
@@ -153,6 +185,13 @@ func (k *kubernetesResource) Schema(ctx context.Context, _ resource.SchemaReques
 					int64planmodifier.UseStateForUnknown(),
 				},
 			},
+			"color": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The display color of the cluster.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
 			"price": schema.Float64Attribute{
 				Computed:            true,
 				MarkdownDescription: "The price of the Kubernetes cluster.",
@@ -228,6 +267,7 @@ func (k *kubernetesResource) Schema(ctx context.Context, _ resource.SchemaReques
 					},
 				},
 			},
+			"deletion_protection": deletion.ProtectionAttribute("kubernetes cluster"),
 			"timeouts": timeouts.Attributes(ctx, timeouts.Opts{
 				Create: true,
 			}),
@@ -271,6 +311,24 @@ func (k *kubernetesResource) Schema(ctx context.Context, _ resource.SchemaReques
 					int64planmodifier.UseStateForUnknown(),
 				},
 			},
+			"kuber_ver": schema.Int64Attribute{
+				Computed:            true,
+				MarkdownDescription: "The Kubernetes version the cluster was created with.",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (k *kubernetesResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"identifier": identityschema.StringAttribute{
+				RequiredForImport: true,
+				Description:       "The unique identifier of the Kubernetes cluster.",
+			},
 		},
 	}
 }
@@ -280,17 +338,17 @@ func (k *kubernetesResource) Configure(_ context.Context, req resource.Configure
 		return
 	}
 
-	client, ok := req.ProviderData.(*govpsie.Client)
+	data, ok := req.ProviderData.(*providerdata.Data)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configuration Type",
-			fmt.Sprintf("Expected *govpsie.Client, got %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *providerdata.Data, got %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	k.client = client.K8s
+	k.client = data.Client.K8s
 }
 
 // Create creates the resource and sets the initial Terraform state.
@@ -327,67 +385,28 @@ func (k *kubernetesResource) Create(ctx context.Context, req resource.CreateRequ
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, createTimeout)
-	defer cancel()
-
-	for {
-		// Check if the context has expired
-		if ctx.Err() != nil {
-			resp.Diagnostics.AddError("error waiting for resource to become ready", ctx.Err().Error())
-			return
-		}
-
-		// Check resource status here using provider's API or SDK
-		k8s, ready, err := k.checkResourceStatus(ctx, plan.ClusterName.ValueString())
-		if err != nil {
-			//  return fmt.Errorf("Timeout waiting for resource to become ready")
-			resp.Diagnostics.AddError("Error checking status of resource", err.Error())
-			return
-		}
-
-		if ready {
-			plan.Identifier = types.StringValue(k8s.Identifier)
-			plan.ClusterName = types.StringValue(k8s.ClusterName)
-			plan.Color = types.StringValue(k8s.Color)
-			plan.MasterCount = types.Int64Value(int64(k8s.Count))
-			plan.CreatedOn = types.StringValue(k8s.CreatedOn)
-			plan.UpdatedOn = types.StringValue(k8s.UpdatedOn)
-			plan.CreatedBy = types.StringValue(k8s.CreatedBy)
-			plan.NickName = types.StringValue(k8s.NickName)
-			plan.Cpu = types.Int64Value(int64(k8s.Cpu))
-			plan.Ram = types.Int64Value(int64(k8s.Ram))
-			plan.Traffic = types.Int64Value(int64(k8s.Traffic))
-			plan.Price = types.Float64Value(k8s.Price)
-
-			nodes := []Node{}
-			for _, node := range k8s.Nodes {
-				nodes = append(nodes, Node{
-					Id:           types.Int64Value(int64(node.Id)),
-					UserId:       types.Int64Value(int64(node.UserId)),
-					HostName:     types.StringValue(node.HostName),
-					DefaultIP:    types.StringValue(node.DefaultIP),
-					PrivateIP:    types.StringValue(node.PrivateIP),
-					NodeType:     types.Int64Value(int64(node.NodeType)),
-					NodeId:       types.Int64Value(int64(node.NodeId)),
-					DatacenterId: types.Int64Value(int64(node.DatacenterId)),
-					CreatedOn:    types.StringValue(node.CreatedOn),
-				})
-			}
-			plan.Nodes = nodes
-
-			diags = resp.State.Set(ctx, plan)
-			resp.Diagnostics.Append(diags...)
-			if resp.Diagnostics.HasError() {
-				return
-			}
+	k8s, err := (&waiter.Conf[govpsie.K8s]{
+		Pending: []string{waiter.StateCreating},
+		Target:  []string{waiter.StateCreated},
+		Refresh: waiter.Appeared(func(ctx context.Context) (*govpsie.K8s, bool, error) {
+			return k.checkResourceStatus(ctx, plan.ClusterName.ValueString())
+		}),
+		Timeout: createTimeout,
+	}).Wait(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Error waiting for kubernetes cluster to become ready", err.Error())
+		return
+	}
 
-			return
-		}
+	plan.refresh(k8s)
 
-		// Wait for a delay before retrying
-		time.Sleep(5 * time.Second)
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, kubernetesIdentityModel{Identifier: plan.Identifier})...)
 }
 
 // Read refreshes the Terraform state with the latest data.
@@ -401,7 +420,7 @@ func (k *kubernetesResource) Read(ctx context.Context, req resource.ReadRequest,
 
 	k8s, err := k.client.Get(ctx, state.Identifier.ValueString())
 	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
+		if apierror.IsNotFound(err) {
 			resp.State.RemoveResource(ctx)
 			return
 		}
@@ -414,43 +433,21 @@ func (k *kubernetesResource) Read(ctx context.Context, req resource.ReadRequest,
 	}
 
 	// Overwrite items with refreshed state
-	state.Identifier = types.StringValue(k8s.Identifier)
-	state.ClusterName = types.StringValue(k8s.ClusterName)
-	state.Color = types.StringValue(k8s.Color)
-	state.MasterCount = types.Int64Value(int64(k8s.Count))
-	state.CreatedOn = types.StringValue(k8s.CreatedOn)
-	state.UpdatedOn = types.StringValue(k8s.UpdatedOn)
-	state.CreatedBy = types.StringValue(k8s.CreatedBy)
-	state.NickName = types.StringValue(k8s.NickName)
-	state.Cpu = types.Int64Value(int64(k8s.Cpu))
-	state.Ram = types.Int64Value(int64(k8s.Ram))
-	state.Traffic = types.Int64Value(int64(k8s.Traffic))
-	state.Price = types.Float64Value(k8s.Price)
-
-	nodes := []Node{}
-	for _, node := range k8s.Nodes {
-		nodes = append(nodes, Node{
-			Id:           types.Int64Value(int64(node.Id)),
-			UserId:       types.Int64Value(int64(node.UserId)),
-			HostName:     types.StringValue(node.HostName),
-			DefaultIP:    types.StringValue(node.DefaultIP),
-			PrivateIP:    types.StringValue(node.PrivateIP),
-			NodeType:     types.Int64Value(int64(node.NodeType)),
-			NodeId:       types.Int64Value(int64(node.NodeId)),
-			DatacenterId: types.Int64Value(int64(node.DatacenterId)),
-			CreatedOn:    types.StringValue(node.CreatedOn),
-		})
-	}
-	state.Nodes = nodes
+	state.refresh(k8s)
 
 	diags = resp.State.Set(ctx, &state)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, kubernetesIdentityModel{Identifier: state.Identifier})...)
 }
 
 // Update updates the resource and sets the updated Terraform state on success.
+// Only slave_count, deletion_protection and timeouts change in place; nodes are
+// unknown in the plan, so the new state is built from the prior one and the
+// cluster as the API reports it afterwards.
 func (k *kubernetesResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
 	var state kubernetesResourceModel
 	diags := req.State.Get(ctx, &state)
@@ -459,15 +456,18 @@ func (k *kubernetesResource) Update(ctx context.Context, req resource.UpdateRequ
 		return
 	}
 
-	var plan kubernetesResourceModel
-	diags = req.Plan.Get(ctx, &plan)
-	resp.Diagnostics.Append(diags...)
+	var slaveCount types.Int64
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("slave_count"), &slaveCount)...)
+	var deletionProtection types.Bool
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("deletion_protection"), &deletionProtection)...)
+	var updateTimeouts timeouts.Value
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("timeouts"), &updateTimeouts)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	if plan.SlaveCount.ValueInt64() > state.SlaveCount.ValueInt64() {
-		for i := state.SlaveCount.ValueInt64(); i < plan.SlaveCount.ValueInt64(); i++ {
+	if slaveCount.ValueInt64() > state.SlaveCount.ValueInt64() {
+		for i := state.SlaveCount.ValueInt64(); i < slaveCount.ValueInt64(); i++ {
 			err := k.client.AddSlave(ctx, state.Identifier.ValueString())
 			if err != nil {
 				resp.Diagnostics.AddError(
@@ -479,8 +479,8 @@ func (k *kubernetesResource) Update(ctx context.Context, req resource.UpdateRequ
 			}
 		}
 
-	} else if plan.SlaveCount.ValueInt64() < state.SlaveCount.ValueInt64() {
-		for i := plan.SlaveCount.ValueInt64(); i < state.SlaveCount.ValueInt64(); i++ {
+	} else if slaveCount.ValueInt64() < state.SlaveCount.ValueInt64() {
+		for i := slaveCount.ValueInt64(); i < state.SlaveCount.ValueInt64(); i++ {
 			err := k.client.RemoveSlave(ctx, state.Identifier.ValueString())
 			if err != nil {
 				resp.Diagnostics.AddError(
@@ -494,6 +494,22 @@ func (k *kubernetesResource) Update(ctx context.Context, req resource.UpdateRequ
 
 	}
 
+	k8s, err := k.client.Get(ctx, state.Identifier.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error reading vpsie kubernetes",
+			"Couldn't read vpsie kubernetes identifier "+state.Identifier.ValueString()+": "+err.Error(),
+		)
+		return
+	}
+
+	state.refresh(k8s)
+	state.SlaveCount = slaveCount
+	state.DeletionProtection = deletionProtection
+	state.Timeouts = updateTimeouts
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
 }
 
 // Delete deletes the resource and removes the Terraform state on success.
@@ -505,6 +521,11 @@ func (k *kubernetesResource) Delete(ctx context.Context, req resource.DeleteRequ
 		return
 	}
 
+	resp.Diagnostics.Append(deletion.CheckProtection(state.DeletionProtection, "kubernetes cluster", state.Identifier.ValueString())...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	err := k.client.Delete(ctx, state.Identifier.ValueString(), "terraform", "terraform")
 	if err != nil {
 		resp.Diagnostics.AddError(
@@ -517,7 +538,7 @@ func (k *kubernetesResource) Delete(ctx context.Context, req resource.DeleteRequ
 }
 
 func (k *kubernetesResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("identifier"), req, resp)
+	resource.ImportStatePassthroughWithIdentity(ctx, path.Root("identifier"), path.Root("identifier"), req, resp)
 }
 
 func (k *kubernetesResource) checkResourceStatus(ctx context.Context, cluster_name string) (*govpsie.K8s, bool, error) {
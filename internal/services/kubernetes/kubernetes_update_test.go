@@ -0,0 +1,74 @@
+package kubernetes_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/vpsie/govpsie"
+	"github.com/vpsie/terraform-provider-vpsie/internal/acctest"
+	"github.com/vpsie/terraform-provider-vpsie/internal/fakeapi"
+)
+
+// testKubernetesState creates a cluster with one master and one slave
+// through the fake API and returns it as imported and read by the
+// provider. Clusters cannot be created from configuration.
+func testKubernetesState(t *testing.T, backend *fakeapi.Backend, server tfprotov6.ProviderServer, schemas *tfprotov6.GetProviderSchemaResponse) *acctest.ResourceState {
+	t.Helper()
+	ctx := context.Background()
+
+	client := backend.Client()
+	err := client.K8s.Create(ctx, &govpsie.CreateK8sReq{
+		ClusterName:      "cluster",
+		DcIdentifier:     "dc-1",
+		NodesCountMaster: 1,
+		NodesCountSlave:  1,
+	})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	clusters, _ := client.K8s.List(ctx, nil)
+
+	imported, diags := acctest.ImportResourceState(t, server, schemas, "vpsie_kubernetes", clusters[0].Identifier, nil)
+	if len(diags) > 0 {
+		t.Fatalf("import: %s", diags[0].Detail)
+	}
+	current, diags := acctest.ReadResource(t, server, schemas, "vpsie_kubernetes", &acctest.ResourceState{State: imported.State})
+	if len(diags) > 0 {
+		t.Fatalf("read: %s: %s", diags[0].Summary, diags[0].Detail)
+	}
+	return current
+}
+
+func TestUnitKubernetesResource_UpdateAddsSlave(t *testing.T) {
+	backend := fakeapi.New()
+	server, schemas := acctest.FakeProtoV6Server(t, backend, nil)
+	current := testKubernetesState(t, backend, server, schemas)
+
+	var nodes []tftypes.Value
+	_ = current.State["nodes"].As(&nodes)
+	if len(nodes) != 2 {
+		t.Fatalf("expected the imported cluster to have 2 nodes, got %d", len(nodes))
+	}
+
+	// Only slave_count changes.
+	current.State["deletion_protection"] = tftypes.NewValue(tftypes.Bool, false)
+	updated, diags := acctest.ApplyResource(t, server, schemas, "vpsie_kubernetes", current, map[string]tftypes.Value{
+		"slave_count": tftypes.NewValue(tftypes.Number, 1),
+	})
+	if len(diags) > 0 {
+		t.Fatalf("update: %s: %s", diags[0].Summary, diags[0].Detail)
+	}
+
+	if n := backend.Calls("K8s.AddSlave"); n != 1 {
+		t.Errorf("expected one slave to be added, got %d", n)
+	}
+	if !updated.State["slave_count"].Equal(tftypes.NewValue(tftypes.Number, 1)) {
+		t.Errorf("expected slave_count 1, got %s", updated.State["slave_count"])
+	}
+	_ = updated.State["nodes"].As(&nodes)
+	if len(nodes) != 3 {
+		t.Errorf("expected the new node in state, got %d nodes", len(nodes))
+	}
+}
@@ -0,0 +1,59 @@
+package loadbalancer
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/list/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/vpsie/govpsie"
+	"github.com/vpsie/terraform-provider-vpsie/internal/listresult"
+)
+
+var (
+	_ list.ListResource              = &loadbalancerListResource{}
+	_ list.ListResourceWithConfigure = &loadbalancerListResource{}
+)
+
+type loadbalancerListResource struct {
+	resource loadbalancerResource
+}
+
+func NewLoadbalancerListResource() list.ListResource {
+	return &loadbalancerListResource{}
+}
+
+func (l *loadbalancerListResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	l.resource.Metadata(ctx, req, resp)
+}
+
+func (l *loadbalancerListResource) ListResourceConfigSchema(_ context.Context, _ list.ListResourceSchemaRequest, resp *list.ListResourceSchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Lists the VPSie load balancers in the account, for use with `terraform query`.",
+	}
+}
+
+func (l *loadbalancerListResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	l.resource.Configure(ctx, req, resp)
+}
+
+func (l *loadbalancerListResource) List(ctx context.Context, req list.ListRequest, stream *list.ListResultsStream) {
+	loadbalancers, err := l.resource.client.ListLBs(ctx, nil)
+	if err != nil {
+		var diags diag.Diagnostics
+		diags.AddError(
+			"Error listing load balancers",
+			"Could not list load balancers: "+err.Error(),
+		)
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	stream.Results = listresult.Stream(req, loadbalancers, func(lb govpsie.LB) list.ListResult {
+		return listresult.New(ctx, req, &l.resource, lb.LBName, loadbalancerIdentityModel{
+			Identifier: types.StringValue(lb.Identifier),
+		})
+	})
+}
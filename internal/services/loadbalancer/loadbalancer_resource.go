@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"strconv"
-	"strings"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
@@ -12,6 +11,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
@@ -19,12 +19,16 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/vpsie/govpsie"
+	"github.com/vpsie/terraform-provider-vpsie/internal/apierror"
+	"github.com/vpsie/terraform-provider-vpsie/internal/providerdata"
+	"github.com/vpsie/terraform-provider-vpsie/internal/waiter"
 )
 
 var (
 	_ resource.Resource                = &loadbalancerResource{}
 	_ resource.ResourceWithConfigure   = &loadbalancerResource{}
 	_ resource.ResourceWithImportState = &loadbalancerResource{}
+	_ resource.ResourceWithIdentity    = &loadbalancerResource{}
 )
 
 type loadbalancerResource struct {
@@ -95,6 +99,10 @@ type Backend struct {
 	CreatedOn    types.String `tfsdk:"created_on"`
 }
 
+type loadbalancerIdentityModel struct {
+	Identifier types.String `tfsdk:"identifier"`
+}
+
 func NewLoadbalancerResource() resource.Resource {
 	return &loadbalancerResource{}
 }
@@ -460,22 +468,33 @@ func (l *loadbalancerResource) Schema(ctx context.Context, _ resource.SchemaRequ
 	}
 }
 
+func (l *loadbalancerResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"identifier": identityschema.StringAttribute{
+				RequiredForImport: true,
+				Description:       "The unique identifier of the load balancer.",
+			},
+		},
+	}
+}
+
 func (l *loadbalancerResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
 		return
 	}
 
-	client, ok := req.ProviderData.(*govpsie.Client)
+	data, ok := req.ProviderData.(*providerdata.Data)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configuration Type",
-			fmt.Sprintf("Expected *govpsie.Client, got %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *providerdata.Data, got %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	l.client = client.LB
+	l.client = data.Client.LB
 }
 
 // Create creates the resource and sets the initial Terraform state.
@@ -553,116 +572,104 @@ func (l *loadbalancerResource) Create(ctx context.Context, req resource.CreateRe
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, createTimeout)
-	defer cancel()
-
-	for {
-		// Check if the context has expired
-		if ctx.Err() != nil {
-			resp.Diagnostics.AddError("error waiting for resource to become ready", ctx.Err().Error())
-			return
-		}
+	lb, err := (&waiter.Conf[govpsie.LBDetails]{
+		Pending: []string{waiter.StateCreating},
+		Target:  []string{waiter.StateCreated},
+		Refresh: waiter.Appeared(func(ctx context.Context) (*govpsie.LBDetails, bool, error) {
+			return l.checkResourceStatus(ctx, plan.LBName.ValueString())
+		}),
+		Timeout: createTimeout,
+	}).Wait(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Error waiting for load balancer to become ready", err.Error())
+		return
+	}
 
-		// Check resource status here using provider's API or SDK
-		lb, ready, err := l.checkResourceStatus(ctx, plan.LBName.ValueString())
-		if err != nil {
-			//  return fmt.Errorf("Timeout waiting for resource to become ready")
-			resp.Diagnostics.AddError("Error checking status of resource", err.Error())
-			return
-		}
+	plan.Identifier = types.StringValue(lb.Identifier)
+	plan.UserID = types.Int64Value(int64(lb.UserID))
+	plan.DcName = types.StringValue(lb.DcName)
+	plan.CreatedBy = types.StringValue(lb.CreatedBy)
+	plan.LBName = types.StringValue(lb.LBName)
+	plan.Traffic = types.Int64Value(int64(lb.Traffic))
+	plan.BoxsizeID = types.Int64Value(int64(lb.BoxsizeID))
+	plan.DefaultIP = types.StringValue(lb.DefaultIP)
+	plan.DcName = types.StringValue(lb.DcName)
+	plan.DcID = types.StringValue(lb.DcID)
+	plan.CreatedBy = types.StringValue(lb.CreatedBy)
+	plan.UserID = types.Int64Value(int64(lb.UserID))
+
+	var lbRules = []LBRule{}
+	for _, rule := range lb.Rules {
+		var newRule = LBRule{}
+		newRule.BackPort = types.Int64Value(int64(rule.BackPort))
+		newRule.FrontPort = types.Int64Value(int64(rule.FrontPort))
+		newRule.Scheme = types.StringValue(rule.Scheme)
+		newRule.CreatedOn = types.StringValue(rule.CreatedOn.String())
+		newRule.RuleID = types.StringValue(rule.RuleID)
 
-		if ready {
-			plan.Identifier = types.StringValue(lb.Identifier)
-			plan.UserID = types.Int64Value(int64(lb.UserID))
-			plan.DcName = types.StringValue(lb.DcName)
-			plan.CreatedBy = types.StringValue(lb.CreatedBy)
-			plan.LBName = types.StringValue(lb.LBName)
-			plan.Traffic = types.Int64Value(int64(lb.Traffic))
-			plan.BoxsizeID = types.Int64Value(int64(lb.BoxsizeID))
-			plan.DefaultIP = types.StringValue(lb.DefaultIP)
-			plan.DcName = types.StringValue(lb.DcName)
-			plan.DcID = types.StringValue(lb.DcID)
-			plan.CreatedBy = types.StringValue(lb.CreatedBy)
-			plan.UserID = types.Int64Value(int64(lb.UserID))
-
-			var rules = []LBRule{}
-			for _, rule := range lb.Rules {
-				var newRule = LBRule{}
-				newRule.BackPort = types.Int64Value(int64(rule.BackPort))
-				newRule.FrontPort = types.Int64Value(int64(rule.FrontPort))
-				newRule.Scheme = types.StringValue(rule.Scheme)
-				newRule.CreatedOn = types.StringValue(rule.CreatedOn.String())
-				newRule.RuleID = types.StringValue(rule.RuleID)
-
-				domains := []LBDomain{}
-				for _, dns := range rule.Domains {
-					domain := LBDomain{}
-					domain.DomainName = types.StringValue(dns.DomainName)
-					domain.CreatedOn = types.StringValue(dns.CreatedOn.String())
-
-					var subdomain types.String
-					if dns.Subdomain != nil && *dns.Subdomain != "" {
-						subdomain = types.StringValue(*dns.Subdomain)
-					}
-
-					domain.Subdomain = subdomain
-					domain.BackendScheme = types.StringValue(dns.BackendScheme)
-					domain.Algorithm = types.StringValue(dns.Algorithm)
-					domain.RedirectHTTP = types.Int64Value(int64(dns.RedirectHTTP))
-					domain.HealthCheckPath = types.StringValue(dns.HealthCheckPath)
-					domain.CookieCheck = types.Int64Value(int64(dns.CookieCheck))
-					domain.CookieName = types.StringValue(dns.CookieName)
-					domain.CreatedOn = types.StringValue(dns.CreatedOn.String())
-					domain.BackPort = types.Int64Value(int64(dns.BackPort))
-					domain.DomainID = types.StringValue(dns.DomainID)
-					domain.CheckInterval = types.Int64Value(int64(dns.CheckInterval))
-					domain.FastInterval = types.Int64Value(int64(dns.FastInterval))
-					domain.Rise = types.Int64Value(int64(dns.Rise))
-					domain.Fall = types.Int64Value(int64(dns.Fall))
-
-					dnsBackends := []Backend{}
-					for _, backend := range dns.Backends {
-						dnsBackends = append(dnsBackends, Backend{
-							IP:           types.StringValue(backend.IP),
-							Identifier:   types.StringValue(backend.Identifier),
-							VMIdentifier: types.StringValue(backend.VMIdentifier),
-							CreatedOn:    types.StringValue(backend.CreatedOn.String()),
-						})
-					}
-					domain.Backends = dnsBackends
-					domains = append(domains, domain)
-				}
+		domains := []LBDomain{}
+		for _, dns := range rule.Domains {
+			domain := LBDomain{}
+			domain.DomainName = types.StringValue(dns.DomainName)
+			domain.CreatedOn = types.StringValue(dns.CreatedOn.String())
 
-				newRule.Domains = domains
+			var subdomain types.String
+			if dns.Subdomain != nil && *dns.Subdomain != "" {
+				subdomain = types.StringValue(*dns.Subdomain)
+			}
 
-				backends := []Backend{}
-				for _, backend := range rule.Backends {
-					backends = append(backends, Backend{
-						IP:           types.StringValue(backend.IP),
-						Identifier:   types.StringValue(backend.Identifier),
-						VMIdentifier: types.StringValue(backend.VMIdentifier),
-						CreatedOn:    types.StringValue(backend.CreatedOn.String()),
-					})
-				}
-				newRule.Backends = backends
+			domain.Subdomain = subdomain
+			domain.BackendScheme = types.StringValue(dns.BackendScheme)
+			domain.Algorithm = types.StringValue(dns.Algorithm)
+			domain.RedirectHTTP = types.Int64Value(int64(dns.RedirectHTTP))
+			domain.HealthCheckPath = types.StringValue(dns.HealthCheckPath)
+			domain.CookieCheck = types.Int64Value(int64(dns.CookieCheck))
+			domain.CookieName = types.StringValue(dns.CookieName)
+			domain.CreatedOn = types.StringValue(dns.CreatedOn.String())
+			domain.BackPort = types.Int64Value(int64(dns.BackPort))
+			domain.DomainID = types.StringValue(dns.DomainID)
+			domain.CheckInterval = types.Int64Value(int64(dns.CheckInterval))
+			domain.FastInterval = types.Int64Value(int64(dns.FastInterval))
+			domain.Rise = types.Int64Value(int64(dns.Rise))
+			domain.Fall = types.Int64Value(int64(dns.Fall))
 
-				rules = append(rules, newRule)
+			dnsBackends := []Backend{}
+			for _, backend := range dns.Backends {
+				dnsBackends = append(dnsBackends, Backend{
+					IP:           types.StringValue(backend.IP),
+					Identifier:   types.StringValue(backend.Identifier),
+					VMIdentifier: types.StringValue(backend.VMIdentifier),
+					CreatedOn:    types.StringValue(backend.CreatedOn.String()),
+				})
 			}
-			plan.Rules = rules
+			domain.Backends = dnsBackends
+			domains = append(domains, domain)
+		}
 
-			diags = resp.State.Set(ctx, plan)
-			resp.Diagnostics.Append(diags...)
-			if resp.Diagnostics.HasError() {
-				return
-			}
+		newRule.Domains = domains
 
-			return
+		backends := []Backend{}
+		for _, backend := range rule.Backends {
+			backends = append(backends, Backend{
+				IP:           types.StringValue(backend.IP),
+				Identifier:   types.StringValue(backend.Identifier),
+				VMIdentifier: types.StringValue(backend.VMIdentifier),
+				CreatedOn:    types.StringValue(backend.CreatedOn.String()),
+			})
 		}
+		newRule.Backends = backends
+
+		lbRules = append(lbRules, newRule)
+	}
+	plan.Rules = lbRules
 
-		// Wait for a delay before retrying
-		time.Sleep(5 * time.Second)
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, loadbalancerIdentityModel{Identifier: plan.Identifier})...)
 }
 
 // Read refreshes the Terraform state with the latest data.
@@ -676,7 +683,7 @@ func (l *loadbalancerResource) Read(ctx context.Context, req resource.ReadReques
 
 	lb, err := l.client.GetLB(ctx, state.Identifier.ValueString())
 	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
+		if apierror.IsNotFound(err) {
 			resp.State.RemoveResource(ctx)
 			return
 		}
@@ -772,6 +779,8 @@ func (l *loadbalancerResource) Read(ctx context.Context, req resource.ReadReques
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, loadbalancerIdentityModel{Identifier: state.Identifier})...)
 }
 
 // Update updates the resource and sets the updated Terraform state on success.
@@ -945,7 +954,7 @@ func (l *loadbalancerResource) Delete(ctx context.Context, req resource.DeleteRe
 }
 
 func (l *loadbalancerResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("identifier"), req, resp)
+	resource.ImportStatePassthroughWithIdentity(ctx, path.Root("identifier"), path.Root("identifier"), req, resp)
 }
 
 func (l *loadbalancerResource) checkResourceStatus(ctx context.Context, lbName string) (*govpsie.LBDetails, bool, error) {
@@ -7,18 +7,22 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/vpsie/govpsie"
+	"github.com/vpsie/terraform-provider-vpsie/internal/apierror"
+	"github.com/vpsie/terraform-provider-vpsie/internal/providerdata"
 )
 
 var (
 	_ resource.Resource                = &monitoringRuleResource{}
 	_ resource.ResourceWithConfigure   = &monitoringRuleResource{}
 	_ resource.ResourceWithImportState = &monitoringRuleResource{}
+	_ resource.ResourceWithIdentity    = &monitoringRuleResource{}
 )
 
 type monitoringRuleResource struct {
@@ -43,6 +47,10 @@ type monitoringRuleResourceModel struct {
 	CreatedOn     types.String `tfsdk:"created_on"`
 }
 
+type monitoringRuleIdentityModel struct {
+	Identifier types.String `tfsdk:"identifier"`
+}
+
 func NewMonitoringRuleResource() resource.Resource {
 	return &monitoringRuleResource{}
 }
@@ -186,21 +194,32 @@ func (m *monitoringRuleResource) Schema(_ context.Context, _ resource.SchemaRequ
 	}
 }
 
+func (m *monitoringRuleResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"identifier": identityschema.StringAttribute{
+				RequiredForImport: true,
+				Description:       "The unique identifier of the monitoring rule.",
+			},
+		},
+	}
+}
+
 func (m *monitoringRuleResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
 		return
 	}
 
-	client, ok := req.ProviderData.(*govpsie.Client)
+	data, ok := req.ProviderData.(*providerdata.Data)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configuration Type",
-			fmt.Sprintf("Expected *govpsie.Client, got %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *providerdata.Data, got %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 		return
 	}
 
-	m.client = client.Monitoring
+	m.client = data.Client.Monitoring
 }
 
 func (m *monitoringRuleResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -256,6 +275,8 @@ func (m *monitoringRuleResource) Create(ctx context.Context, req resource.Create
 
 	diags = resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
+
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, monitoringRuleIdentityModel{Identifier: plan.Identifier})...)
 }
 
 func (m *monitoringRuleResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
@@ -297,6 +318,8 @@ func (m *monitoringRuleResource) Read(ctx context.Context, req resource.ReadRequ
 
 	diags = resp.State.Set(ctx, &state)
 	resp.Diagnostics.Append(diags...)
+
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, monitoringRuleIdentityModel{Identifier: state.Identifier})...)
 }
 
 func (m *monitoringRuleResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
@@ -329,7 +352,7 @@ func (m *monitoringRuleResource) Update(ctx context.Context, req resource.Update
 }
 
 func (m *monitoringRuleResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("identifier"), req, resp)
+	resource.ImportStatePassthroughWithIdentity(ctx, path.Root("identifier"), path.Root("identifier"), req, resp)
 }
 
 func (m *monitoringRuleResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
@@ -362,5 +385,5 @@ func (m *monitoringRuleResource) GetRuleByName(ctx context.Context, name string)
 		}
 	}
 
-	return nil, fmt.Errorf("monitoring rule with name %s not found", name)
+	return nil, apierror.NotFoundf("monitoring rule with name %s not found", name)
 }
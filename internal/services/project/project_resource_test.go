@@ -10,6 +10,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-testing/terraform"
 	"github.com/vpsie/govpsie"
 	"github.com/vpsie/terraform-provider-vpsie/internal/acctest"
+	"github.com/vpsie/terraform-provider-vpsie/internal/fakeapi"
 )
 
 func TestAccProjectResource(t *testing.T) {
@@ -71,3 +72,48 @@ func testAccCheckProjectResourceDestroy(s *terraform.State) error {
 
 	return nil
 }
+
+func TestUnitProjectResource_Lifecycle(t *testing.T) {
+	backend := fakeapi.New()
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheckTerraformCLI(t) },
+		ProtoV6ProviderFactories: acctest.FakeProtoV6ProviderFactories(backend),
+		CheckDestroy: func(s *terraform.State) error {
+			projects, err := backend.Client().Project.List(context.Background(), nil)
+			if err != nil {
+				return err
+			}
+			if len(projects) != 0 {
+				return fmt.Errorf("%d projects left after destroy", len(projects))
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProjectConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("vpsie_project.test", "name", "tf-acc-test-project"),
+					resource.TestCheckResourceAttrSet("vpsie_project.test", "identifier"),
+				),
+			},
+			{
+				ResourceName:      "vpsie_project.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// Deleted outside of Terraform: the next plan recreates it.
+			{
+				PreConfig: func() {
+					client := backend.Client()
+					projects, _ := client.Project.List(context.Background(), nil)
+					for _, project := range projects {
+						_ = client.Project.Delete(context.Background(), project.Identifier)
+					}
+				},
+				Config: testAccProjectConfig,
+				Check:  resource.TestCheckResourceAttrSet("vpsie_project.test", "identifier"),
+			},
+		},
+	})
+}
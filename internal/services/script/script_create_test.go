@@ -0,0 +1,39 @@
+package script_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/vpsie/terraform-provider-vpsie/internal/acctest"
+	"github.com/vpsie/terraform-provider-vpsie/internal/apitest"
+)
+
+func TestUnitScriptResource_CreateRequest(t *testing.T) {
+	api := apitest.New(t)
+	api.Handle(http.MethodPost, "/apps/v2/script/add", `{"error":false}`)
+	api.Handle(http.MethodGet, "/apps/v2/scripts", `{"error":false,"data":[{"script_name":"deploy","identifier":"script-1"}],"total":1}`)
+	api.Handle(http.MethodGet, "/apps/v2/script/script-1", `{"error":false,"data":{
+		"id":7,"name":"deploy","script_name":"deploy","script":"echo hi","type":"bash",
+		"identifier":"script-1","created_on":"2026-10-17T00:00:00Z"}}`)
+	server, schemas := acctest.ProtoV6Server(t, api.Client(), nil)
+
+	created, diags := acctest.ApplyResource(t, server, schemas, "vpsie_script", nil, map[string]tftypes.Value{
+		"script_name": tftypes.NewValue(tftypes.String, "deploy"),
+		"script":      tftypes.NewValue(tftypes.String, "echo hi"),
+		"type":        tftypes.NewValue(tftypes.String, "bash"),
+	})
+	if len(diags) > 0 {
+		t.Fatalf("create: %s: %s", diags[0].Summary, diags[0].Detail)
+	}
+
+	requests := api.Requests(http.MethodPost, "/apps/v2/script/add")
+	if len(requests) != 1 {
+		t.Fatalf("expected one create request, got %d", len(requests))
+	}
+	apitest.EqualJSON(t, requests[0].Body, `{"name":"deploy","scriptContent":"echo hi","scriptType":"bash","tags":[]}`)
+
+	if !created.State["identifier"].Equal(tftypes.NewValue(tftypes.String, "script-1")) {
+		t.Errorf("expected identifier script-1, got %s", created.State["identifier"])
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
@@ -15,12 +16,15 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/vpsie/govpsie"
+	"github.com/vpsie/terraform-provider-vpsie/internal/apierror"
+	"github.com/vpsie/terraform-provider-vpsie/internal/providerdata"
 )
 
 var (
 	_ resource.Resource                = &scriptResource{}
 	_ resource.ResourceWithConfigure   = &scriptResource{}
 	_ resource.ResourceWithImportState = &scriptResource{}
+	_ resource.ResourceWithIdentity    = &scriptResource{}
 )
 
 type scriptResource struct {
@@ -40,6 +44,10 @@ type scriptResourceModel struct {
 	Type          types.String `tfsdk:"type"`
 }
 
+type scriptIdentityModel struct {
+	Identifier types.String `tfsdk:"identifier"`
+}
+
 func NewScriptResource() resource.Resource {
 	return &scriptResource{}
 }
@@ -125,22 +133,33 @@ func (s *scriptResource) Schema(_ context.Context, _ resource.SchemaRequest, res
 	}
 }
 
+func (s *scriptResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"identifier": identityschema.StringAttribute{
+				RequiredForImport: true,
+				Description:       "The unique identifier of the script.",
+			},
+		},
+	}
+}
+
 func (s *scriptResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
 		return
 	}
 
-	client, ok := req.ProviderData.(*govpsie.Client)
+	data, ok := req.ProviderData.(*providerdata.Data)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configuration Type",
-			fmt.Sprintf("Expected *govpsie.Client, got %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *providerdata.Data, got %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	s.client = client.Scripts
+	s.client = data.Client.Scripts
 }
 
 // Create creates the resource and sets the initial Terraform state.
@@ -188,6 +207,8 @@ func (s *scriptResource) Create(ctx context.Context, req resource.CreateRequest,
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, scriptIdentityModel{Identifier: plan.Identifier})...)
 }
 
 // Read refreshes the Terraform state with the latest data.
@@ -201,7 +222,7 @@ func (s *scriptResource) Read(ctx context.Context, req resource.ReadRequest, res
 
 	script, err := s.client.GetScript(ctx, state.Identifier.ValueString())
 	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
+		if apierror.IsNotFound(err) {
 			resp.State.RemoveResource(ctx)
 			return
 		}
@@ -231,6 +252,8 @@ func (s *scriptResource) Read(ctx context.Context, req resource.ReadRequest, res
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, scriptIdentityModel{Identifier: state.Identifier})...)
 }
 
 // Update updates the resource and sets the updated Terraform state on success.
@@ -297,7 +320,7 @@ func (s *scriptResource) Delete(ctx context.Context, req resource.DeleteRequest,
 }
 
 func (s *scriptResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("identifier"), req, resp)
+	resource.ImportStatePassthroughWithIdentity(ctx, path.Root("identifier"), path.Root("identifier"), req, resp)
 }
 
 func (s *scriptResource) GetScriptByName(ctx context.Context, scriptName string) (*govpsie.ScriptDetail, error) {
@@ -317,5 +340,5 @@ func (s *scriptResource) GetScriptByName(ctx context.Context, scriptName string)
 		}
 	}
 
-	return nil, fmt.Errorf("script with name %s not found", scriptName)
+	return nil, apierror.NotFoundf("script with name %s not found", scriptName)
 }
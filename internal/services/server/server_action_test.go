@@ -0,0 +1,88 @@
+package server_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/vpsie/govpsie"
+	"github.com/vpsie/terraform-provider-vpsie/internal/acctest"
+	"github.com/vpsie/terraform-provider-vpsie/internal/fakeapi"
+)
+
+func testFakeServer(t *testing.T, client *govpsie.Client) string {
+	t.Helper()
+	ctx := context.Background()
+
+	if err := client.Server.CreateServer(ctx, &govpsie.CreateServerRequest{Hostname: "web", DcIdentifier: "dc-1"}); err != nil {
+		t.Fatalf("CreateServer: %v", err)
+	}
+	servers, err := client.Server.List(ctx, nil)
+	if err != nil || len(servers) != 1 {
+		t.Fatalf("List: %v %+v", err, servers)
+	}
+	return servers[0].Identifier
+}
+
+func TestUnitServerPowerAction_StopStart(t *testing.T) {
+	backend := fakeapi.New()
+	client := backend.Client()
+	identifier := testFakeServer(t, client)
+	server, schemas := acctest.FakeProtoV6Server(t, backend, nil)
+
+	for _, state := range []string{"stopped", "running"} {
+		_, diags := acctest.InvokeAction(t, server, schemas, "vpsie_server_power", map[string]tftypes.Value{
+			"identifier": tftypes.NewValue(tftypes.String, identifier),
+			"state":      tftypes.NewValue(tftypes.String, state),
+		})
+		if len(diags) > 0 {
+			t.Fatalf("power %s: %s", state, diags[0].Detail)
+		}
+
+		vm, err := client.Server.GetServerByIdentifier(context.Background(), identifier)
+		if err != nil {
+			t.Fatalf("GetServerByIdentifier: %v", err)
+		}
+		if vm.State != state {
+			t.Errorf("expected the server to be %s, got %s", state, vm.State)
+		}
+	}
+}
+
+func TestUnitServerRebootAction(t *testing.T) {
+	backend := fakeapi.New()
+	identifier := testFakeServer(t, backend.Client())
+	server, schemas := acctest.FakeProtoV6Server(t, backend, nil)
+	backend.QueuedPolls = 1
+	backend.ProvisioningPolls = 1
+
+	progress, diags := acctest.InvokeAction(t, server, schemas, "vpsie_server_reboot", map[string]tftypes.Value{
+		"identifier": tftypes.NewValue(tftypes.String, identifier),
+	})
+	if len(diags) > 0 {
+		t.Fatalf("reboot: %s", diags[0].Detail)
+	}
+	if got := backend.Calls("Server.RestartServer"); got != 1 {
+		t.Errorf("expected one restart, got %d", got)
+	}
+	// The server reads as before the reboot, then as restarting, then as
+	// running again.
+	if got := backend.Calls("Server.GetServerByIdentifier"); got != 3 {
+		t.Errorf("expected to wait until the server restarted, got %d reads", got)
+	}
+	if len(progress) != 2 {
+		t.Errorf("expected two progress messages, got %q", progress)
+	}
+}
+
+func TestUnitServerRebootAction_NotFound(t *testing.T) {
+	backend := fakeapi.New()
+	server, schemas := acctest.FakeProtoV6Server(t, backend, nil)
+
+	_, diags := acctest.InvokeAction(t, server, schemas, "vpsie_server_reboot", map[string]tftypes.Value{
+		"identifier": tftypes.NewValue(tftypes.String, "missing"),
+	})
+	if len(diags) != 1 || diags[0].Summary != "Error rebooting server" {
+		t.Fatalf("expected a reboot error, got %+v", diags)
+	}
+}
@@ -7,7 +7,7 @@ import (
 )
 
 // ServerAPI defines the subset of govpsie.ServerService methods
-// used by the server resource and data source in this provider.
+// used by the server resource, data source and actions in this provider.
 type ServerAPI interface {
 	CreateServer(ctx context.Context, req *govpsie.CreateServerRequest) error
 	List(ctx context.Context, options *govpsie.ListOptions) ([]govpsie.VmData, error)
@@ -16,9 +16,18 @@ type ServerAPI interface {
 	ChangeHostName(ctx context.Context, identifierId string, newHostname string) error
 	StartServer(ctx context.Context, identifierId string) error
 	StopServer(ctx context.Context, identifierId string) error
+	RestartServer(ctx context.Context, identifierId string) error
 	Lock(ctx context.Context, identifierId string) error
 	UnLock(ctx context.Context, identifierId string) error
 	AddSsh(ctx context.Context, identifierId, sshKeyIdentifier string) error
 	AddScript(ctx context.Context, identifierId, scriptIdentifier string) error
 	ResizeServer(ctx context.Context, identifierId, cpu, ram string) error
 }
+
+// ScriptAPI defines the subset of govpsie.ScriptsService methods used by the
+// server resource to manage the script that holds a server's user data.
+type ScriptAPI interface {
+	CreateScript(ctx context.Context, createScriptRequest *govpsie.CreateScriptRequest) error
+	GetScripts(ctx context.Context) ([]govpsie.Script, error)
+	DeleteScript(ctx context.Context, scriptId string) error
+}
@@ -8,15 +8,17 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 
-	"github.com/vpsie/govpsie"
+	"github.com/vpsie/terraform-provider-vpsie/internal/providerdata"
 )
 
 type serverDataSource struct {
 	client ServerAPI
+	tags   ServerTagsAPI
 }
 
 type serverDataSourceModel struct {
 	Servers []serversModel `tfsdk:"servers"`
+	Tags    types.Set      `tfsdk:"tags"`
 	ID      types.String   `tfsdk:"id"`
 }
 
@@ -89,6 +91,7 @@ type serversModel struct {
 	IsSataAvailable     types.Int64  `tfsdk:"is_sata_available"`
 	IsSsdAvailable      types.Int64  `tfsdk:"is_ssd_available"`
 	PublicIp            types.String `tfsdk:"public_ip"`
+	Tags                types.Set    `tfsdk:"tags"`
 }
 
 // NewServerDataSource is a helper function to create the data source.
@@ -110,6 +113,11 @@ func (s *serverDataSource) Schema(_ context.Context, _ datasource.SchemaRequest,
 				Computed:            true,
 				MarkdownDescription: "The ID of this data source.",
 			},
+			"tags": schema.SetAttribute{
+				Optional:            true,
+				ElementType:         types.StringType,
+				MarkdownDescription: "Only return servers that have all of these tags.",
+			},
 			"servers": schema.ListNestedAttribute{
 				Computed:            true,
 				MarkdownDescription: "The list of servers.",
@@ -400,6 +408,11 @@ func (s *serverDataSource) Schema(_ context.Context, _ datasource.SchemaRequest,
 							Optional:            true,
 							MarkdownDescription: "The public IP address of the server.",
 						},
+						"tags": schema.SetAttribute{
+							Computed:            true,
+							ElementType:         types.StringType,
+							MarkdownDescription: "The tags assigned to the server.",
+						},
 					},
 				},
 			},
@@ -410,6 +423,19 @@ func (s *serverDataSource) Schema(_ context.Context, _ datasource.SchemaRequest,
 // Read refreshes the Terraform state with the latest data.
 func (s *serverDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
 	var state serverDataSourceModel
+	diags := req.Config.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var filter []string
+	if !state.Tags.IsNull() {
+		resp.Diagnostics.Append(state.Tags.ElementsAs(ctx, &filter, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
 
 	servers, err := s.client.List(ctx, nil)
 	if err != nil {
@@ -422,6 +448,26 @@ func (s *serverDataSource) Read(ctx context.Context, req datasource.ReadRequest,
 	}
 
 	for _, server := range servers {
+		tags, err := s.tags.ListServerTags(ctx, server.Identifier)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error reading servers",
+				"Could not read tags of server "+server.Identifier+": "+err.Error(),
+			)
+
+			return
+		}
+
+		if added, _ := diffServerTags(filter, tags); len(added) > 0 {
+			continue
+		}
+
+		serverTags, diags := types.SetValueFrom(ctx, types.StringType, tags)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
 		serverState := serversModel{
 			ID:                  types.Int64Value(server.ID),
 			Identifier:          types.StringValue(server.Identifier),
@@ -491,13 +537,14 @@ func (s *serverDataSource) Read(ctx context.Context, req datasource.ReadRequest,
 			IsSataAvailable:     types.Int64Value(server.IsSataAvailable),
 			IsSsdAvailable:      types.Int64Value(server.IsSsdAvailable),
 			PublicIp:            types.StringPointerValue(server.PublicIp),
+			Tags:                serverTags,
 		}
 
 		state.Servers = append(state.Servers, serverState)
 	}
 
 	state.ID = types.StringValue("servers")
-	diags := resp.State.Set(ctx, &state)
+	diags = resp.State.Set(ctx, &state)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
@@ -510,16 +557,17 @@ func (s *serverDataSource) Configure(_ context.Context, req datasource.Configure
 		return
 	}
 
-	client, ok := req.ProviderData.(*govpsie.Client)
+	data, ok := req.ProviderData.(*providerdata.Data)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configuration Type",
-			fmt.Sprintf("Expected *govpsie.Client, got %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *providerdata.Data, got %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	s.client = client.Server
+	s.client = data.Client.Server
+	s.tags = newServerTagsClient(data.Client)
 }
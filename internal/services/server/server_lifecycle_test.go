@@ -0,0 +1,192 @@
+package server_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/vpsie/govpsie"
+	"github.com/vpsie/terraform-provider-vpsie/internal/acctest"
+	"github.com/vpsie/terraform-provider-vpsie/internal/fakeapi"
+)
+
+func TestUnitServerResource_Lifecycle(t *testing.T) {
+	backend := fakeapi.New()
+	backend.ProvisioningPolls = 1
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheckTerraformCLI(t) },
+		ProtoV6ProviderFactories: acctest.FakeProtoV6ProviderFactories(backend),
+		CheckDestroy: func(s *terraform.State) error {
+			servers, err := backend.Client().Server.List(context.Background(), nil)
+			if err != nil {
+				return err
+			}
+			if len(servers) != 0 {
+				return fmt.Errorf("%d servers left after destroy", len(servers))
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testServerLifecycleConfig("tf-fake-server"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("vpsie_server.test", "hostname", "tf-fake-server"),
+					resource.TestCheckResourceAttrSet("vpsie_server.test", "identifier"),
+				),
+			},
+			{
+				Config: testServerLifecycleConfig("tf-fake-server-renamed"),
+				Check:  resource.TestCheckResourceAttr("vpsie_server.test", "hostname", "tf-fake-server-renamed"),
+			},
+		},
+	})
+}
+
+// TestUnitServerResource_DeleteWithProviderPassword destroys a server whose
+// state holds no deletion password, as Terraform would, over the plugin
+// protocol.
+func TestUnitServerResource_DeleteWithProviderPassword(t *testing.T) {
+	ctx := context.Background()
+	backend := fakeapi.New()
+	client := backend.Client()
+
+	if err := client.Server.CreateServer(ctx, &govpsie.CreateServerRequest{Hostname: "web", DcIdentifier: "dc-1"}); err != nil {
+		t.Fatalf("CreateServer: %v", err)
+	}
+	servers, _ := client.Server.List(ctx, nil)
+	identifier := servers[0].Identifier
+
+	destroy := func(server tfprotov6.ProviderServer, schemas *tfprotov6.GetProviderSchemaResponse) []*tfprotov6.Diagnostic {
+		serverType := schemas.ResourceSchemas["vpsie_server"].ValueType()
+		resp, err := server.ApplyResourceChange(ctx, &tfprotov6.ApplyResourceChangeRequest{
+			TypeName: "vpsie_server",
+			PriorState: acctest.DynamicValue(t, serverType, acctest.ObjectValue(serverType, map[string]tftypes.Value{
+				"identifier": tftypes.NewValue(tftypes.String, identifier),
+			})),
+			PlannedState: acctest.DynamicValue(t, serverType, tftypes.NewValue(serverType, nil)),
+			Config:       acctest.DynamicValue(t, serverType, tftypes.NewValue(serverType, nil)),
+		})
+		if err != nil {
+			t.Fatalf("ApplyResourceChange: %v", err)
+		}
+		return resp.Diagnostics
+	}
+
+	if diags := destroy(acctest.FakeProtoV6Server(t, backend, nil)); len(diags) == 0 || !strings.Contains(diags[0].Detail, "delete_password") {
+		t.Fatalf("expected an error asking for delete_password, got %+v", diags)
+	}
+
+	diags := destroy(acctest.FakeProtoV6Server(t, backend, map[string]tftypes.Value{
+		"delete_password": tftypes.NewValue(tftypes.String, "provider-password"),
+		"delete_reason":   tftypes.NewValue(tftypes.String, "test"),
+	}))
+	if len(diags) > 0 {
+		t.Fatalf("unexpected diagnostics: %s", diags[0].Detail)
+	}
+
+	if servers, _ := client.Server.List(ctx, nil); len(servers) != 0 {
+		t.Fatalf("expected the server to be deleted, got %+v", servers)
+	}
+}
+
+func testServerLifecycleConfig(hostname string) string {
+	return fmt.Sprintf(`
+provider "vpsie" {
+  delete_password = "fake-password"
+  delete_reason   = "test"
+}
+
+resource "vpsie_server" "test" {
+  project_id          = 1
+  resource_identifier = "plan-1"
+  os_identifier       = "os-1"
+  dc_identifier       = "dc-1"
+  hostname            = %q
+}
+`, hostname)
+}
+
+// TestUnitServerResource_CreateFollowsMarker creates a server next to one
+// with the same hostname and checks that the new VM is found by its create
+// marker, which is removed afterwards.
+func TestUnitServerResource_CreateFollowsMarker(t *testing.T) {
+	ctx := context.Background()
+	backend := fakeapi.New()
+	client := backend.Client()
+	server, schemas := acctest.FakeProtoV6Server(t, backend, nil)
+
+	if err := client.Server.CreateServer(ctx, &govpsie.CreateServerRequest{Hostname: "web", DcIdentifier: "dc-1"}); err != nil {
+		t.Fatalf("CreateServer: %v", err)
+	}
+	servers, _ := client.Server.List(ctx, nil)
+	other := servers[0].Identifier
+
+	created, diags := acctest.ApplyResource(t, server, schemas, "vpsie_server", nil, map[string]tftypes.Value{
+		"project_id":          tftypes.NewValue(tftypes.Number, 1),
+		"resource_identifier": tftypes.NewValue(tftypes.String, "plan-small"),
+		"os_identifier":       tftypes.NewValue(tftypes.String, "ubuntu-24.04"),
+		"dc_identifier":       tftypes.NewValue(tftypes.String, "dc-1"),
+		"hostname":            tftypes.NewValue(tftypes.String, "web"),
+	})
+	if len(diags) > 0 {
+		t.Fatalf("create: %s: %s", diags[0].Summary, diags[0].Detail)
+	}
+
+	var identifier string
+	_ = created.State["identifier"].As(&identifier)
+	if identifier == "" || identifier == other {
+		t.Fatalf("expected the new server, got %q", identifier)
+	}
+
+	serverTags, err := client.Server.(interface {
+		ListServerTags(ctx context.Context, identifierId string) ([]string, error)
+	}).ListServerTags(ctx, identifier)
+	if err != nil {
+		t.Fatalf("ListServerTags: %v", err)
+	}
+	for _, tag := range serverTags {
+		if strings.HasPrefix(tag, "terraform-create-") {
+			t.Errorf("expected the create marker to be removed, got tags %v", serverTags)
+		}
+	}
+}
+
+func TestUnitServerResource_StoreInitialPassword(t *testing.T) {
+	backend := fakeapi.New()
+	server, schemas := acctest.FakeProtoV6Server(t, backend, nil)
+	prior, identity := testServerState(t, backend, server, schemas)
+
+	noPassword := tftypes.NewValue(tftypes.String, nil)
+	if !prior["initial_password"].Equal(noPassword) {
+		t.Fatalf("expected an imported server not to store its password, got %s", prior["initial_password"])
+	}
+
+	priorState, config, plan := testServerPlan(t, server, schemas, prior, identity, nil)
+	if len(plan.Diagnostics) > 0 {
+		t.Fatalf("plan: %s", plan.Diagnostics[0].Detail)
+	}
+	state := testServerApply(t, server, schemas, priorState, config, plan)
+	if !state["store_initial_password"].Equal(tftypes.NewValue(tftypes.Bool, false)) || !state["initial_password"].Equal(noPassword) {
+		t.Fatalf("expected the password not to be stored by default, got %s and %s", state["store_initial_password"], state["initial_password"])
+	}
+
+	priorState, config, plan = testServerPlan(t, server, schemas, state, plan.PlannedIdentity, map[string]tftypes.Value{
+		"store_initial_password": tftypes.NewValue(tftypes.Bool, true),
+	})
+	if len(plan.Diagnostics) > 0 {
+		t.Fatalf("plan: %s", plan.Diagnostics[0].Detail)
+	}
+	state = testServerApply(t, server, schemas, priorState, config, plan)
+
+	var password string
+	_ = state["initial_password"].As(&password)
+	if !strings.HasPrefix(password, "initial-password-") {
+		t.Fatalf("expected the password to be stored once opted in, got %s", state["initial_password"])
+	}
+}
@@ -0,0 +1,103 @@
+package server
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/list/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/vpsie/govpsie"
+	"github.com/vpsie/terraform-provider-vpsie/internal/listresult"
+)
+
+var (
+	_ list.ListResource              = &serverListResource{}
+	_ list.ListResourceWithConfigure = &serverListResource{}
+)
+
+type serverListResource struct {
+	resource serverResource
+}
+
+type serverListResourceModel struct {
+	Tags types.List `tfsdk:"tags"`
+}
+
+func NewServerListResource() list.ListResource {
+	return &serverListResource{}
+}
+
+func (l *serverListResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	l.resource.Metadata(ctx, req, resp)
+}
+
+func (l *serverListResource) ListResourceConfigSchema(_ context.Context, _ list.ListResourceSchemaRequest, resp *list.ListResourceSchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Lists the VPSie servers in the account, for use with `terraform query`.",
+		Attributes: map[string]schema.Attribute{
+			"tags": schema.ListAttribute{
+				Optional:            true,
+				ElementType:         types.StringType,
+				MarkdownDescription: "Only list servers that have all of these tags.",
+			},
+		},
+	}
+}
+
+func (l *serverListResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	l.resource.Configure(ctx, req, resp)
+}
+
+func (l *serverListResource) List(ctx context.Context, req list.ListRequest, stream *list.ListResultsStream) {
+	var config serverListResourceModel
+	diags := req.Config.Get(ctx, &config)
+	if diags.HasError() {
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	var filter []string
+	if !config.Tags.IsNull() {
+		diags.Append(config.Tags.ElementsAs(ctx, &filter, false)...)
+		if diags.HasError() {
+			stream.Results = list.ListResultsStreamDiagnostics(diags)
+			return
+		}
+	}
+
+	servers, err := l.resource.client.List(ctx, nil)
+	if err != nil {
+		diags.AddError(
+			"Error listing servers",
+			"Could not list servers: "+err.Error(),
+		)
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	var matched []govpsie.VmData
+	for _, server := range servers {
+		if len(filter) > 0 {
+			tags, err := l.resource.tags.ListServerTags(ctx, server.Identifier)
+			if err != nil {
+				diags.AddError(
+					"Error listing servers",
+					"Could not read tags of server "+server.Identifier+": "+err.Error(),
+				)
+				stream.Results = list.ListResultsStreamDiagnostics(diags)
+				return
+			}
+			if added, _ := diffServerTags(filter, tags); len(added) > 0 {
+				continue
+			}
+		}
+		matched = append(matched, server)
+	}
+
+	stream.Results = listresult.Stream(req, matched, func(server govpsie.VmData) list.ListResult {
+		return listresult.New(ctx, req, &l.resource, server.Hostname, serverIdentityModel{
+			Identifier: types.StringValue(server.Identifier),
+		})
+	})
+}
@@ -0,0 +1,62 @@
+package server_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/vpsie/govpsie"
+	"github.com/vpsie/terraform-provider-vpsie/internal/acctest"
+	"github.com/vpsie/terraform-provider-vpsie/internal/fakeapi"
+)
+
+func TestUnitServerListResource(t *testing.T) {
+	ctx := context.Background()
+	backend := fakeapi.New()
+	client := backend.Client()
+	for _, hostname := range []string{"web", "db"} {
+		if err := client.Server.CreateServer(ctx, &govpsie.CreateServerRequest{Hostname: hostname}); err != nil {
+			t.Fatalf("CreateServer: %v", err)
+		}
+	}
+	servers, _ := client.Server.List(ctx, nil)
+	if err := client.Server.AddTags(ctx, servers[0].Identifier, []string{"prod"}); err != nil {
+		t.Fatalf("AddTags: %v", err)
+	}
+	server, schemas := acctest.FakeProtoV6Server(t, backend, nil)
+
+	results, diags := acctest.ListResource(t, server, schemas, "vpsie_server", nil, false)
+	if len(diags) > 0 {
+		t.Fatalf("list: %s", diags[0].Detail)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected two servers, got %d", len(results))
+	}
+	for _, result := range results {
+		if result.Resource != nil {
+			t.Errorf("expected no resource data for %s", result.DisplayName)
+		}
+	}
+
+	results, diags = acctest.ListResource(t, server, schemas, "vpsie_server", map[string]tftypes.Value{
+		"tags": tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{
+			tftypes.NewValue(tftypes.String, "prod"),
+		}),
+	}, true)
+	if len(diags) > 0 {
+		t.Fatalf("list with resources: %s", diags[0].Detail)
+	}
+	if len(results) != 1 {
+		t.Fatalf("expected one tagged server, got %d", len(results))
+	}
+
+	var identifier, hostname string
+	_ = results[0].Identity["identifier"].As(&identifier)
+	_ = results[0].Resource["hostname"].As(&hostname)
+	if identifier != servers[0].Identifier {
+		t.Errorf("expected identity %s, got %s", servers[0].Identifier, identifier)
+	}
+	if hostname != servers[0].Hostname || results[0].DisplayName != hostname {
+		t.Errorf("expected resource data for %s, got hostname %q", servers[0].Hostname, hostname)
+	}
+}
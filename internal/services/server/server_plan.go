@@ -0,0 +1,106 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/vpsie/govpsie"
+)
+
+// ServerPlanAPI lists the resource plans a VM can run on and moves a VM to
+// another plan. govpsie only lists offers for load balancers, so these calls
+// are made with the client's request helpers directly:
+//
+//   - POST /apps/v2/vm/offers with {"dcIdentifier"} returns the plans of a
+//     data center in the same shape as the load balancer offers.
+//   - POST /apps/v2/vm/upgrade with {"vmIdentifier", "resourceIdentifier"}
+//     moves a VM to a plan, disk included.
+type ServerPlanAPI interface {
+	ListServerPlans(ctx context.Context, dcIdentifier string) ([]govpsie.LBOffers, error)
+	ChangeServerPlan(ctx context.Context, identifierId, resourceIdentifier string) error
+}
+
+type serverPlanClient struct {
+	client *govpsie.Client
+}
+
+var _ ServerPlanAPI = &serverPlanClient{}
+
+// newServerPlanClient returns the plan API for client. A server service that
+// handles plans itself, such as the fake API used in tests, is used directly.
+func newServerPlanClient(client *govpsie.Client) ServerPlanAPI {
+	if planAPI, ok := client.Server.(ServerPlanAPI); ok {
+		return planAPI
+	}
+
+	return &serverPlanClient{client: client}
+}
+
+func (c *serverPlanClient) ListServerPlans(ctx context.Context, dcIdentifier string) ([]govpsie.LBOffers, error) {
+	offerReq := struct {
+		DcIdentifier string `json:"dcIdentifier"`
+	}{
+		DcIdentifier: dcIdentifier,
+	}
+
+	req, err := c.client.NewRequest(ctx, http.MethodPost, "/apps/v2/vm/offers", &offerReq)
+	if err != nil {
+		return nil, err
+	}
+
+	root := new(govpsie.ListOffersRoot)
+	if err := c.client.Do(ctx, req, root); err != nil {
+		return nil, err
+	}
+
+	return root.Data, nil
+}
+
+func (c *serverPlanClient) ChangeServerPlan(ctx context.Context, identifierId, resourceIdentifier string) error {
+	upgradeReq := struct {
+		VmIdentifier       string `json:"vmIdentifier"`
+		ResourceIdentifier string `json:"resourceIdentifier"`
+	}{
+		VmIdentifier:       identifierId,
+		ResourceIdentifier: resourceIdentifier,
+	}
+
+	req, err := c.client.NewRequest(ctx, http.MethodPost, "/apps/v2/vm/upgrade", upgradeReq)
+	if err != nil {
+		return err
+	}
+
+	return c.client.Do(ctx, req, nil)
+}
+
+// findServerPlan returns the plan with the given identifier.
+func findServerPlan(plans []govpsie.LBOffers, resourceIdentifier string) (*govpsie.LBOffers, bool) {
+	for i := range plans {
+		if plans[i].Identifier == resourceIdentifier {
+			return &plans[i], true
+		}
+	}
+
+	return nil, false
+}
+
+// onServerPlan reports whether server has the CPU, RAM and disk of plan.
+func onServerPlan(server *govpsie.VmData, plan *govpsie.LBOffers) bool {
+	return server.Cpu == int64(plan.Cpu) && server.Ram == int64(plan.Ram) && server.Ssd == int64(plan.Ssd)
+}
+
+// checkPlanChange returns an error when a server with a disk of ssd GB
+// cannot be moved to plan. Plans can add CPU, RAM and disk or take CPU and
+// RAM away, but the API cannot shrink a disk.
+func checkPlanChange(ssd int64, plan *govpsie.LBOffers) error {
+	if int64(plan.Ssd) < ssd {
+		return fmt.Errorf(
+			"the server has a %d GB disk and plan %q only has %d GB. The VPSie API cannot shrink a disk; "+
+				"choose a plan with at least %d GB of disk, or replace the server",
+			ssd, plan.Identifier, plan.Ssd, ssd,
+		)
+	}
+
+	return nil
+}
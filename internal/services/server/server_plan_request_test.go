@@ -0,0 +1,48 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/vpsie/terraform-provider-vpsie/internal/apitest"
+)
+
+func TestUnitServerPlanClient_ListServerPlans(t *testing.T) {
+	api := apitest.New(t)
+	api.Handle(http.MethodPost, "/apps/v2/vm/offers", `{"error":false,"Data":[
+		{"cpu":2,"ram":4096,"ssd":80,"traffic":4,"price":"20.00","nickname":"Medium","identifier":"plan-medium","category":"Standard"}]}`)
+
+	plans, err := newServerPlanClient(api.Client()).ListServerPlans(context.Background(), "dc-1")
+	if err != nil {
+		t.Fatalf("ListServerPlans: %v", err)
+	}
+
+	requests := api.Requests(http.MethodPost, "/apps/v2/vm/offers")
+	if len(requests) != 1 {
+		t.Fatalf("expected one offers request, got %d", len(requests))
+	}
+	apitest.EqualJSON(t, requests[0].Body, `{"dcIdentifier":"dc-1"}`)
+
+	if len(plans) != 1 {
+		t.Fatalf("expected one plan, got %d", len(plans))
+	}
+	if plan := plans[0]; plan.Identifier != "plan-medium" || plan.Cpu != 2 || plan.Ram != 4096 || plan.Ssd != 80 {
+		t.Errorf("unexpected plan %+v", plan)
+	}
+}
+
+func TestUnitServerPlanClient_ChangeServerPlan(t *testing.T) {
+	api := apitest.New(t)
+	api.Handle(http.MethodPost, "/apps/v2/vm/upgrade", `{"error":false}`)
+
+	if err := newServerPlanClient(api.Client()).ChangeServerPlan(context.Background(), "vm-1", "plan-large"); err != nil {
+		t.Fatalf("ChangeServerPlan: %v", err)
+	}
+
+	requests := api.Requests(http.MethodPost, "/apps/v2/vm/upgrade")
+	if len(requests) != 1 {
+		t.Fatalf("expected one upgrade request, got %d", len(requests))
+	}
+	apitest.EqualJSON(t, requests[0].Body, `{"vmIdentifier":"vm-1","resourceIdentifier":"plan-large"}`)
+}
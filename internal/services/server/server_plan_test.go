@@ -0,0 +1,244 @@
+package server_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/vpsie/govpsie"
+	"github.com/vpsie/terraform-provider-vpsie/internal/acctest"
+	"github.com/vpsie/terraform-provider-vpsie/internal/fakeapi"
+)
+
+// testServerState creates a server on plan-medium with SSH key key-1 and
+// returns the state and identity a configuration using them would have after
+// apply.
+func testServerState(t *testing.T, backend *fakeapi.Backend, server tfprotov6.ProviderServer, schemas *tfprotov6.GetProviderSchemaResponse) (map[string]tftypes.Value, *tfprotov6.ResourceIdentityData) {
+	t.Helper()
+	ctx := context.Background()
+
+	client := backend.Client()
+	sshKey := "key-1"
+	err := client.Server.CreateServer(ctx, &govpsie.CreateServerRequest{
+		ResourceIdentifier: "plan-medium",
+		OsIdentifier:       "ubuntu-24.04",
+		DcIdentifier:       "dc-1",
+		Hostname:           "web",
+		ProjectID:          1,
+		SshKeyIdentifier:   &sshKey,
+	})
+	if err != nil {
+		t.Fatalf("CreateServer: %v", err)
+	}
+	servers, _ := client.Server.List(ctx, nil)
+
+	imported, diags := acctest.ImportResourceState(t, server, schemas, "vpsie_server", servers[0].Identifier, nil)
+	if len(diags) > 0 {
+		t.Fatalf("import: %s", diags[0].Detail)
+	}
+
+	state, identity := testServerRead(t, server, schemas, imported.State)
+	state["resource_identifier"] = tftypes.NewValue(tftypes.String, "plan-medium")
+	state["os_identifier"] = tftypes.NewValue(tftypes.String, "ubuntu-24.04")
+
+	return state, identity
+}
+
+// testServerRead refreshes the server in current.
+func testServerRead(t *testing.T, server tfprotov6.ProviderServer, schemas *tfprotov6.GetProviderSchemaResponse, current map[string]tftypes.Value) (map[string]tftypes.Value, *tfprotov6.ResourceIdentityData) {
+	t.Helper()
+	serverType := schemas.ResourceSchemas["vpsie_server"].ValueType()
+
+	resp, err := server.ReadResource(context.Background(), &tfprotov6.ReadResourceRequest{
+		TypeName:     "vpsie_server",
+		CurrentState: acctest.DynamicValue(t, serverType, tftypes.NewValue(serverType, current)),
+	})
+	if err != nil {
+		t.Fatalf("ReadResource: %v", err)
+	}
+	if len(resp.Diagnostics) > 0 {
+		t.Fatalf("ReadResource: %s", resp.Diagnostics[0].Detail)
+	}
+
+	value, err := resp.NewState.Unmarshal(serverType)
+	if err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	var state map[string]tftypes.Value
+	if err := value.As(&state); err != nil {
+		t.Fatalf("As: %v", err)
+	}
+
+	return state, resp.NewIdentity
+}
+
+// testServerPlan plans changing the configuration of the server in prior by
+// changes.
+func testServerPlan(t *testing.T, server tfprotov6.ProviderServer, schemas *tfprotov6.GetProviderSchemaResponse, prior map[string]tftypes.Value, identity *tfprotov6.ResourceIdentityData, changes map[string]tftypes.Value) (tftypes.Value, tftypes.Value, *tfprotov6.PlanResourceChangeResponse) {
+	t.Helper()
+	serverType := schemas.ResourceSchemas["vpsie_server"].ValueType()
+
+	configured := map[string]tftypes.Value{}
+	for _, name := range []string{"project_id", "resource_identifier", "os_identifier", "dc_identifier", "hostname", "sshkey_id"} {
+		configured[name] = prior[name]
+	}
+	proposed := map[string]tftypes.Value{}
+	for name, v := range prior {
+		proposed[name] = v
+	}
+	for name, v := range changes {
+		configured[name] = v
+		proposed[name] = v
+	}
+	config := acctest.ObjectValue(serverType, configured)
+
+	priorState := tftypes.NewValue(serverType, prior)
+	resp, err := server.PlanResourceChange(context.Background(), &tfprotov6.PlanResourceChangeRequest{
+		TypeName:         "vpsie_server",
+		PriorState:       acctest.DynamicValue(t, serverType, priorState),
+		ProposedNewState: acctest.DynamicValue(t, serverType, tftypes.NewValue(serverType, proposed)),
+		Config:           acctest.DynamicValue(t, serverType, config),
+		PriorIdentity:    identity,
+	})
+	if err != nil {
+		t.Fatalf("PlanResourceChange: %v", err)
+	}
+
+	return priorState, config, resp
+}
+
+// testServerApply applies plan to the server in priorState.
+func testServerApply(t *testing.T, server tfprotov6.ProviderServer, schemas *tfprotov6.GetProviderSchemaResponse, priorState, config tftypes.Value, plan *tfprotov6.PlanResourceChangeResponse) map[string]tftypes.Value {
+	t.Helper()
+	serverType := schemas.ResourceSchemas["vpsie_server"].ValueType()
+
+	resp, err := server.ApplyResourceChange(context.Background(), &tfprotov6.ApplyResourceChangeRequest{
+		TypeName:        "vpsie_server",
+		PriorState:      acctest.DynamicValue(t, serverType, priorState),
+		PlannedState:    plan.PlannedState,
+		Config:          acctest.DynamicValue(t, serverType, config),
+		PlannedIdentity: plan.PlannedIdentity,
+	})
+	if err != nil {
+		t.Fatalf("ApplyResourceChange: %v", err)
+	}
+	if len(resp.Diagnostics) > 0 {
+		t.Fatalf("apply: %s", resp.Diagnostics[0].Detail)
+	}
+
+	value, err := resp.NewState.Unmarshal(serverType)
+	if err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	var state map[string]tftypes.Value
+	if err := value.As(&state); err != nil {
+		t.Fatalf("As: %v", err)
+	}
+	return state
+}
+
+func TestUnitServerResource_ChangePlan(t *testing.T) {
+	ctx := context.Background()
+	backend := fakeapi.New()
+	server, schemas := acctest.FakeProtoV6Server(t, backend, nil)
+	serverType := schemas.ResourceSchemas["vpsie_server"].ValueType()
+	prior, identity := testServerState(t, backend, server, schemas)
+
+	priorState, config, plan := testServerPlan(t, server, schemas, prior, identity, map[string]tftypes.Value{
+		"resource_identifier": tftypes.NewValue(tftypes.String, "plan-large"),
+	})
+	if len(plan.Diagnostics) > 0 {
+		t.Fatalf("plan: %s", plan.Diagnostics[0].Detail)
+	}
+
+	planned, err := plan.PlannedState.Unmarshal(serverType)
+	if err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	var plannedAttrs map[string]tftypes.Value
+	_ = planned.As(&plannedAttrs)
+	for attr, want := range map[string]int64{"cpu": 4, "ram": 4096, "ssd": 80} {
+		if !plannedAttrs[attr].Equal(tftypes.NewValue(tftypes.Number, want)) {
+			t.Errorf("expected planned %s %d, got %s", attr, want, plannedAttrs[attr])
+		}
+	}
+
+	testServerApply(t, server, schemas, priorState, config, plan)
+
+	if n := backend.Calls("Server.ChangeServerPlan"); n != 1 {
+		t.Errorf("expected one plan change, got %d", n)
+	}
+	if n := backend.Calls("Server.ResizeServer"); n != 0 {
+		t.Errorf("expected no separate cpu/ram resize, got %d", n)
+	}
+
+	var identifier string
+	_ = prior["identifier"].As(&identifier)
+	vm, err := backend.Client().Server.GetServerByIdentifier(ctx, identifier)
+	if err != nil {
+		t.Fatalf("GetServerByIdentifier: %v", err)
+	}
+	if vm.Cpu != 4 || vm.Ram != 4096 || vm.Ssd != 80 {
+		t.Errorf("expected the server on plan-large, got %d CPU, %d MB RAM, %d GB disk", vm.Cpu, vm.Ram, vm.Ssd)
+	}
+}
+
+func TestUnitServerResource_PlanRejectsDiskShrink(t *testing.T) {
+	backend := fakeapi.New()
+	server, schemas := acctest.FakeProtoV6Server(t, backend, nil)
+	prior, identity := testServerState(t, backend, server, schemas)
+
+	_, _, plan := testServerPlan(t, server, schemas, prior, identity, map[string]tftypes.Value{
+		"resource_identifier": tftypes.NewValue(tftypes.String, "plan-small"),
+	})
+	if len(plan.Diagnostics) == 0 {
+		t.Fatal("expected a diagnostic for a plan with a smaller disk")
+	}
+	if plan.Diagnostics[0].Summary != "Cannot shrink server disk" {
+		t.Errorf("unexpected diagnostic: %s: %s", plan.Diagnostics[0].Summary, plan.Diagnostics[0].Detail)
+	}
+	if backend.Calls("Server.ChangeServerPlan") != 0 {
+		t.Error("expected no plan change at plan time")
+	}
+}
+
+func TestUnitServerResource_PlanRejectsUnknownPlan(t *testing.T) {
+	backend := fakeapi.New()
+	server, schemas := acctest.FakeProtoV6Server(t, backend, nil)
+	prior, identity := testServerState(t, backend, server, schemas)
+
+	_, _, plan := testServerPlan(t, server, schemas, prior, identity, map[string]tftypes.Value{
+		"resource_identifier": tftypes.NewValue(tftypes.String, "plan-huge"),
+	})
+	if len(plan.Diagnostics) == 0 || plan.Diagnostics[0].Summary != "Unknown server plan" {
+		t.Fatalf("expected an unknown plan diagnostic, got %v", plan.Diagnostics)
+	}
+}
+
+// TestUnitServerResource_ChangePlanWaitsForResize moves a server to another
+// plan on an API that keeps reporting the old size, with the server still
+// running, until it picks the upgrade up.
+func TestUnitServerResource_ChangePlanWaitsForResize(t *testing.T) {
+	backend := fakeapi.New()
+	server, schemas := acctest.FakeProtoV6Server(t, backend, nil)
+	prior, identity := testServerState(t, backend, server, schemas)
+	backend.QueuedPolls = 1
+
+	priorState, config, plan := testServerPlan(t, server, schemas, prior, identity, map[string]tftypes.Value{
+		"resource_identifier": tftypes.NewValue(tftypes.String, "plan-large"),
+	})
+	if len(plan.Diagnostics) > 0 {
+		t.Fatalf("plan: %s", plan.Diagnostics[0].Detail)
+	}
+	state := testServerApply(t, server, schemas, priorState, config, plan)
+
+	for attr, want := range map[string]int64{"cpu": 4, "ram": 4096, "ssd": 80} {
+		if !state[attr].Equal(tftypes.NewValue(tftypes.Number, want)) {
+			t.Errorf("expected %s %d after the upgrade, got %s", attr, want, state[attr])
+		}
+	}
+	if n := backend.Calls("Server.ResizeServer"); n != 0 {
+		t.Errorf("expected no cpu/ram resize after the plan change, got %d", n)
+	}
+}
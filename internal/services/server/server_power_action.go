@@ -0,0 +1,123 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/action/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	"github.com/hashicorp/terraform-plugin-framework/action/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/vpsie/terraform-provider-vpsie/internal/providerdata"
+	"github.com/vpsie/terraform-provider-vpsie/internal/transport"
+)
+
+var (
+	_ action.Action              = &serverPowerAction{}
+	_ action.ActionWithConfigure = &serverPowerAction{}
+)
+
+type serverPowerAction struct {
+	client ServerAPI
+}
+
+type serverPowerActionModel struct {
+	Identifier types.String   `tfsdk:"identifier"`
+	State      types.String   `tfsdk:"state"`
+	Timeouts   timeouts.Value `tfsdk:"timeouts"`
+}
+
+func NewServerPowerAction() action.Action {
+	return &serverPowerAction{}
+}
+
+func (a *serverPowerAction) Metadata(_ context.Context, req action.MetadataRequest, resp *action.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_server_power"
+}
+
+func (a *serverPowerAction) Schema(ctx context.Context, _ action.SchemaRequest, resp *action.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Starts or stops a VPSie server and waits until it reaches the requested state, without changing its configuration.",
+		Attributes: map[string]schema.Attribute{
+			"identifier": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "The identifier of the server.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"state": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "The power state to move the server to: `running` or `stopped`.",
+				Validators: []validator.String{
+					stringvalidator.OneOf(StateRunning, StateStopped),
+				},
+			},
+			"timeouts": timeouts.Attributes(ctx),
+		},
+	}
+}
+
+func (a *serverPowerAction) Configure(_ context.Context, req action.ConfigureRequest, resp *action.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*providerdata.Data)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Action Configure Type",
+			fmt.Sprintf("Expected *providerdata.Data, got %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	a.client = data.Client.Server
+}
+
+func (a *serverPowerAction) Invoke(ctx context.Context, req action.InvokeRequest, resp *action.InvokeResponse) {
+	var data serverPowerActionModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout, diags := data.Timeouts.Invoke(ctx, 10*time.Minute)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	identifier := data.Identifier.ValueString()
+	target := data.State.ValueString()
+
+	// Starting and stopping only move the server to a target state, so they
+	// are safe to retry.
+	var err error
+	if target == StateRunning {
+		err = a.client.StartServer(transport.AllowRetry(ctx), identifier)
+	} else {
+		err = a.client.StopServer(transport.AllowRetry(ctx), identifier)
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error changing server power state",
+			"couldn't move server "+identifier+" to "+target+", unexpected error: "+err.Error(),
+		)
+		return
+	}
+	resp.SendProgress(action.InvokeProgressEvent{Message: "Waiting for server " + identifier + " to be " + target})
+
+	_, err = waitForState(ctx, a.client, identifier, target, timeout)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error waiting for server power state",
+			"server "+identifier+" did not reach "+target+": "+err.Error(),
+		)
+		return
+	}
+	resp.SendProgress(action.InvokeProgressEvent{Message: "Server " + identifier + " is " + target})
+}
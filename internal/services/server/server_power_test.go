@@ -0,0 +1,60 @@
+package server_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/vpsie/terraform-provider-vpsie/internal/acctest"
+	"github.com/vpsie/terraform-provider-vpsie/internal/fakeapi"
+)
+
+func TestUnitServerResource_PowerState(t *testing.T) {
+	backend := fakeapi.New()
+	server, schemas := acctest.FakeProtoV6Server(t, backend, nil)
+	prior, identity := testServerState(t, backend, server, schemas)
+	backend.ProvisioningPolls = 1
+
+	stopped := tftypes.NewValue(tftypes.String, "stopped")
+	priorState, config, plan := testServerPlan(t, server, schemas, prior, identity, map[string]tftypes.Value{
+		"power_state": stopped,
+	})
+	if len(plan.Diagnostics) > 0 {
+		t.Fatalf("plan: %s", plan.Diagnostics[0].Detail)
+	}
+
+	state := testServerApply(t, server, schemas, priorState, config, plan)
+
+	if n := backend.Calls("Server.StopServer"); n != 1 {
+		t.Errorf("expected one stop, got %d", n)
+	}
+	for _, attr := range []string{"power_state", "state"} {
+		if !state[attr].Equal(stopped) {
+			t.Errorf("expected %s stopped, got %s", attr, state[attr])
+		}
+	}
+	if !state["power"].Equal(tftypes.NewValue(tftypes.Number, 0)) {
+		t.Errorf("expected power 0, got %s", state["power"])
+	}
+}
+
+func TestUnitServerResource_PowerStateDrift(t *testing.T) {
+	backend := fakeapi.New()
+	server, schemas := acctest.FakeProtoV6Server(t, backend, nil)
+	prior, _ := testServerState(t, backend, server, schemas)
+
+	if !prior["power_state"].Equal(tftypes.NewValue(tftypes.String, "running")) {
+		t.Fatalf("expected power_state running, got %s", prior["power_state"])
+	}
+
+	var identifier string
+	_ = prior["identifier"].As(&identifier)
+	if err := backend.Client().Server.StopServer(context.Background(), identifier); err != nil {
+		t.Fatalf("StopServer: %v", err)
+	}
+
+	state, _ := testServerRead(t, server, schemas, prior)
+	if !state["power_state"].Equal(tftypes.NewValue(tftypes.String, "stopped")) {
+		t.Errorf("expected refresh to report power_state stopped, got %s", state["power_state"])
+	}
+}
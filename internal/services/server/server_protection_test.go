@@ -0,0 +1,103 @@
+package server_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/vpsie/terraform-provider-vpsie/internal/acctest"
+	"github.com/vpsie/terraform-provider-vpsie/internal/fakeapi"
+)
+
+func TestUnitServerResource_DeletionProtection(t *testing.T) {
+	ctx := context.Background()
+	backend := fakeapi.New()
+	server, schemas := acctest.FakeProtoV6Server(t, backend, map[string]tftypes.Value{
+		"delete_password": tftypes.NewValue(tftypes.String, "provider-password"),
+		"delete_reason":   tftypes.NewValue(tftypes.String, "test"),
+	})
+	serverType := schemas.ResourceSchemas["vpsie_server"].ValueType()
+	prior, identity := testServerState(t, backend, server, schemas)
+
+	var identifier string
+	_ = prior["identifier"].As(&identifier)
+	if !prior["deletion_protection"].Equal(tftypes.NewValue(tftypes.Bool, false)) {
+		t.Fatalf("expected an unlocked server to read without protection, got %s", prior["deletion_protection"])
+	}
+
+	priorState, config, plan := testServerPlan(t, server, schemas, prior, identity, map[string]tftypes.Value{
+		"deletion_protection": tftypes.NewValue(tftypes.Bool, true),
+	})
+	if len(plan.Diagnostics) > 0 {
+		t.Fatalf("plan: %s", plan.Diagnostics[0].Detail)
+	}
+	planned, err := plan.PlannedState.Unmarshal(serverType)
+	if err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	var plannedAttrs map[string]tftypes.Value
+	_ = planned.As(&plannedAttrs)
+	if !plannedAttrs["is_locked"].Equal(tftypes.NewValue(tftypes.Number, 1)) {
+		t.Errorf("expected is_locked to be planned as 1, got %s", plannedAttrs["is_locked"])
+	}
+
+	state := testServerApply(t, server, schemas, priorState, config, plan)
+
+	if n := backend.Calls("Server.Lock"); n != 1 {
+		t.Errorf("expected one lock, got %d", n)
+	}
+	vm, err := backend.Client().Server.GetServerByIdentifier(ctx, identifier)
+	if err != nil {
+		t.Fatalf("GetServerByIdentifier: %v", err)
+	}
+	if vm.IsLocked != 1 {
+		t.Error("expected the server to be locked")
+	}
+
+	destroy := func(current map[string]tftypes.Value) *tfprotov6.ApplyResourceChangeResponse {
+		t.Helper()
+		noState := tftypes.NewValue(serverType, nil)
+		resp, err := server.ApplyResourceChange(ctx, &tfprotov6.ApplyResourceChangeRequest{
+			TypeName:     "vpsie_server",
+			PriorState:   acctest.DynamicValue(t, serverType, tftypes.NewValue(serverType, current)),
+			PlannedState: acctest.DynamicValue(t, serverType, noState),
+			Config:       acctest.DynamicValue(t, serverType, noState),
+		})
+		if err != nil {
+			t.Fatalf("ApplyResourceChange: %v", err)
+		}
+		return resp
+	}
+
+	resp := destroy(state)
+	if len(resp.Diagnostics) == 0 || resp.Diagnostics[0].Summary != "Deletion protection enabled" {
+		t.Fatalf("expected a deletion protection diagnostic, got %v", resp.Diagnostics)
+	}
+	if n := backend.Calls("Server.DeleteServer"); n != 0 {
+		t.Errorf("expected no delete call, got %d", n)
+	}
+
+	priorState, config, plan = testServerPlan(t, server, schemas, state, plan.PlannedIdentity, map[string]tftypes.Value{
+		"deletion_protection": tftypes.NewValue(tftypes.Bool, false),
+	})
+	if len(plan.Diagnostics) > 0 {
+		t.Fatalf("plan: %s", plan.Diagnostics[0].Detail)
+	}
+	state = testServerApply(t, server, schemas, priorState, config, plan)
+
+	if n := backend.Calls("Server.UnLock"); n != 1 {
+		t.Errorf("expected one unlock, got %d", n)
+	}
+	if !state["is_locked"].Equal(tftypes.NewValue(tftypes.Number, 0)) {
+		t.Errorf("expected is_locked 0, got %s", state["is_locked"])
+	}
+
+	resp = destroy(state)
+	if len(resp.Diagnostics) > 0 {
+		t.Fatalf("destroy: %s", resp.Diagnostics[0].Detail)
+	}
+	if _, err := backend.Client().Server.GetServerByIdentifier(ctx, identifier); err == nil {
+		t.Error("expected the server to be deleted")
+	}
+}
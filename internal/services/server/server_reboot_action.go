@@ -0,0 +1,124 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/action/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	"github.com/hashicorp/terraform-plugin-framework/action/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/vpsie/govpsie"
+	"github.com/vpsie/terraform-provider-vpsie/internal/providerdata"
+)
+
+// rebootMinDowntime is how long after a reboot request a running server is
+// taken to have rebooted. A reboot can finish between two polls, so the
+// server may never be seen in another state.
+const rebootMinDowntime = 30 * time.Second
+
+var (
+	_ action.Action              = &serverRebootAction{}
+	_ action.ActionWithConfigure = &serverRebootAction{}
+)
+
+type serverRebootAction struct {
+	client      ServerAPI
+	minDowntime time.Duration
+}
+
+type serverRebootActionModel struct {
+	Identifier types.String   `tfsdk:"identifier"`
+	Timeouts   timeouts.Value `tfsdk:"timeouts"`
+}
+
+func NewServerRebootAction() action.Action {
+	return &serverRebootAction{minDowntime: rebootMinDowntime}
+}
+
+func (a *serverRebootAction) Metadata(_ context.Context, req action.MetadataRequest, resp *action.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_server_reboot"
+}
+
+func (a *serverRebootAction) Schema(ctx context.Context, _ action.SchemaRequest, resp *action.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Reboots a VPSie server and waits until it is running again, without changing its configuration.",
+		Attributes: map[string]schema.Attribute{
+			"identifier": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "The identifier of the server to reboot.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"timeouts": timeouts.Attributes(ctx),
+		},
+	}
+}
+
+func (a *serverRebootAction) Configure(_ context.Context, req action.ConfigureRequest, resp *action.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*providerdata.Data)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Action Configure Type",
+			fmt.Sprintf("Expected *providerdata.Data, got %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	a.client = data.Client.Server
+}
+
+func (a *serverRebootAction) Invoke(ctx context.Context, req action.InvokeRequest, resp *action.InvokeResponse) {
+	var data serverRebootActionModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout, diags := data.Timeouts.Invoke(ctx, 10*time.Minute)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	identifier := data.Identifier.ValueString()
+	requested := time.Now()
+
+	err := a.client.RestartServer(ctx, identifier)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error rebooting server",
+			"couldn't reboot server "+identifier+", unexpected error: "+err.Error(),
+		)
+		return
+	}
+	resp.SendProgress(action.InvokeProgressEvent{Message: "Rebooting server " + identifier})
+
+	err = a.waitForReboot(ctx, identifier, timeout, requested)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error waiting for server to reboot",
+			"server "+identifier+" did not come back up: "+err.Error(),
+		)
+		return
+	}
+	resp.SendProgress(action.InvokeProgressEvent{Message: "Server " + identifier + " is running"})
+}
+
+// waitForReboot waits until the server with the given identifier runs again
+// after a reboot requested at requested. The server counts as rebooted once
+// it has reported another state, or once minDowntime has passed.
+func (a *serverRebootAction) waitForReboot(ctx context.Context, identifier string, timeout time.Duration, requested time.Time) error {
+	_, err := waitForRestart(ctx, a.client, identifier, timeout, func(*govpsie.VmData) bool {
+		return time.Since(requested) >= a.minDowntime
+	})
+	return err
+}
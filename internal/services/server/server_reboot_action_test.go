@@ -0,0 +1,35 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/vpsie/govpsie"
+	"github.com/vpsie/terraform-provider-vpsie/internal/waiter"
+)
+
+// TestUnitServerRebootAction_RebootBetweenPolls reboots a server that is
+// running on every poll, as when the reboot finishes between two of them.
+func TestUnitServerRebootAction_RebootBetweenPolls(t *testing.T) {
+	calls := 0
+	client := &mockServerAPI{
+		GetServerByIdentifierFn: func(ctx context.Context, identifierId string) (*govpsie.VmData, error) {
+			calls++
+			return &govpsie.VmData{Identifier: identifierId, State: StateRunning}, nil
+		},
+	}
+	a := &serverRebootAction{client: client, minDowntime: time.Minute}
+
+	if err := a.waitForReboot(context.Background(), "vm-1", time.Second, time.Now().Add(-time.Minute)); err != nil {
+		t.Fatalf("expected the reboot to count once the downtime has passed, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected one read, got %d", calls)
+	}
+
+	err := a.waitForReboot(context.Background(), "vm-1", 10*time.Millisecond, time.Now())
+	if !waiter.IsTimeout(err) {
+		t.Fatalf("expected a running server not to count before the downtime has passed, got %v", err)
+	}
+}
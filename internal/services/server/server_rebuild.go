@@ -0,0 +1,50 @@
+package server
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/vpsie/govpsie"
+)
+
+// ServerRebuildAPI reinstalls a VM with another image, keeping its
+// identifier, size and IP addresses. govpsie has no call for it, so it is
+// made with the client's request helpers directly, as POST
+// /apps/v2/vm/rebuild with {"vmIdentifier", "osIdentifier"}.
+type ServerRebuildAPI interface {
+	RebuildServer(ctx context.Context, identifierId, osIdentifier string) error
+}
+
+type serverRebuildClient struct {
+	client *govpsie.Client
+}
+
+var _ ServerRebuildAPI = &serverRebuildClient{}
+
+// newServerRebuildClient returns the rebuild API for client. A server service
+// that handles rebuilds itself, such as the fake API used in tests, is used
+// directly.
+func newServerRebuildClient(client *govpsie.Client) ServerRebuildAPI {
+	if rebuildAPI, ok := client.Server.(ServerRebuildAPI); ok {
+		return rebuildAPI
+	}
+
+	return &serverRebuildClient{client: client}
+}
+
+func (c *serverRebuildClient) RebuildServer(ctx context.Context, identifierId, osIdentifier string) error {
+	rebuildReq := struct {
+		VmIdentifier string `json:"vmIdentifier"`
+		OsIdentifier string `json:"osIdentifier"`
+	}{
+		VmIdentifier: identifierId,
+		OsIdentifier: osIdentifier,
+	}
+
+	req, err := c.client.NewRequest(ctx, http.MethodPost, "/apps/v2/vm/rebuild", rebuildReq)
+	if err != nil {
+		return err
+	}
+
+	return c.client.Do(ctx, req, nil)
+}
@@ -0,0 +1,24 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/vpsie/terraform-provider-vpsie/internal/apitest"
+)
+
+func TestUnitServerRebuildClient_RebuildServer(t *testing.T) {
+	api := apitest.New(t)
+	api.Handle(http.MethodPost, "/apps/v2/vm/rebuild", `{"error":false}`)
+
+	if err := newServerRebuildClient(api.Client()).RebuildServer(context.Background(), "vm-1", "debian-12"); err != nil {
+		t.Fatalf("RebuildServer: %v", err)
+	}
+
+	requests := api.Requests(http.MethodPost, "/apps/v2/vm/rebuild")
+	if len(requests) != 1 {
+		t.Fatalf("expected one rebuild request, got %d", len(requests))
+	}
+	apitest.EqualJSON(t, requests[0].Body, `{"vmIdentifier":"vm-1","osIdentifier":"debian-12"}`)
+}
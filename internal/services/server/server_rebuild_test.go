@@ -0,0 +1,174 @@
+package server_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/vpsie/terraform-provider-vpsie/internal/acctest"
+	"github.com/vpsie/terraform-provider-vpsie/internal/fakeapi"
+)
+
+func TestUnitServerResource_RebuildOnOsChange(t *testing.T) {
+	backend := fakeapi.New()
+	server, schemas := acctest.FakeProtoV6Server(t, backend, nil)
+	prior, identity := testServerState(t, backend, server, schemas)
+	backend.ProvisioningPolls = 1
+
+	priorState, config, plan := testServerPlan(t, server, schemas, prior, identity, map[string]tftypes.Value{
+		"os_identifier":        tftypes.NewValue(tftypes.String, "debian-12"),
+		"rebuild_on_os_change": tftypes.NewValue(tftypes.Bool, true),
+	})
+	if len(plan.Diagnostics) > 0 {
+		t.Fatalf("plan: %s", plan.Diagnostics[0].Detail)
+	}
+	if len(plan.RequiresReplace) > 0 {
+		t.Fatalf("expected an in-place update, got replacement for %v", plan.RequiresReplace)
+	}
+
+	state := testServerApply(t, server, schemas, priorState, config, plan)
+
+	if n := backend.Calls("Server.RebuildServer"); n != 1 {
+		t.Errorf("expected one rebuild, got %d", n)
+	}
+	for _, attr := range []string{"identifier", "default_ip", "sshkey_id"} {
+		if !state[attr].Equal(prior[attr]) {
+			t.Errorf("expected %s to stay %s, got %s", attr, prior[attr], state[attr])
+		}
+	}
+	if !state["os_identifier"].Equal(tftypes.NewValue(tftypes.String, "debian-12")) {
+		t.Errorf("expected os_identifier debian-12, got %s", state["os_identifier"])
+	}
+
+	var identifier string
+	_ = prior["identifier"].As(&identifier)
+	vm, err := backend.Client().Server.GetServerByIdentifier(context.Background(), identifier)
+	if err != nil {
+		t.Fatalf("GetServerByIdentifier: %v", err)
+	}
+	if vm.FullName != "debian-12" {
+		t.Errorf("expected the server to run debian-12, got %q", vm.FullName)
+	}
+	if vm.SshKeyID == nil || *vm.SshKeyID != "key-1" {
+		t.Errorf("expected SSH key key-1 to be applied again, got %v", vm.SshKeyID)
+	}
+}
+
+// TestUnitServerResource_RebuildWaitsForReinstall rebuilds a server on an
+// API that keeps reporting the old image, with the server still running,
+// until it picks the rebuild up.
+func TestUnitServerResource_RebuildWaitsForReinstall(t *testing.T) {
+	backend := fakeapi.New()
+	server, schemas := acctest.FakeProtoV6Server(t, backend, nil)
+	prior, identity := testServerState(t, backend, server, schemas)
+	backend.QueuedPolls = 1
+
+	priorState, config, plan := testServerPlan(t, server, schemas, prior, identity, map[string]tftypes.Value{
+		"os_identifier":        tftypes.NewValue(tftypes.String, "debian-12"),
+		"rebuild_on_os_change": tftypes.NewValue(tftypes.Bool, true),
+	})
+	if len(plan.Diagnostics) > 0 {
+		t.Fatalf("plan: %s", plan.Diagnostics[0].Detail)
+	}
+	state := testServerApply(t, server, schemas, priorState, config, plan)
+
+	var identifier string
+	_ = prior["identifier"].As(&identifier)
+	vm, err := backend.Client().Server.GetServerByIdentifier(context.Background(), identifier)
+	if err != nil {
+		t.Fatalf("GetServerByIdentifier: %v", err)
+	}
+	if !state["boximage_id"].Equal(tftypes.NewValue(tftypes.Number, vm.BoxImageID)) {
+		t.Errorf("expected boximage_id %d of the new image, got %s", vm.BoxImageID, state["boximage_id"])
+	}
+	if n := backend.Calls("Server.AddSsh"); n != 1 {
+		t.Errorf("expected the SSH key to be applied once, got %d", n)
+	}
+}
+
+// TestUnitServerResource_RebuildSavesStateBeforeKeys fails to apply the SSH
+// key again after a rebuild and checks that the next apply adds the key
+// without reinstalling the server a second time.
+func TestUnitServerResource_RebuildSavesStateBeforeKeys(t *testing.T) {
+	backend := fakeapi.New()
+	server, schemas := acctest.FakeProtoV6Server(t, backend, nil)
+	serverType := schemas.ResourceSchemas["vpsie_server"].ValueType()
+	prior, identity := testServerState(t, backend, server, schemas)
+
+	changes := map[string]tftypes.Value{
+		"os_identifier":        tftypes.NewValue(tftypes.String, "debian-12"),
+		"rebuild_on_os_change": tftypes.NewValue(tftypes.Bool, true),
+	}
+	priorState, config, plan := testServerPlan(t, server, schemas, prior, identity, changes)
+	if len(plan.Diagnostics) > 0 {
+		t.Fatalf("plan: %s", plan.Diagnostics[0].Detail)
+	}
+
+	backend.FailNext("Server.AddSsh", fakeapi.Error(http.StatusInternalServerError, "ssh key service unavailable"))
+	resp, err := server.ApplyResourceChange(context.Background(), &tfprotov6.ApplyResourceChangeRequest{
+		TypeName:        "vpsie_server",
+		PriorState:      acctest.DynamicValue(t, serverType, priorState),
+		PlannedState:    plan.PlannedState,
+		Config:          acctest.DynamicValue(t, serverType, config),
+		PlannedIdentity: plan.PlannedIdentity,
+	})
+	if err != nil {
+		t.Fatalf("ApplyResourceChange: %v", err)
+	}
+	if len(resp.Diagnostics) == 0 {
+		t.Fatal("expected the failing SSH key to fail the apply")
+	}
+
+	value, err := resp.NewState.Unmarshal(serverType)
+	if err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	var partial map[string]tftypes.Value
+	if err := value.As(&partial); err != nil {
+		t.Fatalf("As: %v", err)
+	}
+	if !partial["os_identifier"].Equal(changes["os_identifier"]) {
+		t.Fatalf("expected the rebuild to be saved, got os_identifier %s", partial["os_identifier"])
+	}
+	if !partial["sshkey_id"].IsNull() {
+		t.Errorf("expected sshkey_id to be cleared until the key is applied again, got %s", partial["sshkey_id"])
+	}
+
+	// The configuration still sets the key the partial state lost.
+	changes["sshkey_id"] = prior["sshkey_id"]
+	priorState, config, plan = testServerPlan(t, server, schemas, partial, resp.NewIdentity, changes)
+	if len(plan.Diagnostics) > 0 {
+		t.Fatalf("plan: %s", plan.Diagnostics[0].Detail)
+	}
+	state := testServerApply(t, server, schemas, priorState, config, plan)
+
+	if n := backend.Calls("Server.RebuildServer"); n != 1 {
+		t.Errorf("expected one rebuild across both applies, got %d", n)
+	}
+	if !state["sshkey_id"].Equal(prior["sshkey_id"]) {
+		t.Errorf("expected sshkey_id %s to be applied again, got %s", prior["sshkey_id"], state["sshkey_id"])
+	}
+}
+
+func TestUnitServerResource_OsChangeRequiresReplace(t *testing.T) {
+	backend := fakeapi.New()
+	server, schemas := acctest.FakeProtoV6Server(t, backend, nil)
+	prior, identity := testServerState(t, backend, server, schemas)
+
+	_, _, plan := testServerPlan(t, server, schemas, prior, identity, map[string]tftypes.Value{
+		"os_identifier": tftypes.NewValue(tftypes.String, "debian-12"),
+	})
+	if len(plan.Diagnostics) > 0 {
+		t.Fatalf("plan: %s", plan.Diagnostics[0].Detail)
+	}
+
+	want := tftypes.NewAttributePath().WithAttributeName("os_identifier")
+	for _, p := range plan.RequiresReplace {
+		if p.Equal(want) {
+			return
+		}
+	}
+	t.Errorf("expected os_identifier to force replacement, got %v", plan.RequiresReplace)
+}
@@ -2,33 +2,57 @@ package server
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/vpsie/govpsie"
+	"github.com/vpsie/terraform-provider-vpsie/internal/apierror"
+	"github.com/vpsie/terraform-provider-vpsie/internal/deletion"
+	"github.com/vpsie/terraform-provider-vpsie/internal/providerdata"
+	"github.com/vpsie/terraform-provider-vpsie/internal/tags"
+	"github.com/vpsie/terraform-provider-vpsie/internal/transport"
+	"github.com/vpsie/terraform-provider-vpsie/internal/waiter"
 )
 
 var (
 	_ resource.Resource                = &serverResource{}
 	_ resource.ResourceWithConfigure   = &serverResource{}
 	_ resource.ResourceWithImportState = &serverResource{}
+	_ resource.ResourceWithIdentity    = &serverResource{}
+	_ resource.ResourceWithModifyPlan  = &serverResource{}
 )
 
 type serverResource struct {
-	client ServerAPI
+	client      ServerAPI
+	tags        ServerTagsAPI
+	plans       ServerPlanAPI
+	rebuilds    ServerRebuildAPI
+	sshKeys     ServerSshKeysAPI
+	scripts     ScriptAPI
+	defaultTags []string
+	deletion    deletion.Settings
 }
 
 type serverResourceModel struct {
@@ -59,6 +83,7 @@ type serverResourceModel struct {
 	IsActive            types.Int64  `tfsdk:"is_active"`
 	IsDeleted           types.Int64  `tfsdk:"is_deleted"`
 	Power               types.Int64  `tfsdk:"power"`
+	PowerState          types.String `tfsdk:"power_state"`
 	ProjectID           types.Int64  `tfsdk:"project_id"`
 	IsCustom            types.Int64  `tfsdk:"is_custom"`
 	NrAddedIps          types.Int64  `tfsdk:"nr_added_ips"`
@@ -68,6 +93,9 @@ type serverResourceModel struct {
 	LastLicensePay      types.String `tfsdk:"last_license_pay"`
 	ScriptID            types.String `tfsdk:"script_id"`
 	SshKeyID            types.String `tfsdk:"sshkey_id"`
+	SshKeyIDs           types.Set    `tfsdk:"ssh_key_ids"`
+	UserData            types.String `tfsdk:"user_data"`
+	UserDataScriptID    types.String `tfsdk:"user_data_script_id"`
 	IsLocked            types.Int64  `tfsdk:"is_locked"`
 	IsWorkWithNew       types.Int64  `tfsdk:"is_work_with_new_version"`
 	IsSuspended         types.Int64  `tfsdk:"is_suspended"`
@@ -100,6 +128,8 @@ type serverResourceModel struct {
 	IsSataAvailable     types.Int64  `tfsdk:"is_sata_available"`
 	IsSsdAvailable      types.Int64  `tfsdk:"is_ssd_available"`
 	PublicIp            types.String `tfsdk:"public_ip"`
+	Tags                types.Set    `tfsdk:"tags"`
+	TagsAll             types.Set    `tfsdk:"tags_all"`
 
 	ResourceIdentifier types.String `tfsdk:"resource_identifier"`
 	OsIdentifier       types.String `tfsdk:"os_identifier"`
@@ -112,6 +142,14 @@ type serverResourceModel struct {
 	DeleteReason  types.String   `tfsdk:"delete_reason"`
 	DeleteNote    types.String   `tfsdk:"delete_note"`
 	Timeouts      timeouts.Value `tfsdk:"timeouts"`
+
+	StoreInitialPassword types.Bool `tfsdk:"store_initial_password"`
+	RebuildOnOsChange    types.Bool `tfsdk:"rebuild_on_os_change"`
+	DeletionProtection   types.Bool `tfsdk:"deletion_protection"`
+}
+
+type serverIdentityModel struct {
+	Identifier types.String `tfsdk:"identifier"`
 }
 
 func NewServerResource() resource.Resource {
@@ -184,7 +222,7 @@ func (s *serverResource) Schema(ctx context.Context, _ resource.SchemaRequest, r
 			},
 			"resource_identifier": schema.StringAttribute{
 				Required:            true,
-				MarkdownDescription: "The identifier of the resource plan (box size) for the server.",
+				MarkdownDescription: "The identifier of the resource plan (box size) for the server. Changing it resizes the server in place, including its disk, and waits until it is running again. Plans with a smaller disk than the server's are rejected at plan time, because disks cannot be shrunk.",
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.UseStateForUnknown(),
 				},
@@ -194,9 +232,24 @@ func (s *serverResource) Schema(ctx context.Context, _ resource.SchemaRequest, r
 			},
 			"os_identifier": schema.StringAttribute{
 				Required:            true,
-				MarkdownDescription: "The identifier of the operating system image for the server.",
+				MarkdownDescription: "The identifier of the operating system image for the server. Changing it forces a new server to be created, unless `rebuild_on_os_change` is set.",
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplaceIf(
+						func(ctx context.Context, req planmodifier.StringRequest, resp *stringplanmodifier.RequiresReplaceIfFuncResponse) {
+							// An imported server has no os_identifier in state
+							// and adopts the configured one.
+							if req.StateValue.IsNull() {
+								return
+							}
+
+							var rebuild types.Bool
+							resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("rebuild_on_os_change"), &rebuild)...)
+							resp.RequiresReplace = !rebuild.ValueBool()
+						},
+						"Changing the image forces a new server to be created, unless rebuild_on_os_change is set.",
+						"Changing the image forces a new server to be created, unless `rebuild_on_os_change` is set.",
+					),
 				},
 				Validators: []validator.String{
 					stringvalidator.LengthAtLeast(1),
@@ -229,6 +282,21 @@ func (s *serverResource) Schema(ctx context.Context, _ resource.SchemaRequest, r
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
+			"tags": schema.SetAttribute{
+				Optional:            true,
+				Computed:            true,
+				ElementType:         types.StringType,
+				Default:             setdefault.StaticValue(types.SetValueMust(types.StringType, []attr.Value{})),
+				MarkdownDescription: "The tags assigned to the server. Tags not listed here or in the provider's `default_tags` are removed from the server on update.",
+				Validators: []validator.Set{
+					setvalidator.ValueStringsAre(stringvalidator.LengthAtLeast(1)),
+				},
+			},
+			"tags_all": schema.SetAttribute{
+				Computed:            true,
+				ElementType:         types.StringType,
+				MarkdownDescription: "All tags assigned to the server, including those inherited from the provider's `default_tags`.",
+			},
 			"script_id": schema.StringAttribute{
 				Optional:            true,
 				MarkdownDescription: "The identifier of a startup script to run on the server.",
@@ -238,7 +306,34 @@ func (s *serverResource) Schema(ctx context.Context, _ resource.SchemaRequest, r
 			},
 			"sshkey_id": schema.StringAttribute{
 				Optional:            true,
-				MarkdownDescription: "The identifier of an SSH key to add to the server.",
+				MarkdownDescription: "The identifier of an SSH key to add to the server. Use `ssh_key_ids` to manage several keys.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"ssh_key_ids": schema.SetAttribute{
+				Optional:            true,
+				ElementType:         types.StringType,
+				MarkdownDescription: "The identifiers of the SSH keys installed on the server. Keys not listed here are removed from the server on update. Conflicts with `sshkey_id`.",
+				Validators: []validator.Set{
+					setvalidator.ValueStringsAre(stringvalidator.LengthAtLeast(1)),
+					setvalidator.ConflictsWith(path.MatchRoot("sshkey_id")),
+				},
+			},
+			"user_data": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Cloud-init user data for the server. It is uploaded as a script that the provider manages, runs when the server first boots and is deleted with the server. Changing it forces a new server to be created. Conflicts with `script_id`.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+					stringvalidator.ConflictsWith(path.MatchRoot("script_id")),
+				},
+			},
+			"user_data_script_id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The identifier of the script holding `user_data`.",
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.UseStateForUnknown(),
 				},
@@ -333,7 +428,7 @@ func (s *serverResource) Schema(ctx context.Context, _ resource.SchemaRequest, r
 			},
 			"ssd": schema.Int64Attribute{
 				Computed:            true,
-				MarkdownDescription: "The SSD storage size in GB allocated to the server.",
+				MarkdownDescription: "The SSD storage size in GB allocated to the server. It follows `resource_identifier`.",
 				PlanModifiers: []planmodifier.Int64{
 					int64planmodifier.UseStateForUnknown(),
 				},
@@ -356,11 +451,23 @@ func (s *serverResource) Schema(ctx context.Context, _ resource.SchemaRequest, r
 			"initial_password": schema.StringAttribute{
 				Computed:            true,
 				Sensitive:           true,
-				MarkdownDescription: "The initial root password for the server.",
+				MarkdownDescription: "The initial root password for the server. Null when `store_initial_password` is `false`.",
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
+			"store_initial_password": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+				MarkdownDescription: "Whether to keep the initial root password in `initial_password`, and so in state. Defaults to `false`.",
+			},
+			"rebuild_on_os_change": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+				MarkdownDescription: "Whether a change of `os_identifier` reinstalls the server in place instead of replacing it. The server keeps its identifier and IP addresses, `sshkey_id` and `script_id` are applied again, and all data on its disk is lost. Defaults to `false`.",
+			},
 			"created_on": schema.StringAttribute{
 				Computed:            true,
 				MarkdownDescription: "The timestamp when the server was created.",
@@ -399,11 +506,22 @@ func (s *serverResource) Schema(ctx context.Context, _ resource.SchemaRequest, r
 			},
 			"power": schema.Int64Attribute{
 				Computed:            true,
-				MarkdownDescription: "The power state of the server (0 = off, 1 = on).",
+				MarkdownDescription: "The power state of the server (0 = off, 1 = on). Use `power_state` to start or stop the server.",
 				PlanModifiers: []planmodifier.Int64{
 					int64planmodifier.UseStateForUnknown(),
 				},
 			},
+			"power_state": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Whether the server should be `running` or `stopped`. Create and update start or stop the server and wait until it reaches this state. When it is not set, the server's current state is reported without being changed.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				Validators: []validator.String{
+					stringvalidator.OneOf(StateRunning, StateStopped),
+				},
+			},
 			"is_custom": schema.Int64Attribute{
 				Computed:            true,
 				MarkdownDescription: "Whether the server uses a custom configuration.",
@@ -450,7 +568,7 @@ func (s *serverResource) Schema(ctx context.Context, _ resource.SchemaRequest, r
 			},
 			"is_locked": schema.Int64Attribute{
 				Computed:            true,
-				MarkdownDescription: "Whether the server is locked from modifications.",
+				MarkdownDescription: "Whether the server is locked from modifications. Follows `deletion_protection`.",
 				PlanModifiers: []planmodifier.Int64{
 					int64planmodifier.UseStateForUnknown(),
 				},
@@ -671,7 +789,8 @@ func (s *serverResource) Schema(ctx context.Context, _ resource.SchemaRequest, r
 			},
 			"password": schema.StringAttribute{
 				Optional:            true,
-				MarkdownDescription: "The password used for server deletion verification.",
+				MarkdownDescription: "The password used for server deletion verification. It is kept in state; prefer the provider's `delete_password`, which is not.",
+				DeprecationMessage:  "The password is stored in state in plain text. Set delete_password on the provider, or the VPSIE_DELETE_PASSWORD environment variable, instead.",
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.UseStateForUnknown(),
 				},
@@ -679,42 +798,221 @@ func (s *serverResource) Schema(ctx context.Context, _ resource.SchemaRequest, r
 			},
 			"delete_reason": schema.StringAttribute{
 				Optional:            true,
-				MarkdownDescription: "The reason for deleting the server.",
+				MarkdownDescription: "The reason for deleting the server. Defaults to the provider's `delete_reason`.",
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
 			"delete_note": schema.StringAttribute{
 				Optional:            true,
-				MarkdownDescription: "An optional note to include when deleting the server.",
+				MarkdownDescription: "An optional note to include when deleting the server. Defaults to the provider's `delete_note`.",
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
+			"deletion_protection": serverDeletionProtectionAttribute(),
 
 			"timeouts": timeouts.Attributes(ctx, timeouts.Opts{
 				Create: true,
+				Update: true,
 			}),
 		},
 	}
 }
 
+func (s *serverResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"identifier": identityschema.StringAttribute{
+				RequiredForImport: true,
+				Description:       "The unique identifier of the server.",
+			},
+		},
+	}
+}
+
 func (s *serverResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
 		return
 	}
 
-	client, ok := req.ProviderData.(*govpsie.Client)
+	data, ok := req.ProviderData.(*providerdata.Data)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configuration Type",
-			fmt.Sprintf("Expected *govpsie.Client, got %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *providerdata.Data, got %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	s.client = client.Server
+	s.client = data.Client.Server
+	s.tags = newServerTagsClient(data.Client)
+	s.plans = newServerPlanClient(data.Client)
+	s.rebuilds = newServerRebuildClient(data.Client)
+	s.sshKeys = newServerSshKeysClient(data.Client)
+	s.scripts = data.Client.Scripts
+	s.defaultTags = data.DefaultTags
+	s.deletion = data.Deletion
+}
+
+// ModifyPlan computes tags_all from the planned tags and the provider's
+// default tags, plans the size of a server that changes resource plan, the
+// image of a server that is rebuilt, the state of a server that is started
+// or stopped and the lock of a server whose deletion protection changes.
+func (s *serverResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var planTags types.Set
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("tags"), &planTags)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tagsAll, diags := tags.MergeSet(ctx, planTags, s.defaultTags)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("tags_all"), tagsAll)...)
+
+	var storeInitialPassword types.Bool
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("store_initial_password"), &storeInitialPassword)...)
+	if !storeInitialPassword.IsUnknown() && !storeInitialPassword.IsNull() && !storeInitialPassword.ValueBool() {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("initial_password"), types.StringNull())...)
+	}
+
+	if req.State.Raw.IsNull() {
+		return
+	}
+
+	resp.Diagnostics.Append(s.modifyPlanResize(ctx, req, resp)...)
+	resp.Diagnostics.Append(s.modifyPlanRebuild(ctx, req, resp)...)
+	resp.Diagnostics.Append(s.modifyPlanPower(ctx, req, resp)...)
+	resp.Diagnostics.Append(s.modifyPlanProtection(ctx, req, resp)...)
+}
+
+// modifyPlanRebuild marks the image and root password of a server that is
+// rebuilt in place as unknown, since the reinstall changes both.
+func (s *serverResource) modifyPlanRebuild(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	var stateOs, planOs types.String
+	diags.Append(req.State.GetAttribute(ctx, path.Root("os_identifier"), &stateOs)...)
+	diags.Append(req.Plan.GetAttribute(ctx, path.Root("os_identifier"), &planOs)...)
+	if diags.HasError() || stateOs.IsNull() || stateOs.Equal(planOs) {
+		return diags
+	}
+
+	diags.Append(resp.Plan.SetAttribute(ctx, path.Root("boximage_id"), types.Int64Unknown())...)
+
+	var storeInitialPassword types.Bool
+	diags.Append(req.Plan.GetAttribute(ctx, path.Root("store_initial_password"), &storeInitialPassword)...)
+	if storeInitialPassword.IsUnknown() || storeInitialPassword.ValueBool() {
+		diags.Append(resp.Plan.SetAttribute(ctx, path.Root("initial_password"), types.StringUnknown())...)
+	}
+
+	return diags
+}
+
+// modifyPlanResize plans the CPU, RAM and disk of a server that moves to
+// another resource plan, and rejects plan changes the API cannot make.
+// Otherwise cpu and ram keep their current values unless they are set.
+func (s *serverResource) modifyPlanResize(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	var state, plan, config serverResourceModel
+	diags.Append(req.State.Get(ctx, &state)...)
+	diags.Append(req.Plan.Get(ctx, &plan)...)
+	diags.Append(req.Config.Get(ctx, &config)...)
+	if diags.HasError() {
+		return diags
+	}
+
+	cpu, ram, ssd := state.Cpu, state.Ram, state.Ssd
+	switch {
+	case plan.ResourceIdentifier.IsUnknown() || plan.DcIdentifier.IsUnknown():
+		cpu, ram, ssd = types.Int64Unknown(), types.Int64Unknown(), types.Int64Unknown()
+		diags.Append(resp.Plan.SetAttribute(ctx, path.Root("boxsize_id"), types.Int64Unknown())...)
+	case !plan.ResourceIdentifier.Equal(state.ResourceIdentifier):
+		resourceIdentifier := plan.ResourceIdentifier.ValueString()
+		dcIdentifier := plan.DcIdentifier.ValueString()
+
+		plans, err := s.plans.ListServerPlans(ctx, dcIdentifier)
+		if err != nil {
+			diags.AddError(
+				"Error reading server plans",
+				"couldn't list the resource plans of data center "+dcIdentifier+": "+err.Error(),
+			)
+			return diags
+		}
+
+		offer, ok := findServerPlan(plans, resourceIdentifier)
+		if !ok {
+			diags.AddAttributeError(
+				path.Root("resource_identifier"),
+				"Unknown server plan",
+				fmt.Sprintf("Plan %q is not offered in data center %q.", resourceIdentifier, dcIdentifier),
+			)
+			return diags
+		}
+		if err := checkPlanChange(state.Ssd.ValueInt64(), offer); err != nil {
+			diags.AddAttributeError(path.Root("resource_identifier"), "Cannot shrink server disk", err.Error())
+			return diags
+		}
+
+		cpu = types.Int64Value(int64(offer.Cpu))
+		ram = types.Int64Value(int64(offer.Ram))
+		ssd = types.Int64Value(int64(offer.Ssd))
+		diags.Append(resp.Plan.SetAttribute(ctx, path.Root("boxsize_id"), types.Int64Unknown())...)
+	}
+
+	if config.Cpu.IsNull() {
+		diags.Append(resp.Plan.SetAttribute(ctx, path.Root("cpu"), cpu)...)
+	}
+	if config.Ram.IsNull() {
+		diags.Append(resp.Plan.SetAttribute(ctx, path.Root("ram"), ram)...)
+	}
+	diags.Append(resp.Plan.SetAttribute(ctx, path.Root("ssd"), ssd)...)
+
+	return diags
+}
+
+// modifyPlanPower plans the state of a server whose power_state changes.
+func (s *serverResource) modifyPlanPower(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	var statePower, planPower types.String
+	diags.Append(req.State.GetAttribute(ctx, path.Root("power_state"), &statePower)...)
+	diags.Append(req.Plan.GetAttribute(ctx, path.Root("power_state"), &planPower)...)
+	if diags.HasError() || planPower.IsUnknown() || planPower.IsNull() || statePower.Equal(planPower) {
+		return diags
+	}
+
+	diags.Append(resp.Plan.SetAttribute(ctx, path.Root("state"), planPower)...)
+	diags.Append(resp.Plan.SetAttribute(ctx, path.Root("power"), types.Int64Unknown())...)
+
+	return diags
+}
+
+// modifyPlanProtection plans is_locked for a server whose
+// deletion_protection changes.
+func (s *serverResource) modifyPlanProtection(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	var stateProtection, planProtection types.Bool
+	diags.Append(req.State.GetAttribute(ctx, path.Root("deletion_protection"), &stateProtection)...)
+	diags.Append(req.Plan.GetAttribute(ctx, path.Root("deletion_protection"), &planProtection)...)
+	if diags.HasError() || stateProtection.Equal(planProtection) {
+		return diags
+	}
+
+	diags.Append(resp.Plan.SetAttribute(ctx, path.Root("is_locked"), lockedValue(planProtection))...)
+
+	return diags
 }
 
 // Create creates the resource and sets the initial Terraform state.
@@ -729,6 +1027,15 @@ func (s *serverResource) Create(ctx context.Context, req resource.CreateRequest,
 	var createServerReq *govpsie.CreateServerRequest = &govpsie.CreateServerRequest{}
 	createServerReq.Tags = []*string{}
 
+	var tagsAll []string
+	resp.Diagnostics.Append(plan.TagsAll.ElementsAs(ctx, &tagsAll, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	for _, tag := range tagsAll {
+		createServerReq.Tags = append(createServerReq.Tags, &tag)
+	}
+
 	createServerReq.AddPrivateIp = plan.AddPrivateIp.ValueInt64Pointer()
 	createServerReq.AddPublicIpV4 = plan.AddPublicIpV4.ValueInt64Pointer()
 	createServerReq.AddPublicIpV6 = plan.AddPublicIpV6.ValueInt64Pointer()
@@ -751,9 +1058,35 @@ func (s *serverResource) Create(ctx context.Context, req resource.CreateRequest,
 		createServerReq.Notes = plan.Notes.ValueStringPointer()
 	}
 
-	err := s.client.CreateServer(ctx, createServerReq)
+	marker, err := serverCreateMarker()
+	if err != nil {
+		resp.Diagnostics.AddError("Error creating server", "couldn't generate a marker for the new server: "+err.Error())
+		return
+	}
+
+	plan.UserDataScriptID = types.StringNull()
+	if !plan.UserData.IsNull() {
+		scriptID, err := s.createUserDataScript(ctx, plan.Hostname.ValueString(), plan.UserData.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("Error uploading server user data", err.Error())
+			return
+		}
+		createServerReq.ScriptIdentifier = &scriptID
+		plan.UserDataScriptID = types.StringValue(scriptID)
+	}
+
+	// The create endpoint does not return the new VM, so it is tagged with a
+	// marker unique to this request and found by it afterwards.
+	createServerReq.Tags = append(createServerReq.Tags, &marker)
+
+	err = s.client.CreateServer(ctx, createServerReq)
 	if err != nil {
 		resp.Diagnostics.AddError("Error creating server", err.Error())
+		if !plan.UserDataScriptID.IsNull() {
+			if err := s.deleteUserDataScript(ctx, plan.UserDataScriptID.ValueString()); err != nil {
+				resp.Diagnostics.AddWarning("Error deleting server user data", err.Error())
+			}
+		}
 		return
 	}
 
@@ -765,106 +1098,130 @@ func (s *serverResource) Create(ctx context.Context, req resource.CreateRequest,
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, createTimeout)
-	defer cancel()
+	server, err := (&waiter.Conf[govpsie.VmData]{
+		Pending: []string{waiter.StateCreating},
+		Target:  []string{waiter.StateCreated},
+		Refresh: waiter.Appeared(func(ctx context.Context) (*govpsie.VmData, bool, error) {
+			return s.checkResourceStatus(ctx, createServerReq, marker)
+		}),
+		Timeout: createTimeout,
+	}).Wait(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Error waiting for server to become ready", err.Error())
+		return
+	}
 
-	for {
-		// Check if the context has expired
-		if ctx.Err() != nil {
-			resp.Diagnostics.AddError("error waiting for resource to become ready", ctx.Err().Error())
+	plan.ID = types.Int64Value(server.ID)
+	plan.Identifier = types.StringValue(server.Identifier)
+	plan.UserID = types.Int64Value(server.UserID)
+	plan.BoxSizeID = types.Int64Value(server.BoxSizeID)
+	plan.BoxImageID = types.Int64Value(server.BoxImageID)
+	plan.DataCenterID = types.Int64Value(server.DataCenterID)
+	plan.NodeID = types.Int64Value(server.NodeID)
+	plan.BoxdIsCountID = types.Int64PointerValue(server.BoxdIsCountID)
+	plan.Hostname = types.StringValue(server.Hostname)
+	plan.DefaultIP = types.StringValue(server.DefaultIP)
+	plan.DefaultIPv6 = types.StringValue(server.DefaultIPv6)
+	plan.PrivateIP = types.StringValue(server.PrivateIP)
+	plan.IsAutoBackup = types.Int64Value(server.IsAutoBackup)
+	plan.BoxVirtualization = types.StringValue(server.BoxVirtualization)
+	plan.Ram = types.Int64Value(server.Ram)
+	plan.Cpu = types.Int64Value(server.Cpu)
+	plan.Ssd = types.Int64Value(server.Ssd)
+	plan.Traffic = types.Int64Value(server.Traffic)
+	plan.AddedIpAddresses = types.StringPointerValue(server.AddedIpAddresses)
+	plan.InitialPassword = initialPassword(plan, server)
+	plan.Notes = types.StringPointerValue(server.Notes)
+	plan.CreatedOn = types.StringValue(server.CreatedOn)
+	plan.LastUpdated = types.StringValue(server.LastUpdated)
+	plan.DroppedOn = types.StringPointerValue(server.DroppedOn)
+	plan.IsActive = types.Int64Value(server.IsActive)
+	plan.IsDeleted = types.Int64Value(server.IsDeleted)
+	plan.Power = types.Int64Value(server.Power)
+	plan.ProjectID = types.Int64Value(server.ProjectID)
+	plan.IsCustom = types.Int64Value(server.IsCustom)
+	plan.NrAddedIps = types.Int64Value(server.NrAddedIps)
+	plan.InPcs = types.Int64Value(server.InPcs)
+	plan.CustomPrice = types.Int64PointerValue(server.CustomPrice)
+	plan.PayableLicense = types.Int64Value(server.PayableLicense)
+	plan.LastLicensePay = types.StringPointerValue(server.LastLicensePay)
+	plan.ScriptID = scriptID(plan, server)
+	plan.SshKeyID = sshKeyID(plan, server)
+	plan.IsLocked = types.Int64Value(server.IsLocked)
+	plan.IsWorkWithNew = types.Int64Value(server.IsWorkWithNew)
+	plan.IsSuspended = types.Int64Value(server.IsSuspended)
+	plan.IsTerminated = types.Int64Value(server.IsTerminated)
+	plan.OldID = types.Int64Value(server.OldID)
+	plan.CustomIsoID = types.Int64PointerValue(server.CustomIsoID)
+	plan.IsIsoImageBootAble = types.Int64Value(server.IsIsoImageBootAble)
+	plan.HasSsl = types.Int64Value(server.HasSsl)
+	plan.LastActionDate = types.StringPointerValue(server.LastActionDate)
+	plan.IsCreatedFromLegacy = types.Int64Value(server.IsCreatedFromLegacy)
+	plan.IsSmtpAllowed = types.Int64Value(server.IsSmtpAllowed)
+	plan.WeeklyBackup = types.Int64Value(server.WeeklyBackup)
+	plan.MonthlyBackup = types.Int64Value(server.MonthlyBackup)
+	plan.LibIsoID = types.Int64PointerValue(server.LibIsoID)
+	plan.DailySnapshot = types.Int64Value(server.DailySnapshot)
+	plan.WeeklySnapshot = types.Int64Value(server.WeeklySnapshot)
+	plan.MonthlySnapshot = types.Int64Value(server.MonthlySnapshot)
+	plan.LastActionInMin = types.Int64Value(server.LastActionInMin)
+	plan.FirstName = types.StringValue(server.FirstName)
+	plan.LastName = types.StringValue(server.LastName)
+	plan.Username = types.StringValue(server.Username)
+	plan.State = types.StringValue(server.State)
+	plan.IsFipAvailable = types.Int64Value(server.IsFipAvailable)
+	plan.IsBucketAvailable = types.Int64Value(server.IsBucketAvailable)
+	plan.DcIdentifier = types.StringValue(server.DcIdentifier)
+	plan.Category = types.StringValue(server.Category)
+	plan.FullName = types.StringValue(server.FullName)
+	plan.VmDescription = types.StringValue(server.VmDescription)
+	plan.BoxesSuspended = types.Int64Value(server.BoxesSuspended)
+	plan.IsSataAvailable = types.Int64Value(server.IsSataAvailable)
+	plan.IsSsdAvailable = types.Int64Value(server.IsSsdAvailable)
+	plan.PublicIp = types.StringPointerValue(server.PublicIp)
+
+	target := plan.PowerState.ValueString()
+	plan.PowerState = types.StringValue(server.State)
+	if target != "" && target != server.State {
+		resp.Diagnostics.Append(s.setPowerState(ctx, &plan, target, createTimeout)...)
+		if resp.Diagnostics.HasError() {
 			return
 		}
+	}
 
-		// Check resource status here using provider's API or SDK
-		server, ready, err := s.checkResourceStatus(ctx, plan.Hostname.ValueString())
-		if err != nil {
-			//  return fmt.Errorf("Timeout waiting for resource to become ready")
-			resp.Diagnostics.AddError("Error checking status of resource", err.Error())
+	if !plan.SshKeyIDs.IsNull() {
+		resp.Diagnostics.Append(s.updateSshKeys(ctx, plan.Identifier.ValueString(), plan.SshKeyIDs)...)
+		if resp.Diagnostics.HasError() {
 			return
 		}
+	}
 
-		if ready {
-
-			plan.ID = types.Int64Value(server.ID)
-			plan.Identifier = types.StringValue(server.Identifier)
-			plan.UserID = types.Int64Value(server.UserID)
-			plan.BoxSizeID = types.Int64Value(server.BoxSizeID)
-			plan.BoxImageID = types.Int64Value(server.BoxImageID)
-			plan.DataCenterID = types.Int64Value(server.DataCenterID)
-			plan.NodeID = types.Int64Value(server.NodeID)
-			plan.BoxdIsCountID = types.Int64PointerValue(server.BoxdIsCountID)
-			plan.Hostname = types.StringValue(server.Hostname)
-			plan.DefaultIP = types.StringValue(server.DefaultIP)
-			plan.DefaultIPv6 = types.StringValue(server.DefaultIPv6)
-			plan.PrivateIP = types.StringValue(server.PrivateIP)
-			plan.IsAutoBackup = types.Int64Value(server.IsAutoBackup)
-			plan.BoxVirtualization = types.StringValue(server.BoxVirtualization)
-			plan.Ram = types.Int64Value(server.Ram)
-			plan.Cpu = types.Int64Value(server.Cpu)
-			plan.Ssd = types.Int64Value(server.Ssd)
-			plan.Traffic = types.Int64Value(server.Traffic)
-			plan.AddedIpAddresses = types.StringPointerValue(server.AddedIpAddresses)
-			plan.InitialPassword = types.StringValue(server.InitialPassword)
-			plan.Notes = types.StringPointerValue(server.Notes)
-			plan.CreatedOn = types.StringValue(server.CreatedOn)
-			plan.LastUpdated = types.StringValue(server.LastUpdated)
-			plan.DroppedOn = types.StringPointerValue(server.DroppedOn)
-			plan.IsActive = types.Int64Value(server.IsActive)
-			plan.IsDeleted = types.Int64Value(server.IsDeleted)
-			plan.Power = types.Int64Value(server.Power)
-			plan.ProjectID = types.Int64Value(server.ProjectID)
-			plan.IsCustom = types.Int64Value(server.IsCustom)
-			plan.NrAddedIps = types.Int64Value(server.NrAddedIps)
-			plan.InPcs = types.Int64Value(server.InPcs)
-			plan.CustomPrice = types.Int64PointerValue(server.CustomPrice)
-			plan.PayableLicense = types.Int64Value(server.PayableLicense)
-			plan.LastLicensePay = types.StringPointerValue(server.LastLicensePay)
-			plan.ScriptID = types.StringPointerValue(server.ScriptID)
-			plan.SshKeyID = types.StringPointerValue(server.SshKeyID)
-			plan.IsLocked = types.Int64Value(server.IsLocked)
-			plan.IsWorkWithNew = types.Int64Value(server.IsWorkWithNew)
-			plan.IsSuspended = types.Int64Value(server.IsSuspended)
-			plan.IsTerminated = types.Int64Value(server.IsTerminated)
-			plan.OldID = types.Int64Value(server.OldID)
-			plan.CustomIsoID = types.Int64PointerValue(server.CustomIsoID)
-			plan.IsIsoImageBootAble = types.Int64Value(server.IsIsoImageBootAble)
-			plan.HasSsl = types.Int64Value(server.HasSsl)
-			plan.LastActionDate = types.StringPointerValue(server.LastActionDate)
-			plan.IsCreatedFromLegacy = types.Int64Value(server.IsCreatedFromLegacy)
-			plan.IsSmtpAllowed = types.Int64Value(server.IsSmtpAllowed)
-			plan.WeeklyBackup = types.Int64Value(server.WeeklyBackup)
-			plan.MonthlyBackup = types.Int64Value(server.MonthlyBackup)
-			plan.LibIsoID = types.Int64PointerValue(server.LibIsoID)
-			plan.DailySnapshot = types.Int64Value(server.DailySnapshot)
-			plan.WeeklySnapshot = types.Int64Value(server.WeeklySnapshot)
-			plan.MonthlySnapshot = types.Int64Value(server.MonthlySnapshot)
-			plan.LastActionInMin = types.Int64Value(server.LastActionInMin)
-			plan.FirstName = types.StringValue(server.FirstName)
-			plan.LastName = types.StringValue(server.LastName)
-			plan.Username = types.StringValue(server.Username)
-			plan.State = types.StringValue(server.State)
-			plan.IsFipAvailable = types.Int64Value(server.IsFipAvailable)
-			plan.IsBucketAvailable = types.Int64Value(server.IsBucketAvailable)
-			plan.DcIdentifier = types.StringValue(server.DcIdentifier)
-			plan.Category = types.StringValue(server.Category)
-			plan.FullName = types.StringValue(server.FullName)
-			plan.VmDescription = types.StringValue(server.VmDescription)
-			plan.BoxesSuspended = types.Int64Value(server.BoxesSuspended)
-			plan.IsSataAvailable = types.Int64Value(server.IsSataAvailable)
-			plan.IsSsdAvailable = types.Int64Value(server.IsSsdAvailable)
-			plan.PublicIp = types.StringPointerValue(server.PublicIp)
-
-			diags = resp.State.Set(ctx, plan)
-			resp.Diagnostics.Append(diags...)
-			if resp.Diagnostics.HasError() {
-				return
-			}
+	// Setting the planned tags drops the create marker.
+	resp.Diagnostics.Append(s.updateTags(ctx, plan.Identifier.ValueString(), plan.TagsAll)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(s.refreshTags(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
+	// The server is locked last, once nothing else has to change on it.
+	if plan.DeletionProtection.ValueBool() {
+		resp.Diagnostics.Append(s.setLocked(ctx, &plan, true)...)
+		if resp.Diagnostics.HasError() {
 			return
 		}
-		time.Sleep(5 * time.Second)
 	}
 
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, serverIdentityModel{Identifier: plan.Identifier})...)
 }
 
 // Read refreshes the Terraform state with the latest data.
@@ -877,7 +1234,7 @@ func (s *serverResource) Read(ctx context.Context, req resource.ReadRequest, res
 	}
 	server, err := s.client.GetServerByIdentifier(ctx, state.Identifier.ValueString())
 	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
+		if apierror.IsNotFound(err) {
 			resp.State.RemoveResource(ctx)
 			return
 		}
@@ -908,7 +1265,7 @@ func (s *serverResource) Read(ctx context.Context, req resource.ReadRequest, res
 	state.Ssd = types.Int64Value(server.Ssd)
 	state.Traffic = types.Int64Value(server.Traffic)
 	state.AddedIpAddresses = types.StringPointerValue(server.AddedIpAddresses)
-	state.InitialPassword = types.StringValue(server.InitialPassword)
+	state.InitialPassword = initialPassword(state, server)
 	state.Notes = types.StringPointerValue(server.Notes)
 	state.CreatedOn = types.StringValue(server.CreatedOn)
 	state.LastUpdated = types.StringValue(server.LastUpdated)
@@ -916,6 +1273,7 @@ func (s *serverResource) Read(ctx context.Context, req resource.ReadRequest, res
 	state.IsActive = types.Int64Value(server.IsActive)
 	state.IsDeleted = types.Int64Value(server.IsDeleted)
 	state.Power = types.Int64Value(server.Power)
+	state.PowerState = types.StringValue(server.State)
 	state.ProjectID = types.Int64Value(server.ProjectID)
 	state.IsCustom = types.Int64Value(server.IsCustom)
 	state.NrAddedIps = types.Int64Value(server.NrAddedIps)
@@ -923,9 +1281,10 @@ func (s *serverResource) Read(ctx context.Context, req resource.ReadRequest, res
 	state.CustomPrice = types.Int64PointerValue(server.CustomPrice)
 	state.PayableLicense = types.Int64Value(server.PayableLicense)
 	state.LastLicensePay = types.StringPointerValue(server.LastLicensePay)
-	state.ScriptID = types.StringPointerValue(server.ScriptID)
-	state.SshKeyID = types.StringPointerValue(server.SshKeyID)
+	state.ScriptID = scriptID(state, server)
+	state.SshKeyID = sshKeyID(state, server)
 	state.IsLocked = types.Int64Value(server.IsLocked)
+	state.DeletionProtection = types.BoolValue(server.IsLocked == 1)
 	state.IsWorkWithNew = types.Int64Value(server.IsWorkWithNew)
 	state.IsSuspended = types.Int64Value(server.IsSuspended)
 	state.IsTerminated = types.Int64Value(server.IsTerminated)
@@ -957,11 +1316,26 @@ func (s *serverResource) Read(ctx context.Context, req resource.ReadRequest, res
 	state.IsSataAvailable = types.Int64Value(server.IsSataAvailable)
 	state.IsSsdAvailable = types.Int64Value(server.IsSsdAvailable)
 	state.PublicIp = types.StringPointerValue(server.PublicIp)
+
+	resp.Diagnostics.Append(s.refreshTags(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !state.SshKeyIDs.IsNull() {
+		resp.Diagnostics.Append(s.refreshSshKeys(ctx, &state)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
 	diags = resp.State.Set(ctx, &state)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, serverIdentityModel{Identifier: state.Identifier})...)
 }
 
 // Update updates the resource and sets the updated Terraform state on success.
@@ -980,6 +1354,12 @@ func (s *serverResource) Update(ctx context.Context, req resource.UpdateRequest,
 		return
 	}
 
+	updateTimeout, diags := plan.Timeouts.Update(ctx, 20*time.Minute)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	if !state.Hostname.Equal(plan.Hostname) {
 		err := s.client.ChangeHostName(ctx, state.Identifier.ValueString(), plan.Hostname.ValueString())
 		if err != nil {
@@ -994,84 +1374,107 @@ func (s *serverResource) Update(ctx context.Context, req resource.UpdateRequest,
 		state.Hostname = plan.Hostname
 	}
 
-	if !state.Power.Equal(plan.Power) {
-		if plan.Power.ValueInt64() == 1 {
-			err := s.client.StartServer(ctx, state.Identifier.ValueString())
-			if err != nil {
-				resp.Diagnostics.AddError(
-					"Error starting server",
-					"couldn't start server, unexpected error: "+err.Error(),
-				)
+	// A server losing its deletion protection is unlocked before the other
+	// changes and one gaining it is locked after them.
+	if state.DeletionProtection.ValueBool() && !plan.DeletionProtection.ValueBool() {
+		resp.Diagnostics.Append(s.setLocked(ctx, &state, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	// Rebuilds and plan changes leave the server running.
+	restarted := false
 
+	if !state.OsIdentifier.Equal(plan.OsIdentifier) {
+		// An imported server has no os_identifier in state and adopts the
+		// configured one. Otherwise the change is planned as a replacement
+		// unless rebuild_on_os_change is set.
+		if !state.OsIdentifier.IsNull() {
+			resp.Diagnostics.Append(s.rebuild(ctx, &state, plan, updateTimeout)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+			restarted = true
+
+			// The reinstall is recorded before any later step can fail,
+			// so that the next apply does not reinstall the server again
+			// and applies the keys and scripts it dropped.
+			resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+			if resp.Diagnostics.HasError() {
 				return
 			}
-		} else {
-			err := s.client.StopServer(ctx, state.Identifier.ValueString())
-			if err != nil {
-				resp.Diagnostics.AddError(
-					"Error stopping server",
-					"couldn't stop server, unexpected error: "+err.Error(),
-				)
 
+			resp.Diagnostics.Append(s.restoreUserData(ctx, &state)...)
+			if resp.Diagnostics.HasError() {
 				return
 			}
 		}
-		state.Power = plan.Power
+
+		state.OsIdentifier = plan.OsIdentifier
 	}
+	state.RebuildOnOsChange = plan.RebuildOnOsChange
 
-	if !state.IsLocked.Equal(plan.IsLocked) {
-		if plan.IsLocked.ValueInt64() == 1 {
-			err := s.client.Lock(ctx, state.Identifier.ValueString())
+	// The API cannot take a single key or script away, so unsetting
+	// sshkey_id or script_id only stops tracking it.
+	if !state.SshKeyID.Equal(plan.SshKeyID) {
+		if !plan.SshKeyID.IsNull() {
+			err := s.client.AddSsh(ctx, state.Identifier.ValueString(), plan.SshKeyID.ValueString())
 			if err != nil {
 				resp.Diagnostics.AddError(
-					"Error locking server",
-					"couldn't lock server, unexpected error: "+err.Error(),
+					"Error updating server sshkey",
+					"couldn't update server sshkey, unexpected error: "+err.Error(),
 				)
 
 				return
 			}
-		} else {
-			err := s.client.UnLock(ctx, state.Identifier.ValueString())
+		}
+
+		state.SshKeyID = plan.SshKeyID
+	}
+
+	if !state.ScriptID.Equal(plan.ScriptID) {
+		if !plan.ScriptID.IsNull() {
+			err := s.client.AddScript(ctx, state.Identifier.ValueString(), plan.ScriptID.ValueString())
 			if err != nil {
 				resp.Diagnostics.AddError(
-					"Error unlocking server",
-					"couldn't unlock server, unexpected error: "+err.Error(),
+					"Error updating server script",
+					"couldn't update server script, unexpected error: "+err.Error(),
 				)
 
 				return
 			}
 		}
 
-		state.IsLocked = plan.IsLocked
+		state.ScriptID = plan.ScriptID
 	}
 
-	if !state.SshKeyID.Equal(plan.SshKeyID) {
-		err := s.client.AddSsh(ctx, state.Identifier.ValueString(), plan.SshKeyID.ValueString())
-		if err != nil {
-			resp.Diagnostics.AddError(
-				"Error updating server sshkey",
-				"couldn't update server sshkey, unexpected error: "+err.Error(),
-			)
-
-			return
+	// Removing ssh_key_ids leaves the server's keys in place.
+	if !state.SshKeyIDs.Equal(plan.SshKeyIDs) {
+		if !plan.SshKeyIDs.IsNull() {
+			resp.Diagnostics.Append(s.updateSshKeys(ctx, state.Identifier.ValueString(), plan.SshKeyIDs)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
 		}
 
-		state.SshKeyID = plan.SshKeyID
+		state.SshKeyIDs = plan.SshKeyIDs
 	}
 
-	if !state.ScriptID.Equal(plan.ScriptID) {
-		err := s.client.AddScript(ctx, state.Identifier.ValueString(), plan.ScriptID.ValueString())
-		if err != nil {
-			resp.Diagnostics.AddError(
-				"Error updating server script",
-				"couldn't update server script, unexpected error: "+err.Error(),
-			)
-
-			return
+	if !state.ResourceIdentifier.Equal(plan.ResourceIdentifier) {
+		// An imported server has no resource_identifier in state. It adopts
+		// the configured plan without a resize when it already matches it.
+		if !state.ResourceIdentifier.IsNull() || !state.Cpu.Equal(plan.Cpu) || !state.Ram.Equal(plan.Ram) || !state.Ssd.Equal(plan.Ssd) {
+			resp.Diagnostics.Append(s.changePlan(ctx, &state, plan.ResourceIdentifier.ValueString(), updateTimeout)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+			restarted = true
 		}
 
-		state.ScriptID = plan.ScriptID
+		state.ResourceIdentifier = plan.ResourceIdentifier
 	}
+
 	if !state.Cpu.Equal(plan.Cpu) || !state.Ram.Equal(plan.Ram) {
 		cpu := strconv.FormatInt(plan.Cpu.ValueInt64(), 10)
 		ram := strconv.FormatInt(plan.Ram.ValueInt64(), 10)
@@ -1087,6 +1490,51 @@ func (s *serverResource) Update(ctx context.Context, req resource.UpdateRequest,
 		state.Ram = plan.Ram
 	}
 
+	if target := plan.PowerState.ValueString(); target != "" {
+		if !state.PowerState.Equal(plan.PowerState) || restarted && target != StateRunning {
+			resp.Diagnostics.Append(s.setPowerState(ctx, &state, target, updateTimeout)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+		}
+	}
+
+	if !state.TagsAll.Equal(plan.TagsAll) {
+		resp.Diagnostics.Append(s.updateTags(ctx, state.Identifier.ValueString(), plan.TagsAll)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+	state.Tags = plan.Tags
+	state.TagsAll = plan.TagsAll
+	state.Password = plan.Password
+	state.DeleteReason = plan.DeleteReason
+	state.DeleteNote = plan.DeleteNote
+
+	if !state.DeletionProtection.ValueBool() && plan.DeletionProtection.ValueBool() {
+		resp.Diagnostics.Append(s.setLocked(ctx, &state, true)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	if !state.StoreInitialPassword.Equal(plan.StoreInitialPassword) {
+		state.StoreInitialPassword = plan.StoreInitialPassword
+		state.InitialPassword = types.StringNull()
+
+		if plan.StoreInitialPassword.ValueBool() {
+			server, err := s.client.GetServerByIdentifier(ctx, state.Identifier.ValueString())
+			if err != nil {
+				resp.Diagnostics.AddError(
+					"Error reading vpsie server",
+					"couldn't read vpsie server identifier "+state.Identifier.ValueString()+": "+err.Error(),
+				)
+				return
+			}
+			state.InitialPassword = initialPassword(state, server)
+		}
+	}
+
 	diags = resp.State.Set(ctx, &state)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
@@ -1104,22 +1552,28 @@ func (s *serverResource) Delete(ctx context.Context, req resource.DeleteRequest,
 		return
 	}
 
-	if state.DeleteReason.IsNull() || state.Password.IsNull() || state.DeleteReason.ValueString() == "" || state.Password.ValueString() == "" {
+	resp.Diagnostics.Append(deletion.CheckProtection(state.DeletionProtection, "server", state.Identifier.ValueString())...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Terraform does not send the configuration to Delete, so credentials
+	// that are not in state have to come from the provider.
+	password := deletion.Pick(state.Password.ValueString(), s.deletion.Password)
+	deleteReason := deletion.Pick(state.DeleteReason.ValueString(), s.deletion.Reason)
+	deleteNote := deletion.Pick(state.DeleteNote.ValueString(), s.deletion.Note)
+
+	if password == "" || deleteReason == "" {
 		resp.Diagnostics.AddError(
 			"Error deleting server",
-			"Delete reason and password are required to delete server",
+			"A password and a delete reason are required to delete a server. Set delete_password and delete_reason on the provider, "+
+				"or the VPSIE_DELETE_PASSWORD and VPSIE_DELETE_REASON environment variables.",
 		)
 
 		return
 	}
 
-	var deleteNote string
-	if state.DeleteNote.IsNull() {
-		deleteNote = ""
-	} else {
-		deleteNote = state.DeleteNote.ValueString()
-	}
-	err := s.client.DeleteServer(ctx, state.Identifier.ValueString(), state.Password.ValueString(), state.DeleteReason.ValueString(), deleteNote)
+	err := s.client.DeleteServer(ctx, state.Identifier.ValueString(), password, deleteReason, deleteNote)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error deleting server",
@@ -1128,23 +1582,438 @@ func (s *serverResource) Delete(ctx context.Context, req resource.DeleteRequest,
 
 		return
 	}
+
+	// The server is gone at this point, so a script that cannot be deleted
+	// does not keep it in state.
+	if !state.UserDataScriptID.IsNull() {
+		if err := s.deleteUserDataScript(ctx, state.UserDataScriptID.ValueString()); err != nil {
+			resp.Diagnostics.AddWarning(
+				"Error deleting server user data",
+				"couldn't delete user data script "+state.UserDataScriptID.ValueString()+", delete it manually: "+err.Error(),
+			)
+		}
+	}
 }
 
 func (s *serverResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("identifier"), req, resp)
+	resource.ImportStatePassthroughWithIdentity(ctx, path.Root("identifier"), path.Root("identifier"), req, resp)
+}
+
+// changePlan moves the server in m to the resource plan resourceIdentifier,
+// waits until it runs on the new plan and stores its new size in m.
+func (s *serverResource) changePlan(ctx context.Context, m *serverResourceModel, resourceIdentifier string, timeout time.Duration) diag.Diagnostics {
+	var diags diag.Diagnostics
+	identifier := m.Identifier.ValueString()
+	dcIdentifier := m.DcIdentifier.ValueString()
+
+	plans, err := s.plans.ListServerPlans(ctx, dcIdentifier)
+	if err != nil {
+		diags.AddError(
+			"Error reading server plans",
+			"couldn't list the resource plans of data center "+dcIdentifier+": "+err.Error(),
+		)
+		return diags
+	}
+	offer, ok := findServerPlan(plans, resourceIdentifier)
+	if !ok {
+		diags.AddError(
+			"Unknown server plan",
+			fmt.Sprintf("Plan %q is not offered in data center %q.", resourceIdentifier, dcIdentifier),
+		)
+		return diags
+	}
+
+	err = s.plans.ChangeServerPlan(ctx, identifier, resourceIdentifier)
+	if err != nil {
+		diags.AddError(
+			"Error changing server plan",
+			"couldn't move server "+identifier+" to plan "+resourceIdentifier+", unexpected error: "+err.Error(),
+		)
+		return diags
+	}
+
+	server, err := waitForRestart(ctx, s.client, identifier, timeout, func(server *govpsie.VmData) bool {
+		return onServerPlan(server, offer)
+	})
+	if err != nil {
+		diags.AddError(
+			"Error waiting for server plan change",
+			"server "+identifier+" did not come back up after moving to plan "+resourceIdentifier+": "+err.Error(),
+		)
+		return diags
+	}
+
+	m.BoxSizeID = types.Int64Value(server.BoxSizeID)
+	m.Cpu = types.Int64Value(server.Cpu)
+	m.Ram = types.Int64Value(server.Ram)
+	m.Ssd = types.Int64Value(server.Ssd)
+
+	return diags
+}
+
+// setLocked locks or unlocks the server in m through the API and records
+// the result as its deletion protection. Locking only moves the server to a
+// target state, so it is safe to retry.
+func (s *serverResource) setLocked(ctx context.Context, m *serverResourceModel, locked bool) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if locked {
+		if err := s.client.Lock(transport.AllowRetry(ctx), m.Identifier.ValueString()); err != nil {
+			diags.AddError(
+				"Error locking server",
+				"couldn't lock server, unexpected error: "+err.Error(),
+			)
+			return diags
+		}
+	} else {
+		if err := s.client.UnLock(transport.AllowRetry(ctx), m.Identifier.ValueString()); err != nil {
+			diags.AddError(
+				"Error unlocking server",
+				"couldn't unlock server, unexpected error: "+err.Error(),
+			)
+			return diags
+		}
+	}
+
+	m.DeletionProtection = types.BoolValue(locked)
+	m.IsLocked = lockedValue(m.DeletionProtection)
+
+	return diags
+}
+
+// setPowerState starts or stops the server in m, waits until it reaches
+// target and stores its new state in m. Starting and stopping only move the
+// server to a target state, so they are safe to retry.
+func (s *serverResource) setPowerState(ctx context.Context, m *serverResourceModel, target string, timeout time.Duration) diag.Diagnostics {
+	var diags diag.Diagnostics
+	identifier := m.Identifier.ValueString()
+
+	var err error
+	if target == StateRunning {
+		err = s.client.StartServer(transport.AllowRetry(ctx), identifier)
+	} else {
+		err = s.client.StopServer(transport.AllowRetry(ctx), identifier)
+	}
+	if err != nil {
+		diags.AddError(
+			"Error changing server power state",
+			"couldn't move server "+identifier+" to "+target+", unexpected error: "+err.Error(),
+		)
+		return diags
+	}
+
+	server, err := waitForState(ctx, s.client, identifier, target, timeout)
+	if err != nil {
+		diags.AddError(
+			"Error waiting for server power state",
+			"server "+identifier+" did not reach "+target+": "+err.Error(),
+		)
+		return diags
+	}
+
+	m.Power = types.Int64Value(server.Power)
+	m.State = types.StringValue(server.State)
+	m.PowerState = types.StringValue(server.State)
+
+	return diags
+}
+
+// rebuild reinstalls the server in m with the image planned in plan, waits
+// until it is running again and stores its new image in m. The reinstall
+// drops the server's SSH keys and scripts, so sshkey_id, script_id and
+// ssh_key_ids are cleared in m for Update to apply them again.
+func (s *serverResource) rebuild(ctx context.Context, m *serverResourceModel, plan serverResourceModel, timeout time.Duration) diag.Diagnostics {
+	var diags diag.Diagnostics
+	identifier := m.Identifier.ValueString()
+	osIdentifier := plan.OsIdentifier.ValueString()
+
+	err := s.rebuilds.RebuildServer(ctx, identifier, osIdentifier)
+	if err != nil {
+		diags.AddError(
+			"Error rebuilding server",
+			"couldn't rebuild server "+identifier+" with image "+osIdentifier+", unexpected error: "+err.Error(),
+		)
+		return diags
+	}
+
+	// Until the API starts the reinstall it reports the old image, with the
+	// server still running.
+	previousImage := m.BoxImageID.ValueInt64()
+	server, err := waitForRestart(ctx, s.client, identifier, timeout, func(server *govpsie.VmData) bool {
+		return server.BoxImageID != previousImage
+	})
+	if err != nil {
+		diags.AddError(
+			"Error waiting for server rebuild",
+			"server "+identifier+" did not come back up after being rebuilt with image "+osIdentifier+": "+err.Error(),
+		)
+		return diags
+	}
+
+	m.OsIdentifier = plan.OsIdentifier
+	m.BoxImageID = types.Int64Value(server.BoxImageID)
+	m.InitialPassword = initialPassword(plan, server)
+	m.SshKeyID = types.StringNull()
+	m.ScriptID = types.StringNull()
+	m.SshKeyIDs = types.SetNull(types.StringType)
+
+	return diags
+}
+
+// restoreUserData applies the user data script of the server in m again
+// after a rebuild dropped it.
+func (s *serverResource) restoreUserData(ctx context.Context, m *serverResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if m.UserDataScriptID.IsNull() {
+		return diags
+	}
+
+	err := s.client.AddScript(ctx, m.Identifier.ValueString(), m.UserDataScriptID.ValueString())
+	if err != nil {
+		diags.AddError(
+			"Error updating server user data",
+			"couldn't add user data to rebuilt server, unexpected error: "+err.Error(),
+		)
+	}
+
+	return diags
 }
 
-func (s *serverResource) checkResourceStatus(ctx context.Context, hostname string) (*govpsie.VmData, bool, error) {
+// serverDeletionProtectionAttribute returns the shared deletion_protection
+// attribute, which on a server also locks it through the API.
+func serverDeletionProtectionAttribute() schema.BoolAttribute {
+	attr := deletion.ProtectionAttribute("server")
+	attr.MarkdownDescription += " The server is locked through the API while it is enabled, which also blocks deletion outside Terraform."
+	return attr
+}
+
+// lockedValue returns the is_locked value matching deletion protection.
+func lockedValue(protection types.Bool) types.Int64 {
+	switch {
+	case protection.IsUnknown():
+		return types.Int64Unknown()
+	case protection.ValueBool():
+		return types.Int64Value(1)
+	default:
+		return types.Int64Value(0)
+	}
+}
+
+// initialPassword returns the initial password of server as it belongs in
+// m, which is null unless m opts in to storing it. An imported server does
+// not store it until store_initial_password is set.
+func initialPassword(m serverResourceModel, server *govpsie.VmData) types.String {
+	if !m.StoreInitialPassword.ValueBool() {
+		return types.StringNull()
+	}
+	return types.StringValue(server.InitialPassword)
+}
+
+// scriptID returns the startup script of server as it belongs in m. The
+// script holding user_data is not reported as script_id.
+func scriptID(m serverResourceModel, server *govpsie.VmData) types.String {
+	if !m.UserData.IsNull() {
+		return m.ScriptID
+	}
+	return types.StringPointerValue(server.ScriptID)
+}
+
+// sshKeyID returns the SSH key of server as it belongs in m. Servers whose
+// keys are managed with ssh_key_ids leave sshkey_id unset.
+func sshKeyID(m serverResourceModel, server *govpsie.VmData) types.String {
+	if !m.SshKeyIDs.IsNull() {
+		return m.SshKeyID
+	}
+	return types.StringPointerValue(server.SshKeyID)
+}
+
+// refreshSshKeys reads the SSH keys installed on the server in m into
+// SshKeyIDs.
+func (s *serverResource) refreshSshKeys(ctx context.Context, m *serverResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	keys, err := s.sshKeys.ListServerSshKeys(ctx, m.Identifier.ValueString())
+	if err != nil {
+		diags.AddError(
+			"Error reading server SSH keys",
+			"couldn't read SSH keys of vpsie server identifier "+m.Identifier.ValueString()+": "+err.Error(),
+		)
+		return diags
+	}
+
+	m.SshKeyIDs, diags = types.SetValueFrom(ctx, types.StringType, keys)
+	return diags
+}
+
+// updateSshKeys installs the SSH keys in planned on the server with the given
+// identifier and removes the ones it has that are not planned.
+func (s *serverResource) updateSshKeys(ctx context.Context, identifier string, planned types.Set) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	var want []string
+	diags.Append(planned.ElementsAs(ctx, &want, false)...)
+	if diags.HasError() {
+		return diags
+	}
+
+	have, err := s.sshKeys.ListServerSshKeys(ctx, identifier)
+	if err != nil {
+		diags.AddError(
+			"Error reading server SSH keys",
+			"couldn't read SSH keys of vpsie server identifier "+identifier+": "+err.Error(),
+		)
+		return diags
+	}
+
+	added, removed := diffServerSshKeys(want, have)
+	for _, key := range added {
+		if err := s.sshKeys.AddServerSshKey(ctx, identifier, key); err != nil {
+			diags.AddError(
+				"Error updating server SSH keys",
+				"couldn't add SSH key "+key+" to server "+identifier+", unexpected error: "+err.Error(),
+			)
+			return diags
+		}
+	}
+	for _, key := range removed {
+		if err := s.sshKeys.RemoveServerSshKey(ctx, identifier, key); err != nil {
+			diags.AddError(
+				"Error updating server SSH keys",
+				"couldn't remove SSH key "+key+" from server "+identifier+", unexpected error: "+err.Error(),
+			)
+			return diags
+		}
+	}
+
+	return diags
+}
+
+// refreshTags reads the tags of the server in m. All of them are stored in
+// TagsAll, and those that are not inherited from the provider's default tags
+// in Tags.
+func (s *serverResource) refreshTags(ctx context.Context, m *serverResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	apiTags, err := s.tags.ListServerTags(ctx, m.Identifier.ValueString())
+	if err != nil {
+		diags.AddError(
+			"Error reading server tags",
+			"couldn't read tags of vpsie server identifier "+m.Identifier.ValueString()+": "+err.Error(),
+		)
+		return diags
+	}
+
+	var declared []string
+	if !m.Tags.IsNull() && !m.Tags.IsUnknown() {
+		diags.Append(m.Tags.ElementsAs(ctx, &declared, false)...)
+		if diags.HasError() {
+			return diags
+		}
+	}
+
+	var d diag.Diagnostics
+	m.TagsAll, d = types.SetValueFrom(ctx, types.StringType, apiTags)
+	diags.Append(d...)
+	m.Tags, d = types.SetValueFrom(ctx, types.StringType, tags.ResourceTags(apiTags, s.defaultTags, declared))
+	diags.Append(d...)
+
+	return diags
+}
+
+// updateTags makes the tags of a server match planned. Additions go through
+// the add tags endpoint; removing a tag replaces the whole set.
+func (s *serverResource) updateTags(ctx context.Context, identifier string, planned types.Set) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	var want []string
+	diags.Append(planned.ElementsAs(ctx, &want, false)...)
+	if diags.HasError() {
+		return diags
+	}
+
+	have, err := s.tags.ListServerTags(ctx, identifier)
+	if err != nil {
+		diags.AddError(
+			"Error reading server tags",
+			"couldn't read tags of vpsie server identifier "+identifier+": "+err.Error(),
+		)
+		return diags
+	}
+
+	added, removed := diffServerTags(want, have)
+	switch {
+	case removed:
+		err = s.tags.SetServerTags(ctx, identifier, want)
+	case len(added) > 0:
+		err = s.tags.AddServerTags(ctx, identifier, added)
+	}
+	if err != nil {
+		diags.AddError(
+			"Error updating server tags",
+			"couldn't update server tags, unexpected error: "+err.Error(),
+		)
+	}
+
+	return diags
+}
+
+// serverCreateMarker returns a random tag unique to one create request,
+// which the new VM is found by.
+func serverCreateMarker() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "terraform-create-" + hex.EncodeToString(b), nil
+}
+
+// checkResourceStatus looks for the VM created by createReq, which carries
+// the tag marker. Only VMs with the requested hostname have their tags
+// read, and an error is returned when more than one of them carries the
+// marker rather than binding to either.
+func (s *serverResource) checkResourceStatus(ctx context.Context, createReq *govpsie.CreateServerRequest, marker string) (*govpsie.VmData, bool, error) {
 	servers, err := s.client.List(ctx, nil)
 	if err != nil {
 		return nil, false, err
 	}
 
+	var matches []govpsie.VmData
 	for _, server := range servers {
-		if server.Hostname == hostname {
-			return &server, true, nil
+		if server.Hostname != createReq.Hostname {
+			continue
+		}
+		if server.DcIdentifier != "" && createReq.DcIdentifier != "" && server.DcIdentifier != createReq.DcIdentifier {
+			continue
+		}
+
+		serverTags, err := s.tags.ListServerTags(ctx, server.Identifier)
+		if err != nil {
+			if apierror.IsNotFound(err) {
+				continue
+			}
+			return nil, false, err
 		}
+		if slices.Contains(serverTags, marker) {
+			matches = append(matches, server)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, false, nil
+	case 1:
+		return &matches[0], true, nil
+	}
+
+	identifiers := make([]string, len(matches))
+	for i, server := range matches {
+		identifiers[i] = server.Identifier
 	}
 
-	return nil, false, nil
+	return nil, false, fmt.Errorf(
+		"found %d servers with hostname %q tagged %s (%s) and cannot tell which one this resource created; "+
+			"remove the extra servers or import the correct one with terraform import",
+		len(matches), createReq.Hostname, marker, strings.Join(identifiers, ", "),
+	)
 }
@@ -2,9 +2,14 @@ package server
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/vpsie/govpsie"
+	"github.com/vpsie/terraform-provider-vpsie/internal/apitest"
 )
 
 // mockServerAPI implements ServerAPI for unit testing.
@@ -16,6 +21,7 @@ type mockServerAPI struct {
 	ChangeHostNameFn        func(ctx context.Context, identifierId string, newHostname string) error
 	StartServerFn           func(ctx context.Context, identifierId string) error
 	StopServerFn            func(ctx context.Context, identifierId string) error
+	RestartServerFn         func(ctx context.Context, identifierId string) error
 	LockFn                  func(ctx context.Context, identifierId string) error
 	UnLockFn                func(ctx context.Context, identifierId string) error
 	AddSshFn                func(ctx context.Context, identifierId, sshKeyIdentifier string) error
@@ -51,6 +57,10 @@ func (m *mockServerAPI) StopServer(ctx context.Context, identifierId string) err
 	return m.StopServerFn(ctx, identifierId)
 }
 
+func (m *mockServerAPI) RestartServer(ctx context.Context, identifierId string) error {
+	return m.RestartServerFn(ctx, identifierId)
+}
+
 func (m *mockServerAPI) Lock(ctx context.Context, identifierId string) error {
 	return m.LockFn(ctx, identifierId)
 }
@@ -97,6 +107,9 @@ func TestUnitServerAPI_MockSatisfiesInterface(t *testing.T) {
 		StopServerFn: func(ctx context.Context, identifierId string) error {
 			return nil
 		},
+		RestartServerFn: func(ctx context.Context, identifierId string) error {
+			return nil
+		},
 		LockFn: func(ctx context.Context, identifierId string) error {
 			return nil
 		},
@@ -119,38 +132,79 @@ func TestUnitServerAPI_MockSatisfiesInterface(t *testing.T) {
 }
 
 func TestUnitServerAPI_CheckResourceStatus(t *testing.T) {
+	const marker = "terraform-create-abc"
+
 	tests := []struct {
 		name        string
 		hostname    string
+		tags        map[string][]string
 		servers     []govpsie.VmData
+		expectID    string
 		expectFound bool
 		expectErr   bool
 	}{
 		{
-			name:     "server found by hostname",
+			name:     "server found by marker",
 			hostname: "test-host",
+			tags:     map[string][]string{"id-2": {"web", marker}},
 			servers: []govpsie.VmData{
 				{Hostname: "other-host", Identifier: "id-1"},
 				{Hostname: "test-host", Identifier: "id-2"},
 			},
+			expectID:    "id-2",
+			expectFound: true,
+		},
+		{
+			name:     "server with same hostname but another marker ignored",
+			hostname: "test-host",
+			tags: map[string][]string{
+				"id-1": {"terraform-create-xyz"},
+				"id-2": {marker},
+			},
+			servers: []govpsie.VmData{
+				{Hostname: "test-host", Identifier: "id-1"},
+				{Hostname: "test-host", Identifier: "id-2"},
+			},
+			expectID:    "id-2",
 			expectFound: true,
-			expectErr:   false,
 		},
 		{
-			name:     "server not found",
+			name:     "only servers without the marker",
+			hostname: "test-host",
+			tags:     map[string][]string{"id-1": {"web"}},
+			servers: []govpsie.VmData{
+				{Hostname: "test-host", Identifier: "id-1"},
+			},
+			expectFound: false,
+		},
+		{
+			name:     "ambiguous servers with the marker",
+			hostname: "test-host",
+			tags: map[string][]string{
+				"id-1": {marker},
+				"id-2": {marker},
+			},
+			servers: []govpsie.VmData{
+				{Hostname: "test-host", Identifier: "id-1"},
+				{Hostname: "test-host", Identifier: "id-2"},
+			},
+			expectFound: false,
+			expectErr:   true,
+		},
+		{
+			name:     "marker on a server with another hostname ignored",
 			hostname: "missing-host",
+			tags:     map[string][]string{"id-1": {marker}},
 			servers: []govpsie.VmData{
 				{Hostname: "other-host", Identifier: "id-1"},
 			},
 			expectFound: false,
-			expectErr:   false,
 		},
 		{
 			name:        "empty server list",
 			hostname:    "test-host",
 			servers:     []govpsie.VmData{},
 			expectFound: false,
-			expectErr:   false,
 		},
 	}
 
@@ -162,8 +216,8 @@ func TestUnitServerAPI_CheckResourceStatus(t *testing.T) {
 				},
 			}
 
-			r := &serverResource{client: mock}
-			server, found, err := r.checkResourceStatus(t.Context(), tt.hostname)
+			r := &serverResource{client: mock, tags: &mockServerTagsAPI{byServer: tt.tags}}
+			server, found, err := r.checkResourceStatus(t.Context(), &govpsie.CreateServerRequest{Hostname: tt.hostname}, marker)
 
 			if tt.expectErr && err == nil {
 				t.Fatal("expected error, got nil")
@@ -177,8 +231,8 @@ func TestUnitServerAPI_CheckResourceStatus(t *testing.T) {
 			if tt.expectFound && server == nil {
 				t.Fatal("expected server to be non-nil when found")
 			}
-			if tt.expectFound && server.Hostname != tt.hostname {
-				t.Fatalf("expected hostname %q, got %q", tt.hostname, server.Hostname)
+			if tt.expectFound && server.Identifier != tt.expectID {
+				t.Fatalf("expected identifier %q, got %q", tt.expectID, server.Identifier)
 			}
 		})
 	}
@@ -248,3 +302,144 @@ func TestUnitServerAPI_DeleteServer(t *testing.T) {
 		t.Fatalf("expected note 'test note', got %q", calledWith.note)
 	}
 }
+
+// mockServerTagsAPI implements ServerTagsAPI for unit testing.
+type mockServerTagsAPI struct {
+	tags  []string
+	calls []string
+	// byServer, when set, holds the tags of each server instead of tags.
+	byServer map[string][]string
+}
+
+func (m *mockServerTagsAPI) ListServerTags(ctx context.Context, identifierId string) ([]string, error) {
+	if m.byServer != nil {
+		return m.byServer[identifierId], nil
+	}
+	return m.tags, nil
+}
+
+func (m *mockServerTagsAPI) AddServerTags(ctx context.Context, identifierId string, tags []string) error {
+	m.calls = append(m.calls, fmt.Sprintf("add %v", tags))
+	m.tags = append(m.tags, tags...)
+	return nil
+}
+
+func (m *mockServerTagsAPI) SetServerTags(ctx context.Context, identifierId string, tags []string) error {
+	m.calls = append(m.calls, fmt.Sprintf("set %v", tags))
+	m.tags = tags
+	return nil
+}
+
+func TestUnitServerTags_Decode(t *testing.T) {
+	raw := []json.RawMessage{
+		json.RawMessage(`"web"`),
+		json.RawMessage(`{"id": 4, "tag": "cost-center-a"}`),
+		json.RawMessage(`{"name": "blue"}`),
+	}
+
+	tags, err := decodeServerTags(raw)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fmt.Sprint(tags) != "[blue cost-center-a web]" {
+		t.Fatalf("unexpected tags: %v", tags)
+	}
+
+	if _, err := decodeServerTags([]json.RawMessage{json.RawMessage(`{"id": 4}`)}); err == nil {
+		t.Fatal("expected error for tag without a name, got nil")
+	}
+}
+
+func TestUnitServerTags_Update(t *testing.T) {
+	tests := []struct {
+		name   string
+		have   []string
+		want   []string
+		expect string
+	}{
+		{name: "only additions", have: []string{"web"}, want: []string{"web", "prod"}, expect: "[add [prod]]"},
+		{name: "removal replaces set", have: []string{"web", "old"}, want: []string{"web", "prod"}, expect: "[set [web prod]]"},
+		{name: "unchanged", have: []string{"web"}, want: []string{"web"}, expect: "[]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockServerTagsAPI{tags: tt.have}
+			r := &serverResource{tags: mock}
+
+			set, diags := types.SetValueFrom(t.Context(), types.StringType, tt.want)
+			if diags.HasError() {
+				t.Fatalf("unexpected diagnostics: %v", diags)
+			}
+
+			if diags := r.updateTags(t.Context(), "vm-1", set); diags.HasError() {
+				t.Fatalf("unexpected diagnostics: %v", diags)
+			}
+			if fmt.Sprint(mock.calls) != tt.expect {
+				t.Fatalf("expected calls %s, got %v", tt.expect, mock.calls)
+			}
+		})
+	}
+}
+
+func TestUnitServerSshKeys_Diff(t *testing.T) {
+	added, removed := diffServerSshKeys([]string{"key-3", "key-1"}, []string{"key-1", "key-2"})
+	if fmt.Sprint(added, removed) != "[key-3] [key-2]" {
+		t.Fatalf("expected [key-3] added and [key-2] removed, got %v and %v", added, removed)
+	}
+
+	added, removed = diffServerSshKeys(nil, []string{"key-1"})
+	if len(added) != 0 || fmt.Sprint(removed) != "[key-1]" {
+		t.Fatalf("expected every key removed, got %v added and %v removed", added, removed)
+	}
+}
+
+func TestUnitServerCreateMarker_Unique(t *testing.T) {
+	seen := map[string]bool{}
+	for range 1000 {
+		marker, err := serverCreateMarker()
+		if err != nil {
+			t.Fatalf("serverCreateMarker: %v", err)
+		}
+		if seen[marker] {
+			t.Fatalf("marker %s returned twice", marker)
+		}
+		seen[marker] = true
+	}
+}
+
+func TestUnitCreateUserDataScript_Request(t *testing.T) {
+	api := apitest.New(t)
+	api.Handle(http.MethodPost, "/apps/v2/script/add", `{"error":false}`)
+	api.HandleFunc(http.MethodGet, "/apps/v2/scripts", func(apitest.Request) string {
+		var created govpsie.CreateScriptRequest
+		if requests := api.Requests(http.MethodPost, "/apps/v2/script/add"); len(requests) > 0 {
+			_ = json.Unmarshal([]byte(requests[0].Body), &created)
+		}
+		return fmt.Sprintf(`{"error":false,"data":[{"script_name":%q,"identifier":"script-1"}],"total":1}`, created.Name)
+	})
+	s := &serverResource{scripts: api.Client().Scripts}
+
+	identifier, err := s.createUserDataScript(context.Background(), "web.example.com", "#cloud-config\n")
+	if err != nil {
+		t.Fatalf("createUserDataScript: %v", err)
+	}
+	if identifier != "script-1" {
+		t.Errorf("expected identifier script-1, got %q", identifier)
+	}
+
+	requests := api.Requests(http.MethodPost, "/apps/v2/script/add")
+	if len(requests) != 1 {
+		t.Fatalf("expected one create request, got %d", len(requests))
+	}
+	var body map[string]any
+	if err := json.Unmarshal([]byte(requests[0].Body), &body); err != nil {
+		t.Fatalf("decoding request body: %v", err)
+	}
+	if tags, ok := body["tags"].([]any); !ok || len(tags) != 0 {
+		t.Errorf("expected tags to be sent as [], got %v", body["tags"])
+	}
+	if body["scriptType"] != userDataScriptType || body["scriptContent"] != "#cloud-config\n" {
+		t.Errorf("unexpected request body %s", requests[0].Body)
+	}
+}
@@ -0,0 +1,108 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"sort"
+
+	"github.com/vpsie/govpsie"
+)
+
+// ServerSshKeysAPI reads and changes the SSH keys installed on a VM. govpsie
+// can only add a key, with POST /apps/v2/vm/sshkey, so listing and removing
+// keys are made with the client's request helpers directly:
+//
+//   - GET /apps/v2/vm/sshkeys/{vmIdentifier} returns the keys of a VM in the
+//     same shape as the account's key list.
+//   - POST /apps/v2/vm/sshkey/remove takes the same body as the add call,
+//     {"vmIdentifier", "sshKeyIdentifier"}.
+type ServerSshKeysAPI interface {
+	ListServerSshKeys(ctx context.Context, identifierId string) ([]string, error)
+	AddServerSshKey(ctx context.Context, identifierId, sshKeyIdentifier string) error
+	RemoveServerSshKey(ctx context.Context, identifierId, sshKeyIdentifier string) error
+}
+
+type serverSshKeysClient struct {
+	client *govpsie.Client
+}
+
+var _ ServerSshKeysAPI = &serverSshKeysClient{}
+
+// newServerSshKeysClient returns the SSH keys API for client. A server
+// service that handles SSH keys itself, such as the fake API used in tests,
+// is used directly.
+func newServerSshKeysClient(client *govpsie.Client) ServerSshKeysAPI {
+	if sshKeysAPI, ok := client.Server.(ServerSshKeysAPI); ok {
+		return sshKeysAPI
+	}
+
+	return &serverSshKeysClient{client: client}
+}
+
+func (c *serverSshKeysClient) ListServerSshKeys(ctx context.Context, identifierId string) ([]string, error) {
+	req, err := c.client.NewRequest(ctx, http.MethodGet, "/apps/v2/vm/sshkeys/"+identifierId, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	root := new(govpsie.SshKeysListRoot)
+	if err := c.client.Do(ctx, req, root); err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(root.Data))
+	for _, key := range root.Data {
+		keys = append(keys, key.Identifier)
+	}
+	sort.Strings(keys)
+
+	return keys, nil
+}
+
+func (c *serverSshKeysClient) AddServerSshKey(ctx context.Context, identifierId, sshKeyIdentifier string) error {
+	return c.client.Server.AddSsh(ctx, identifierId, sshKeyIdentifier)
+}
+
+func (c *serverSshKeysClient) RemoveServerSshKey(ctx context.Context, identifierId, sshKeyIdentifier string) error {
+	removeReq := struct {
+		VmIdentifier     string `json:"vmIdentifier"`
+		SshKeyIdentifier string `json:"sshKeyIdentifier"`
+	}{
+		VmIdentifier:     identifierId,
+		SshKeyIdentifier: sshKeyIdentifier,
+	}
+
+	req, err := c.client.NewRequest(ctx, http.MethodPost, "/apps/v2/vm/sshkey/remove", removeReq)
+	if err != nil {
+		return err
+	}
+
+	return c.client.Do(ctx, req, nil)
+}
+
+// diffServerSshKeys returns the keys in want that the server does not have
+// and the keys it has that are not in want, both sorted.
+func diffServerSshKeys(want, have []string) (added, removed []string) {
+	current := make(map[string]bool, len(have))
+	for _, key := range have {
+		current[key] = true
+	}
+
+	wanted := make(map[string]bool, len(want))
+	for _, key := range want {
+		wanted[key] = true
+		if !current[key] {
+			added = append(added, key)
+		}
+	}
+
+	for _, key := range have {
+		if !wanted[key] {
+			removed = append(removed, key)
+		}
+	}
+
+	sort.Strings(added)
+	sort.Strings(removed)
+	return added, removed
+}
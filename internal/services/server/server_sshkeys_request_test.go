@@ -0,0 +1,53 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/vpsie/terraform-provider-vpsie/internal/apitest"
+)
+
+func TestUnitServerSshKeysClient_ListServerSshKeys(t *testing.T) {
+	api := apitest.New(t)
+	api.Handle(http.MethodGet, "/apps/v2/vm/sshkeys/vm-1", `{"error":false,"data":[
+		{"id":2,"user_id":1,"name":"laptop","created_on":"2026-10-17 00:00:00","identifier":"key-2","created_by":"user"},
+		{"id":1,"user_id":1,"name":"ci","created_on":"2026-10-17 00:00:00","identifier":"key-1","created_by":"user"}],"total":2}`)
+
+	keys, err := newServerSshKeysClient(api.Client()).ListServerSshKeys(context.Background(), "vm-1")
+	if err != nil {
+		t.Fatalf("ListServerSshKeys: %v", err)
+	}
+	if !slices.Equal(keys, []string{"key-1", "key-2"}) {
+		t.Errorf("expected keys [key-1 key-2], got %v", keys)
+	}
+}
+
+func TestUnitServerSshKeysClient_AddRemove(t *testing.T) {
+	api := apitest.New(t)
+	api.Handle(http.MethodPost, "/apps/v2/vm/sshkey", `{"error":false}`)
+	api.Handle(http.MethodPost, "/apps/v2/vm/sshkey/remove", `{"error":false}`)
+	client := newServerSshKeysClient(api.Client())
+
+	if err := client.AddServerSshKey(context.Background(), "vm-1", "key-1"); err != nil {
+		t.Fatalf("AddServerSshKey: %v", err)
+	}
+	if err := client.RemoveServerSshKey(context.Background(), "vm-1", "key-2"); err != nil {
+		t.Fatalf("RemoveServerSshKey: %v", err)
+	}
+
+	for _, tt := range []struct {
+		path string
+		body string
+	}{
+		{path: "/apps/v2/vm/sshkey", body: `{"vmIdentifier":"vm-1","sshKeyIdentifier":"key-1"}`},
+		{path: "/apps/v2/vm/sshkey/remove", body: `{"vmIdentifier":"vm-1","sshKeyIdentifier":"key-2"}`},
+	} {
+		requests := api.Requests(http.MethodPost, tt.path)
+		if len(requests) != 1 {
+			t.Fatalf("expected one request to %s, got %d", tt.path, len(requests))
+		}
+		apitest.EqualJSON(t, requests[0].Body, tt.body)
+	}
+}
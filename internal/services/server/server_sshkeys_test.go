@@ -0,0 +1,50 @@
+package server_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/vpsie/terraform-provider-vpsie/internal/acctest"
+	"github.com/vpsie/terraform-provider-vpsie/internal/fakeapi"
+	"github.com/vpsie/terraform-provider-vpsie/internal/services/server"
+)
+
+func TestUnitServerResource_SshKeyIDs(t *testing.T) {
+	backend := fakeapi.New()
+	provider, schemas := acctest.FakeProtoV6Server(t, backend, nil)
+	prior, identity := testServerState(t, backend, provider, schemas)
+
+	keys := tftypes.NewValue(tftypes.Set{ElementType: tftypes.String}, []tftypes.Value{
+		tftypes.NewValue(tftypes.String, "key-2"),
+		tftypes.NewValue(tftypes.String, "key-3"),
+	})
+	priorState, config, plan := testServerPlan(t, provider, schemas, prior, identity, map[string]tftypes.Value{
+		"sshkey_id":   tftypes.NewValue(tftypes.String, nil),
+		"ssh_key_ids": keys,
+	})
+	if len(plan.Diagnostics) > 0 {
+		t.Fatalf("plan: %s", plan.Diagnostics[0].Detail)
+	}
+
+	state := testServerApply(t, provider, schemas, priorState, config, plan)
+
+	var identifier string
+	_ = prior["identifier"].As(&identifier)
+	installed, err := backend.Client().Server.(server.ServerSshKeysAPI).ListServerSshKeys(context.Background(), identifier)
+	if err != nil {
+		t.Fatalf("ListServerSshKeys: %v", err)
+	}
+	if fmt.Sprint(installed) != "[key-2 key-3]" {
+		t.Errorf("expected key-1 to be replaced by key-2 and key-3, got %v", installed)
+	}
+
+	refreshed, _ := testServerRead(t, provider, schemas, state)
+	if !refreshed["ssh_key_ids"].Equal(keys) {
+		t.Errorf("expected ssh_key_ids %s after refresh, got %s", keys, refreshed["ssh_key_ids"])
+	}
+	if !refreshed["sshkey_id"].IsNull() {
+		t.Errorf("expected sshkey_id to stay unset, got %s", refreshed["sshkey_id"])
+	}
+}
@@ -0,0 +1,71 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/vpsie/govpsie"
+	"github.com/vpsie/terraform-provider-vpsie/internal/apierror"
+	"github.com/vpsie/terraform-provider-vpsie/internal/waiter"
+)
+
+// Server states reported in VmData.State.
+const (
+	StateRunning = "running"
+	StateStopped = "stopped"
+)
+
+// stateRestarted is the state waitForRestart reports once the server runs
+// again after a request.
+const stateRestarted = "running again"
+
+// restartNotFoundChecks is the number of consecutive polls a server may be
+// missing while waitForRestart waits for it. The API can stop listing a
+// server for a moment while it is resized or reinstalled.
+const restartNotFoundChecks = 3
+
+// waitForState polls the server with the given identifier until its state
+// is target.
+func waitForState(ctx context.Context, client ServerAPI, identifier, target string, timeout time.Duration) (*govpsie.VmData, error) {
+	return (&waiter.Conf[govpsie.VmData]{
+		Target: []string{target},
+		Refresh: func(ctx context.Context) (*govpsie.VmData, string, error) {
+			server, err := client.GetServerByIdentifier(ctx, identifier)
+			if err != nil {
+				return nil, "", err
+			}
+			return server, server.State, nil
+		},
+		Timeout: timeout,
+	}).Wait(ctx)
+}
+
+// waitForRestart polls the server with the given identifier until it runs
+// again after a request that takes it down, such as a reinstall. The API
+// keeps reporting the server as it was until it picks the request up, so a
+// running server only counts once it has reported another state, or once
+// applied, when set, reports that the request took effect. A server that
+// goes missing for a few polls is still waited on, and counts as picked up.
+func waitForRestart(ctx context.Context, client ServerAPI, identifier string, timeout time.Duration, applied func(server *govpsie.VmData) bool) (*govpsie.VmData, error) {
+	picked := false
+	return (&waiter.Conf[govpsie.VmData]{
+		Target: []string{stateRestarted},
+		Refresh: func(ctx context.Context) (*govpsie.VmData, string, error) {
+			server, err := client.GetServerByIdentifier(ctx, identifier)
+			if apierror.IsNotFound(err) {
+				picked = true
+				return nil, "", nil
+			}
+			if err != nil {
+				return nil, "", err
+			}
+			picked = picked || server.State != StateRunning || applied != nil && applied(server)
+			if picked && server.State == StateRunning {
+				return server, stateRestarted, nil
+			}
+			return server, server.State, nil
+		},
+		Timeout:        timeout,
+		NotFoundChecks: restartNotFoundChecks,
+	}).Wait(ctx)
+}
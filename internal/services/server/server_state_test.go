@@ -0,0 +1,30 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/vpsie/govpsie"
+	"github.com/vpsie/terraform-provider-vpsie/internal/apierror"
+)
+
+func TestUnitWaitForRestart_ToleratesMissingServer(t *testing.T) {
+	calls := 0
+	client := &mockServerAPI{
+		GetServerByIdentifierFn: func(ctx context.Context, identifierId string) (*govpsie.VmData, error) {
+			calls++
+			if calls == 1 {
+				return nil, apierror.NotFoundf("server %s not found", identifierId)
+			}
+			return &govpsie.VmData{Identifier: identifierId, State: StateRunning}, nil
+		},
+	}
+
+	server, err := waitForRestart(context.Background(), client, "vm-1", 0, nil)
+	if err != nil {
+		t.Fatalf("expected a server that briefly goes missing to be waited on, got %v", err)
+	}
+	if server.Identifier != "vm-1" || calls != 2 {
+		t.Errorf("expected to return the running server on the second poll, got %+v after %d polls", server, calls)
+	}
+}
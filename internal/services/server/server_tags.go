@@ -0,0 +1,137 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/vpsie/govpsie"
+)
+
+// ServerTagsAPI reads and replaces the tags of a VM. govpsie decodes the
+// vmTags of a server into an empty struct and its EditTag sends no body, so
+// these calls are made with the client's request helpers directly.
+type ServerTagsAPI interface {
+	ListServerTags(ctx context.Context, identifierId string) ([]string, error)
+	AddServerTags(ctx context.Context, identifierId string, tags []string) error
+	SetServerTags(ctx context.Context, identifierId string, tags []string) error
+}
+
+type serverTagsClient struct {
+	client *govpsie.Client
+}
+
+var _ ServerTagsAPI = &serverTagsClient{}
+
+// newServerTagsClient returns the tags API for client. A server service that
+// handles tags itself, such as the fake API used in tests, is used directly.
+func newServerTagsClient(client *govpsie.Client) ServerTagsAPI {
+	if tagsAPI, ok := client.Server.(ServerTagsAPI); ok {
+		return tagsAPI
+	}
+
+	return &serverTagsClient{client: client}
+}
+
+func (c *serverTagsClient) ListServerTags(ctx context.Context, identifierId string) ([]string, error) {
+	req, err := c.client.NewRequest(ctx, http.MethodGet, "/apps/v2/vm/"+identifierId, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	root := struct {
+		Data struct {
+			VmTags []json.RawMessage `json:"vmTags"`
+		} `json:"data"`
+	}{}
+	if err := c.client.Do(ctx, req, &root); err != nil {
+		return nil, err
+	}
+
+	return decodeServerTags(root.Data.VmTags)
+}
+
+func (c *serverTagsClient) AddServerTags(ctx context.Context, identifierId string, tags []string) error {
+	return c.client.Server.AddTags(ctx, identifierId, tags)
+}
+
+func (c *serverTagsClient) SetServerTags(ctx context.Context, identifierId string, tags []string) error {
+	editReq := struct {
+		VmIdentifier string   `json:"vmIdentifier"`
+		Tags         []string `json:"tags"`
+	}{
+		VmIdentifier: identifierId,
+		Tags:         tags,
+	}
+
+	req, err := c.client.NewRequest(ctx, http.MethodPost, "/apps/v2/vm/tags/edit", editReq)
+	if err != nil {
+		return err
+	}
+
+	return c.client.Do(ctx, req, nil)
+}
+
+// decodeServerTags accepts vmTags entries either as plain strings or as
+// objects carrying the tag name, and returns the sorted tag names.
+func decodeServerTags(raw []json.RawMessage) ([]string, error) {
+	tags := make([]string, 0, len(raw))
+	for _, entry := range raw {
+		var name string
+		if err := json.Unmarshal(entry, &name); err == nil {
+			tags = append(tags, name)
+			continue
+		}
+
+		var object struct {
+			Tag     string `json:"tag"`
+			Name    string `json:"name"`
+			TagName string `json:"tagName"`
+		}
+		if err := json.Unmarshal(entry, &object); err != nil {
+			return nil, fmt.Errorf("unexpected server tag %s: %w", entry, err)
+		}
+
+		switch {
+		case object.Tag != "":
+			tags = append(tags, object.Tag)
+		case object.Name != "":
+			tags = append(tags, object.Name)
+		case object.TagName != "":
+			tags = append(tags, object.TagName)
+		default:
+			return nil, fmt.Errorf("unexpected server tag %s", entry)
+		}
+	}
+	sort.Strings(tags)
+
+	return tags, nil
+}
+
+// diffServerTags returns the tags in want that the server does not have and
+// whether any of the server's tags are missing from want.
+func diffServerTags(want, have []string) (added []string, removed bool) {
+	current := make(map[string]bool, len(have))
+	for _, tag := range have {
+		current[tag] = true
+	}
+
+	wanted := make(map[string]bool, len(want))
+	for _, tag := range want {
+		wanted[tag] = true
+		if !current[tag] {
+			added = append(added, tag)
+		}
+	}
+
+	for _, tag := range have {
+		if !wanted[tag] {
+			removed = true
+		}
+	}
+
+	sort.Strings(added)
+	return added, removed
+}
@@ -0,0 +1,64 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vpsie/govpsie"
+	"github.com/vpsie/terraform-provider-vpsie/internal/apierror"
+)
+
+// userDataScriptType is the script type the user data of a server is
+// uploaded as.
+const userDataScriptType = "cloud-init"
+
+// userDataScriptName returns a name for the user data script of a server
+// with the given hostname. Script names cannot contain dots, since the API
+// treats what follows one as an extension.
+func userDataScriptName(hostname string, now time.Time) string {
+	return "terraform-user-data-" + strings.ReplaceAll(hostname, ".", "-") + "-" + strconv.FormatInt(now.UnixNano(), 36)
+}
+
+// createUserDataScript uploads content as the user data script of a server
+// with the given hostname and returns the identifier of the script. The
+// create endpoint does not return the script, so it is looked up by its
+// unique name.
+func (s *serverResource) createUserDataScript(ctx context.Context, hostname, content string) (string, error) {
+	name := userDataScriptName(hostname, time.Now())
+
+	err := s.scripts.CreateScript(ctx, &govpsie.CreateScriptRequest{
+		Name:          name,
+		ScriptContent: content,
+		ScriptType:    userDataScriptType,
+		Tags:          []string{},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	scripts, err := s.scripts.GetScripts(ctx)
+	if err != nil {
+		return "", err
+	}
+	for _, script := range scripts {
+		if strings.Split(script.ScriptName, ".")[0] == name {
+			return script.Identifier, nil
+		}
+	}
+
+	return "", fmt.Errorf("script %s not found after creating it", name)
+}
+
+// deleteUserDataScript deletes the user data script with the given
+// identifier. A script that is already gone is not an error.
+func (s *serverResource) deleteUserDataScript(ctx context.Context, identifier string) error {
+	err := s.scripts.DeleteScript(ctx, identifier)
+	if err != nil && !apierror.IsNotFound(err) {
+		return err
+	}
+
+	return nil
+}
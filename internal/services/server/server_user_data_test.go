@@ -0,0 +1,89 @@
+package server_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/vpsie/terraform-provider-vpsie/internal/acctest"
+	"github.com/vpsie/terraform-provider-vpsie/internal/fakeapi"
+)
+
+func TestUnitServerResource_UserData(t *testing.T) {
+	ctx := context.Background()
+	backend := fakeapi.New()
+	server, schemas := acctest.FakeProtoV6Server(t, backend, map[string]tftypes.Value{
+		"delete_password": tftypes.NewValue(tftypes.String, "provider-password"),
+		"delete_reason":   tftypes.NewValue(tftypes.String, "test"),
+	})
+	serverType := schemas.ResourceSchemas["vpsie_server"].ValueType()
+
+	userData := "#cloud-config\npackages:\n  - nginx\n"
+	config := acctest.ObjectValue(serverType, map[string]tftypes.Value{
+		"project_id":          tftypes.NewValue(tftypes.Number, 1),
+		"resource_identifier": tftypes.NewValue(tftypes.String, "plan-small"),
+		"os_identifier":       tftypes.NewValue(tftypes.String, "ubuntu-24.04"),
+		"dc_identifier":       tftypes.NewValue(tftypes.String, "dc-1"),
+		"hostname":            tftypes.NewValue(tftypes.String, "web.example.com"),
+		"user_data":           tftypes.NewValue(tftypes.String, userData),
+	})
+	noState := tftypes.NewValue(serverType, nil)
+
+	plan, err := server.PlanResourceChange(ctx, &tfprotov6.PlanResourceChangeRequest{
+		TypeName:         "vpsie_server",
+		PriorState:       acctest.DynamicValue(t, serverType, noState),
+		ProposedNewState: acctest.DynamicValue(t, serverType, config),
+		Config:           acctest.DynamicValue(t, serverType, config),
+	})
+	if err != nil {
+		t.Fatalf("PlanResourceChange: %v", err)
+	}
+	if len(plan.Diagnostics) > 0 {
+		t.Fatalf("plan: %s", plan.Diagnostics[0].Detail)
+	}
+
+	state := testServerApply(t, server, schemas, noState, config, plan)
+
+	var scriptID, identifier string
+	_ = state["user_data_script_id"].As(&scriptID)
+	_ = state["identifier"].As(&identifier)
+	if scriptID == "" {
+		t.Fatal("expected user_data_script_id to be set")
+	}
+	if !state["script_id"].IsNull() {
+		t.Errorf("expected script_id to stay unset, got %s", state["script_id"])
+	}
+
+	client := backend.Client()
+	script, err := client.Scripts.GetScript(ctx, scriptID)
+	if err != nil {
+		t.Fatalf("GetScript: %v", err)
+	}
+	if script.Script != userData || script.Type != "cloud-init" {
+		t.Errorf("expected a cloud-init script holding the user data, got type %q with %q", script.Type, script.Script)
+	}
+	vm, err := client.Server.GetServerByIdentifier(ctx, identifier)
+	if err != nil {
+		t.Fatalf("GetServerByIdentifier: %v", err)
+	}
+	if vm.ScriptID == nil || *vm.ScriptID != scriptID {
+		t.Errorf("expected the server to be created with script %s, got %v", scriptID, vm.ScriptID)
+	}
+
+	resp, err := server.ApplyResourceChange(ctx, &tfprotov6.ApplyResourceChangeRequest{
+		TypeName:     "vpsie_server",
+		PriorState:   acctest.DynamicValue(t, serverType, tftypes.NewValue(serverType, state)),
+		PlannedState: acctest.DynamicValue(t, serverType, noState),
+		Config:       acctest.DynamicValue(t, serverType, noState),
+	})
+	if err != nil {
+		t.Fatalf("ApplyResourceChange: %v", err)
+	}
+	if len(resp.Diagnostics) > 0 {
+		t.Fatalf("destroy: %s", resp.Diagnostics[0].Detail)
+	}
+	if _, err := client.Scripts.GetScript(ctx, scriptID); err == nil {
+		t.Error("expected the user data script to be deleted with the server")
+	}
+}
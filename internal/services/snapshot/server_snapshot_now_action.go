@@ -0,0 +1,103 @@
+package snapshot
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	"github.com/hashicorp/terraform-plugin-framework/action/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/vpsie/terraform-provider-vpsie/internal/providerdata"
+)
+
+var (
+	_ action.Action              = &serverSnapshotNowAction{}
+	_ action.ActionWithConfigure = &serverSnapshotNowAction{}
+)
+
+type serverSnapshotNowAction struct {
+	client SnapshotAPI
+}
+
+type serverSnapshotNowActionModel struct {
+	VmIdentifier types.String `tfsdk:"vm_identifier"`
+	Name         types.String `tfsdk:"name"`
+	Note         types.String `tfsdk:"note"`
+}
+
+func NewServerSnapshotNowAction() action.Action {
+	return &serverSnapshotNowAction{}
+}
+
+func (a *serverSnapshotNowAction) Metadata(_ context.Context, req action.MetadataRequest, resp *action.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_server_snapshot_now"
+}
+
+func (a *serverSnapshotNowAction) Schema(_ context.Context, _ action.SchemaRequest, resp *action.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Takes a snapshot of a VPSie server. The snapshot is not managed by Terraform.",
+		Attributes: map[string]schema.Attribute{
+			"vm_identifier": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "The identifier of the server to snapshot.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"name": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "The name of the snapshot. Defaults to `terraform-` followed by the current UTC time.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"note": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "A note to attach to the snapshot.",
+			},
+		},
+	}
+}
+
+func (a *serverSnapshotNowAction) Configure(_ context.Context, req action.ConfigureRequest, resp *action.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*providerdata.Data)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Action Configure Type",
+			fmt.Sprintf("Expected *providerdata.Data, got %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	a.client = data.Client.Snapshot
+}
+
+func (a *serverSnapshotNowAction) Invoke(ctx context.Context, req action.InvokeRequest, resp *action.InvokeResponse) {
+	var data serverSnapshotNowActionModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	name := data.Name.ValueString()
+	if data.Name.IsNull() {
+		name = "terraform-" + time.Now().UTC().Format("20060102-150405")
+	}
+
+	err := a.client.Create(ctx, name, data.VmIdentifier.ValueString(), data.Note.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating snapshot",
+			"couldn't snapshot server "+data.VmIdentifier.ValueString()+", unexpected error: "+err.Error(),
+		)
+		return
+	}
+	resp.SendProgress(action.InvokeProgressEvent{Message: "Requested snapshot " + name + " of server " + data.VmIdentifier.ValueString()})
+}
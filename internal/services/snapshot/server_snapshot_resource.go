@@ -3,11 +3,11 @@ package snapshot
 import (
 	"context"
 	"fmt"
-	"strings"
 
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
@@ -15,12 +15,15 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/vpsie/govpsie"
+	"github.com/vpsie/terraform-provider-vpsie/internal/apierror"
+	"github.com/vpsie/terraform-provider-vpsie/internal/providerdata"
 )
 
 var (
 	_ resource.Resource                = &serverSnapshotResource{}
 	_ resource.ResourceWithConfigure   = &serverSnapshotResource{}
 	_ resource.ResourceWithImportState = &serverSnapshotResource{}
+	_ resource.ResourceWithIdentity    = &serverSnapshotResource{}
 )
 
 type serverSnapshotResource struct {
@@ -53,6 +56,10 @@ type serverSnapshotResourceModel struct {
 	VMSSD        types.Int64  `tfsdk:"vm_ssd"`
 }
 
+type serverSnapshotIdentityModel struct {
+	Identifier types.String `tfsdk:"identifier"`
+}
+
 func NewServerSnapshotResource() resource.Resource {
 	return &serverSnapshotResource{}
 }
@@ -236,22 +243,33 @@ func (s *serverSnapshotResource) Schema(_ context.Context, _ resource.SchemaRequ
 	}
 }
 
+func (s *serverSnapshotResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"identifier": identityschema.StringAttribute{
+				RequiredForImport: true,
+				Description:       "The unique identifier of the server snapshot.",
+			},
+		},
+	}
+}
+
 func (s *serverSnapshotResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
 		return
 	}
 
-	client, ok := req.ProviderData.(*govpsie.Client)
+	data, ok := req.ProviderData.(*providerdata.Data)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configuration Type",
-			fmt.Sprintf("Expected *govpsie.Client, got %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *providerdata.Data, got %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	s.client = client.Snapshot
+	s.client = data.Client.Snapshot
 }
 
 // Create creates the resource and sets the initial Terraform state.
@@ -315,6 +333,8 @@ func (s *serverSnapshotResource) Create(ctx context.Context, req resource.Create
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, serverSnapshotIdentityModel{Identifier: plan.Identifier})...)
 }
 
 // Read refreshes the Terraform state with the latest data.
@@ -328,7 +348,7 @@ func (s *serverSnapshotResource) Read(ctx context.Context, req resource.ReadRequ
 
 	snapshot, err := s.client.Get(ctx, state.Identifier.ValueString())
 	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
+		if apierror.IsNotFound(err) {
 			resp.State.RemoveResource(ctx)
 			return
 		}
@@ -369,6 +389,8 @@ func (s *serverSnapshotResource) Read(ctx context.Context, req resource.ReadRequ
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, serverSnapshotIdentityModel{Identifier: state.Identifier})...)
 }
 
 // Update updates the resource and sets the updated Terraform state on success.
@@ -417,7 +439,7 @@ func (s *serverSnapshotResource) Delete(ctx context.Context, req resource.Delete
 }
 
 func (s *serverSnapshotResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("identifier"), req, resp)
+	resource.ImportStatePassthroughWithIdentity(ctx, path.Root("identifier"), path.Root("identifier"), req, resp)
 }
 
 func (s *serverSnapshotResource) GetSnapshotByName(ctx context.Context, snapshotName string) (*govpsie.Snapshot, error) {
@@ -432,5 +454,5 @@ func (s *serverSnapshotResource) GetSnapshotByName(ctx context.Context, snapshot
 		}
 	}
 
-	return nil, fmt.Errorf("snapshot with name %s not found", snapshotName)
+	return nil, apierror.NotFoundf("snapshot with name %s not found", snapshotName)
 }
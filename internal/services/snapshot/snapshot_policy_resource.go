@@ -3,23 +3,26 @@ package snapshot
 import (
 	"context"
 	"fmt"
-	"strings"
 
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/vpsie/govpsie"
+	"github.com/vpsie/terraform-provider-vpsie/internal/apierror"
+	"github.com/vpsie/terraform-provider-vpsie/internal/providerdata"
 )
 
 var (
 	_ resource.Resource                = &snapshotPolicyResource{}
 	_ resource.ResourceWithConfigure   = &snapshotPolicyResource{}
 	_ resource.ResourceWithImportState = &snapshotPolicyResource{}
+	_ resource.ResourceWithIdentity    = &snapshotPolicyResource{}
 )
 
 type snapshotPolicyResource struct {
@@ -38,6 +41,10 @@ type snapshotPolicyResourceModel struct {
 	Vms        types.List   `tfsdk:"vms"`
 }
 
+type snapshotPolicyIdentityModel struct {
+	Identifier types.String `tfsdk:"identifier"`
+}
+
 func NewSnapshotPolicyResource() resource.Resource {
 	return &snapshotPolicyResource{}
 }
@@ -124,21 +131,32 @@ func (s *snapshotPolicyResource) Schema(_ context.Context, _ resource.SchemaRequ
 	}
 }
 
+func (s *snapshotPolicyResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"identifier": identityschema.StringAttribute{
+				RequiredForImport: true,
+				Description:       "The unique identifier of the snapshot policy.",
+			},
+		},
+	}
+}
+
 func (s *snapshotPolicyResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
 		return
 	}
 
-	client, ok := req.ProviderData.(*govpsie.Client)
+	data, ok := req.ProviderData.(*providerdata.Data)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configuration Type",
-			fmt.Sprintf("Expected *govpsie.Client, got %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *providerdata.Data, got %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 		return
 	}
 
-	s.client = client.Snapshot
+	s.client = data.Client.Snapshot
 }
 
 func (s *snapshotPolicyResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -185,6 +203,8 @@ func (s *snapshotPolicyResource) Create(ctx context.Context, req resource.Create
 
 	diags = resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
+
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, snapshotPolicyIdentityModel{Identifier: plan.Identifier})...)
 }
 
 func (s *snapshotPolicyResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
@@ -197,7 +217,7 @@ func (s *snapshotPolicyResource) Read(ctx context.Context, req resource.ReadRequ
 
 	policy, err := s.client.GetSnapShotPolicy(ctx, state.Identifier.ValueString())
 	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
+		if apierror.IsNotFound(err) {
 			resp.State.RemoveResource(ctx)
 			return
 		}
@@ -228,6 +248,8 @@ func (s *snapshotPolicyResource) Read(ctx context.Context, req resource.ReadRequ
 
 	diags = resp.State.Set(ctx, &state)
 	resp.Diagnostics.Append(diags...)
+
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, snapshotPolicyIdentityModel{Identifier: state.Identifier})...)
 }
 
 func (s *snapshotPolicyResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
@@ -321,7 +343,7 @@ func (s *snapshotPolicyResource) Delete(ctx context.Context, req resource.Delete
 }
 
 func (s *snapshotPolicyResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("identifier"), req, resp)
+	resource.ImportStatePassthroughWithIdentity(ctx, path.Root("identifier"), path.Root("identifier"), req, resp)
 }
 
 func (s *snapshotPolicyResource) GetPolicyByName(ctx context.Context, name string) (*govpsie.SnapShotPolicyListDetail, error) {
@@ -336,5 +358,5 @@ func (s *snapshotPolicyResource) GetPolicyByName(ctx context.Context, name strin
 		}
 	}
 
-	return nil, fmt.Errorf("snapshot policy with name %s not found", name)
+	return nil, apierror.NotFoundf("snapshot policy with name %s not found", name)
 }
@@ -3,11 +3,11 @@ package sshkey
 import (
 	"context"
 	"fmt"
-	"strings"
 
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
@@ -15,12 +15,15 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/vpsie/govpsie"
+	"github.com/vpsie/terraform-provider-vpsie/internal/apierror"
+	"github.com/vpsie/terraform-provider-vpsie/internal/providerdata"
 )
 
 var (
 	_ resource.Resource                = &sshkeyResource{}
 	_ resource.ResourceWithConfigure   = &sshkeyResource{}
 	_ resource.ResourceWithImportState = &sshkeyResource{}
+	_ resource.ResourceWithIdentity    = &sshkeyResource{}
 )
 
 type sshkeyResource struct {
@@ -35,6 +38,13 @@ type sshkeyResourceModel struct {
 	PrivateKey types.String `tfsdk:"private_key"`
 	CreatedOn  types.String `tfsdk:"created_on"`
 	CreatedBy  types.String `tfsdk:"created_by"`
+
+	PrivateKeyWO        types.String `tfsdk:"private_key_wo"`
+	PrivateKeyWOVersion types.Int64  `tfsdk:"private_key_wo_version"`
+}
+
+type sshkeyIdentityModel struct {
+	Identifier types.String `tfsdk:"identifier"`
 }
 
 func NewSshkeyResource() resource.Resource {
@@ -81,16 +91,41 @@ func (s *sshkeyResource) Schema(_ context.Context, _ resource.SchemaRequest, res
 				},
 			},
 			"private_key": schema.StringAttribute{
-				Required:            true,
+				Optional:            true,
 				Sensitive:           true,
-				MarkdownDescription: "The public key content of the SSH key.",
+				MarkdownDescription: "The public key content of the SSH key. Exactly one of `private_key` and `private_key_wo` must be set.",
 				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplace(),
+					stringplanmodifier.RequiresReplaceIf(
+						func(ctx context.Context, req planmodifier.StringRequest, resp *stringplanmodifier.RequiresReplaceIfFuncResponse) {
+							// Moving the key to private_key_wo keeps the SSH key.
+							resp.RequiresReplace = !req.ConfigValue.IsNull()
+						},
+						"Changing the key forces a new resource to be created.",
+						"Changing the key forces a new resource to be created.",
+					),
+				},
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+					stringvalidator.ExactlyOneOf(path.MatchRoot("private_key_wo")),
+					stringvalidator.PreferWriteOnlyAttribute(path.MatchRoot("private_key_wo")),
 				},
+			},
+			"private_key_wo": schema.StringAttribute{
+				Optional:            true,
+				WriteOnly:           true,
+				Sensitive:           true,
+				MarkdownDescription: "Write-only form of `private_key`, which is never stored in state. Requires Terraform 1.11 or later. Change `private_key_wo_version` to replace the key with a new value.",
 				Validators: []validator.String{
 					stringvalidator.LengthAtLeast(1),
 				},
 			},
+			"private_key_wo_version": schema.Int64Attribute{
+				Optional:            true,
+				MarkdownDescription: "Any number; changing it replaces the SSH key with the current value of `private_key_wo`.",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
 			"created_on": schema.StringAttribute{
 				Computed:            true,
 				MarkdownDescription: "The timestamp when the SSH key was created.",
@@ -109,22 +144,33 @@ func (s *sshkeyResource) Schema(_ context.Context, _ resource.SchemaRequest, res
 	}
 }
 
+func (s *sshkeyResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"identifier": identityschema.StringAttribute{
+				RequiredForImport: true,
+				Description:       "The unique identifier of the SSH key.",
+			},
+		},
+	}
+}
+
 func (s *sshkeyResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
 		return
 	}
 
-	client, ok := req.ProviderData.(*govpsie.Client)
+	data, ok := req.ProviderData.(*providerdata.Data)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configuration Type",
-			fmt.Sprintf("Expected *govpsie.Client, got %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *providerdata.Data, got %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	s.client = client.SShKey
+	s.client = data.Client.SShKey
 }
 
 // Create creates the resource and sets the initial Terraform state.
@@ -136,7 +182,19 @@ func (s *sshkeyResource) Create(ctx context.Context, req resource.CreateRequest,
 		return
 	}
 
-	err := s.client.Create(ctx, plan.PrivateKey.ValueString(), plan.Name.ValueString())
+	// Write-only values are only available in the configuration.
+	var privateKeyWO types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("private_key_wo"), &privateKeyWO)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	privateKey := plan.PrivateKey.ValueString()
+	if !privateKeyWO.IsNull() {
+		privateKey = privateKeyWO.ValueString()
+	}
+
+	err := s.client.Create(ctx, privateKey, plan.Name.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error creating sshkey",
@@ -168,6 +226,8 @@ func (s *sshkeyResource) Create(ctx context.Context, req resource.CreateRequest,
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, sshkeyIdentityModel{Identifier: plan.Identifier})...)
 }
 
 // Read refreshes the Terraform state with the latest data.
@@ -181,7 +241,7 @@ func (s *sshkeyResource) Read(ctx context.Context, req resource.ReadRequest, res
 
 	sshkey, err := s.client.Get(ctx, state.Identifier.ValueString())
 	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
+		if apierror.IsNotFound(err) {
 			resp.State.RemoveResource(ctx)
 			return
 		}
@@ -198,7 +258,11 @@ func (s *sshkeyResource) Read(ctx context.Context, req resource.ReadRequest, res
 	state.Id = types.Int64Value(sshkey.Id)
 	state.CreatedOn = types.StringValue(sshkey.CreatedOn)
 	state.CreatedBy = types.StringValue(sshkey.CreatedBy)
-	state.PrivateKey = types.StringValue(sshkey.PrivateKey)
+	// A key created from private_key_wo keeps private_key null. An import
+	// cannot tell, so it reads the key like any other attribute.
+	if !state.PrivateKey.IsNull() || state.Id.IsNull() {
+		state.PrivateKey = types.StringValue(sshkey.PrivateKey)
+	}
 	state.UserId = types.Int64Value(sshkey.UserId)
 
 	diags = resp.State.Set(ctx, &state)
@@ -206,6 +270,8 @@ func (s *sshkeyResource) Read(ctx context.Context, req resource.ReadRequest, res
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, sshkeyIdentityModel{Identifier: state.Identifier})...)
 }
 
 // Update updates the resource and sets the updated Terraform state on success.
@@ -233,7 +299,7 @@ func (s *sshkeyResource) Delete(ctx context.Context, req resource.DeleteRequest,
 }
 
 func (s *sshkeyResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("identifier"), req, resp)
+	resource.ImportStatePassthroughWithIdentity(ctx, path.Root("identifier"), path.Root("identifier"), req, resp)
 }
 
 func (s *sshkeyResource) GetSshkeyByName(ctx context.Context, sshkeyName string) (*govpsie.SShKey, error) {
@@ -248,5 +314,5 @@ func (s *sshkeyResource) GetSshkeyByName(ctx context.Context, sshkeyName string)
 		}
 	}
 
-	return nil, fmt.Errorf("sshkey %s not found", sshkeyName)
+	return nil, apierror.NotFoundf("sshkey %s not found", sshkeyName)
 }
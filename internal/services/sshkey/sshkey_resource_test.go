@@ -3,13 +3,17 @@ package sshkey_test
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"os"
+	"regexp"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
 	"github.com/vpsie/govpsie"
 	"github.com/vpsie/terraform-provider-vpsie/internal/acctest"
+	"github.com/vpsie/terraform-provider-vpsie/internal/fakeapi"
 )
 
 func TestAccSshkeyResource(t *testing.T) {
@@ -71,3 +75,65 @@ func testAccCheckSshkeyResourceDestroy(s *terraform.State) error {
 
 	return nil
 }
+
+func TestUnitSshkeyResource_CreateError(t *testing.T) {
+	backend := fakeapi.New()
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheckTerraformCLI(t) },
+		ProtoV6ProviderFactories: acctest.FakeProtoV6ProviderFactories(backend),
+		Steps: []resource.TestStep{
+			{
+				PreConfig: func() {
+					backend.FailNext("SShKey.Create", fakeapi.Error(http.StatusUnprocessableEntity, "invalid key"))
+				},
+				Config:      testAccSshkeyConfig,
+				ExpectError: regexp.MustCompile(`invalid key`),
+			},
+			{
+				Config: testAccSshkeyConfig,
+				Check:  resource.TestCheckResourceAttrSet("vpsie_sshkey.test", "identifier"),
+			},
+		},
+	})
+}
+
+func TestUnitSshkeyResource_WriteOnly(t *testing.T) {
+	backend := fakeapi.New()
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheckTerraformCLI(t) },
+		ProtoV6ProviderFactories: acctest.FakeProtoV6ProviderFactories(backend),
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_11_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testSshkeyWriteOnlyConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("vpsie_sshkey.test", "identifier"),
+					resource.TestCheckNoResourceAttr("vpsie_sshkey.test", "private_key"),
+					resource.TestCheckNoResourceAttr("vpsie_sshkey.test", "private_key_wo"),
+					func(s *terraform.State) error {
+						keys, err := backend.Client().SShKey.List(context.Background())
+						if err != nil {
+							return err
+						}
+						if len(keys) != 1 || keys[0].PrivateKey != "ssh-ed25519 AAAA write-only" {
+							return fmt.Errorf("expected the write-only key to be sent to the API, got %+v", keys)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+const testSshkeyWriteOnlyConfig = `
+resource "vpsie_sshkey" "test" {
+  name                   = "tf-write-only"
+  private_key_wo         = "ssh-ed25519 AAAA write-only"
+  private_key_wo_version = 1
+}
+`
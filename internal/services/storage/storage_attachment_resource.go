@@ -3,23 +3,26 @@ package storage
 import (
 	"context"
 	"fmt"
-	"strings"
 
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/vpsie/govpsie"
+	"github.com/vpsie/terraform-provider-vpsie/internal/apierror"
+	"github.com/vpsie/terraform-provider-vpsie/internal/providerdata"
 )
 
 var (
 	_ resource.Resource                = &storageAttachmentResource{}
 	_ resource.ResourceWithConfigure   = &storageAttachmentResource{}
 	_ resource.ResourceWithImportState = &storageAttachmentResource{}
+	_ resource.ResourceWithIdentity    = &storageAttachmentResource{}
 )
 
 type storageAttachmentResource struct {
@@ -32,6 +35,10 @@ type storageAttachmentResourceModel struct {
 	VmType            types.String `tfsdk:"vm_type"`
 }
 
+type storageAttachmentIdentityModel struct {
+	StorageIdentifier types.String `tfsdk:"storage_identifier"`
+}
+
 func NewStorageAttachmentResource() resource.Resource {
 	return &storageAttachmentResource{}
 }
@@ -68,22 +75,33 @@ func (s *storageAttachmentResource) Schema(_ context.Context, _ resource.SchemaR
 	}
 }
 
+func (s *storageAttachmentResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"storage_identifier": identityschema.StringAttribute{
+				RequiredForImport: true,
+				Description:       "The identifier of the attached storage volume.",
+			},
+		},
+	}
+}
+
 func (s *storageAttachmentResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
 		return
 	}
 
-	client, ok := req.ProviderData.(*govpsie.Client)
+	data, ok := req.ProviderData.(*providerdata.Data)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configuration Type",
-			fmt.Sprintf("Expected *govpsie.Client, got %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *providerdata.Data, got %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	s.client = client
+	s.client = data.Client
 }
 
 // Create creates the resource and sets the initial Terraform state.
@@ -106,6 +124,8 @@ func (s *storageAttachmentResource) Create(ctx context.Context, req resource.Cre
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, storageAttachmentIdentityModel{StorageIdentifier: plan.StorageIdentifier})...)
 }
 
 // Read refreshes the Terraform state with the latest data.
@@ -119,7 +139,7 @@ func (s *storageAttachmentResource) Read(ctx context.Context, req resource.ReadR
 
 	storage, err := s.client.Storage.Get(ctx, state.StorageIdentifier.ValueString())
 	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
+		if apierror.IsNotFound(err) {
 			resp.State.RemoveResource(ctx)
 			return
 		}
@@ -131,7 +151,10 @@ func (s *storageAttachmentResource) Read(ctx context.Context, req resource.ReadR
 	if storage.Identifier == "" || storage.Identifier != state.StorageIdentifier.ValueString() {
 		tflog.Debug(ctx, "storage attachement was not found, removing from state")
 		resp.State.RemoveResource(ctx)
+		return
 	}
+
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, storageAttachmentIdentityModel{StorageIdentifier: state.StorageIdentifier})...)
 }
 
 // Delete deletes the resource and removes the Terraform state on success.
@@ -151,7 +174,7 @@ func (s *storageAttachmentResource) Delete(ctx context.Context, req resource.Del
 }
 
 func (s *storageAttachmentResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("storage_identifier"), req, resp)
+	resource.ImportStatePassthroughWithIdentity(ctx, path.Root("storage_identifier"), path.Root("storage_identifier"), req, resp)
 }
 
 func (s *storageAttachmentResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
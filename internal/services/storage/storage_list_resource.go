@@ -0,0 +1,59 @@
+package storage
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/list/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/vpsie/govpsie"
+	"github.com/vpsie/terraform-provider-vpsie/internal/listresult"
+)
+
+var (
+	_ list.ListResource              = &storageListResource{}
+	_ list.ListResourceWithConfigure = &storageListResource{}
+)
+
+type storageListResource struct {
+	resource storageResource
+}
+
+func NewStorageListResource() list.ListResource {
+	return &storageListResource{}
+}
+
+func (l *storageListResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	l.resource.Metadata(ctx, req, resp)
+}
+
+func (l *storageListResource) ListResourceConfigSchema(_ context.Context, _ list.ListResourceSchemaRequest, resp *list.ListResourceSchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Lists the VPSie storage volumes in the account, for use with `terraform query`.",
+	}
+}
+
+func (l *storageListResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	l.resource.Configure(ctx, req, resp)
+}
+
+func (l *storageListResource) List(ctx context.Context, req list.ListRequest, stream *list.ListResultsStream) {
+	storages, err := l.resource.client.ListAll(ctx, nil)
+	if err != nil {
+		var diags diag.Diagnostics
+		diags.AddError(
+			"Error listing storages",
+			"Could not list storages: "+err.Error(),
+		)
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	stream.Results = listresult.Stream(req, storages, func(storage govpsie.Storage) list.ListResult {
+		return listresult.New(ctx, req, &l.resource, storage.Name, storageIdentityModel{
+			Identifier: types.StringValue(storage.Identifier),
+		})
+	})
+}
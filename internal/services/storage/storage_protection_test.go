@@ -0,0 +1,20 @@
+package storage_test
+
+import (
+	"testing"
+
+	"github.com/vpsie/terraform-provider-vpsie/internal/acctest"
+	"github.com/vpsie/terraform-provider-vpsie/internal/fakeapi"
+)
+
+func TestUnitStorageResource_DeletionProtection(t *testing.T) {
+	server, schemas := acctest.FakeProtoV6Server(t, fakeapi.New(), nil)
+	config := testStorageConfig()
+
+	created, diags := acctest.ApplyResource(t, server, schemas, "vpsie_storage", nil, config)
+	if len(diags) > 0 {
+		t.Fatalf("create: %s: %s", diags[0].Summary, diags[0].Detail)
+	}
+
+	acctest.CheckDeletionProtection(t, server, schemas, "vpsie_storage", created, config)
+}
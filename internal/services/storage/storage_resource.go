@@ -3,12 +3,13 @@ package storage
 import (
 	"context"
 	"fmt"
-	"strings"
+	"strconv"
 
 	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
@@ -16,12 +17,16 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/vpsie/govpsie"
+	"github.com/vpsie/terraform-provider-vpsie/internal/apierror"
+	"github.com/vpsie/terraform-provider-vpsie/internal/deletion"
+	"github.com/vpsie/terraform-provider-vpsie/internal/providerdata"
 )
 
 var (
 	_ resource.Resource                = &storageResource{}
 	_ resource.ResourceWithConfigure   = &storageResource{}
 	_ resource.ResourceWithImportState = &storageResource{}
+	_ resource.ResourceWithIdentity    = &storageResource{}
 )
 
 type storageResource struct {
@@ -29,27 +34,32 @@ type storageResource struct {
 }
 
 type storageResourceModel struct {
-	ID             types.Int64  `tfsdk:"id"`
-	Name           types.String `tfsdk:"name"`
-	Description    types.String `tfsdk:"description"`
-	UserID         types.Int64  `tfsdk:"user_id"`
-	BoxID          types.Int64  `tfsdk:"box_id"`
-	Identifier     types.String `tfsdk:"identifier"`
-	UserTemplateID types.Int64  `tfsdk:"user_template_id"`
-	StorageType    types.String `tfsdk:"storage_type"`
-	DiskFormat     types.String `tfsdk:"disk_format"`
-	IsAutomatic    types.Int64  `tfsdk:"is_automatic"`
-	Size           types.Int64  `tfsdk:"size"`
-	StorageID      types.Int64  `tfsdk:"storage_id"`
-	DiskKey        types.String `tfsdk:"disk_key"`
-	CreatedOn      types.String `tfsdk:"created_on"`
-	VmIdentifier   types.String `tfsdk:"vm_identifier"`
-	Hostname       types.String `tfsdk:"hostname"`
-	OsIdentifier   types.String `tfsdk:"os_identifier"`
-	State          types.String `tfsdk:"state"`
-	DcIdentifier   types.String `tfsdk:"dc_identifier"`
-	BusDevice      types.String `tfsdk:"bus_device"`
-	BusNumber      types.Int64  `tfsdk:"bus_number"`
+	ID                 types.Int64  `tfsdk:"id"`
+	Name               types.String `tfsdk:"name"`
+	Description        types.String `tfsdk:"description"`
+	UserID             types.Int64  `tfsdk:"user_id"`
+	BoxID              types.Int64  `tfsdk:"box_id"`
+	Identifier         types.String `tfsdk:"identifier"`
+	UserTemplateID     types.Int64  `tfsdk:"user_template_id"`
+	StorageType        types.String `tfsdk:"storage_type"`
+	DiskFormat         types.String `tfsdk:"disk_format"`
+	IsAutomatic        types.Int64  `tfsdk:"is_automatic"`
+	Size               types.Int64  `tfsdk:"size"`
+	StorageID          types.Int64  `tfsdk:"storage_id"`
+	DiskKey            types.String `tfsdk:"disk_key"`
+	CreatedOn          types.String `tfsdk:"created_on"`
+	VmIdentifier       types.String `tfsdk:"vm_identifier"`
+	Hostname           types.String `tfsdk:"hostname"`
+	OsIdentifier       types.String `tfsdk:"os_identifier"`
+	State              types.String `tfsdk:"state"`
+	DcIdentifier       types.String `tfsdk:"dc_identifier"`
+	BusDevice          types.String `tfsdk:"bus_device"`
+	BusNumber          types.Int64  `tfsdk:"bus_number"`
+	DeletionProtection types.Bool   `tfsdk:"deletion_protection"`
+}
+
+type storageIdentityModel struct {
+	Identifier types.String `tfsdk:"identifier"`
 }
 
 func NewStorageResource() resource.Resource {
@@ -212,6 +222,18 @@ func (s *storageResource) Schema(_ context.Context, _ resource.SchemaRequest, re
 					int64planmodifier.UseStateForUnknown(),
 				},
 			},
+			"deletion_protection": deletion.ProtectionAttribute("storage volume"),
+		},
+	}
+}
+
+func (s *storageResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"identifier": identityschema.StringAttribute{
+				RequiredForImport: true,
+				Description:       "The unique identifier of the storage volume.",
+			},
 		},
 	}
 }
@@ -221,17 +243,17 @@ func (s *storageResource) Configure(_ context.Context, req resource.ConfigureReq
 		return
 	}
 
-	client, ok := req.ProviderData.(*govpsie.Client)
+	data, ok := req.ProviderData.(*providerdata.Data)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configuration Type",
-			fmt.Sprintf("Expected *govpsie.Client, got %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *providerdata.Data, got %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	s.client = client.Storage
+	s.client = data.Client.Storage
 }
 
 // Create creates the resource and sets the initial Terraform state.
@@ -286,6 +308,8 @@ func (s *storageResource) Create(ctx context.Context, req resource.CreateRequest
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, storageIdentityModel{Identifier: plan.Identifier})...)
 }
 
 // Read refreshes the Terraform state with the latest data.
@@ -299,7 +323,7 @@ func (s *storageResource) Read(ctx context.Context, req resource.ReadRequest, re
 
 	storage, err := s.GetVolumeByIdentifier(ctx, state.Identifier.ValueString())
 	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
+		if apierror.IsNotFound(err) {
 			resp.State.RemoveResource(ctx)
 			return
 		}
@@ -337,25 +361,29 @@ func (s *storageResource) Read(ctx context.Context, req resource.ReadRequest, re
 		return
 	}
 
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, storageIdentityModel{Identifier: state.Identifier})...)
 }
 
 // Update updates the resource and sets the updated Terraform state on success.
 func (s *storageResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	var nameState, sizeState, namePlan, sizePlan, identifier types.String
-
-	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("name"), &nameState)...)
-	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("size"), &sizeState)...)
-	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("identifier"), &identifier)...)
-
-	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("name"), &namePlan)...)
-	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("size"), &sizePlan)...)
+	var plan storageResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
+	var state storageResourceModel
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	if !namePlan.Equal(nameState) {
-		err := s.client.UpdateName(ctx, identifier.ValueString(), namePlan.ValueString())
+	identifier := state.Identifier.ValueString()
+
+	if !plan.Name.Equal(state.Name) {
+		err := s.client.UpdateName(ctx, identifier, plan.Name.ValueString())
 		if err != nil {
 			resp.Diagnostics.AddError(
 				"Error updating storage name",
@@ -363,12 +391,10 @@ func (s *storageResource) Update(ctx context.Context, req resource.UpdateRequest
 			)
 			return
 		}
-
-		resp.State.SetAttribute(ctx, path.Root("name"), namePlan)
 	}
 
-	if !sizePlan.Equal(sizeState) {
-		err := s.client.UpdateSize(ctx, identifier.ValueString(), sizePlan.ValueString())
+	if !plan.Size.Equal(state.Size) {
+		err := s.client.UpdateSize(ctx, identifier, strconv.FormatInt(plan.Size.ValueInt64(), 10))
 		if err != nil {
 			resp.Diagnostics.AddError(
 				"Error updating storage size",
@@ -376,9 +402,36 @@ func (s *storageResource) Update(ctx context.Context, req resource.UpdateRequest
 			)
 			return
 		}
+	}
 
-		resp.State.SetAttribute(ctx, path.Root("size"), sizePlan)
+	// Attributes the API computes are read back when the plan left them
+	// unknown.
+	storage, err := s.GetVolumeByIdentifier(ctx, identifier)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error reading vpsie storage",
+			"couldn't read vpsie storage identifier "+identifier+": "+err.Error(),
+		)
+		return
+	}
+	if plan.IsAutomatic.IsUnknown() {
+		plan.IsAutomatic = types.Int64Value(int64(storage.IsAutomatic))
+	}
+	if plan.VmIdentifier.IsUnknown() {
+		plan.VmIdentifier = types.StringValue(storage.VmIdentifier)
 	}
+	if plan.Hostname.IsUnknown() {
+		plan.Hostname = types.StringValue(storage.Hostname)
+	}
+	if plan.OsIdentifier.IsUnknown() {
+		plan.OsIdentifier = types.StringValue(storage.OsIdentifier)
+	}
+	if plan.State.IsUnknown() {
+		plan.State = types.StringValue(storage.State)
+	}
+
+	diags = resp.State.Set(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
 }
 
 // Delete deletes the resource and removes the Terraform state on success.
@@ -390,6 +443,11 @@ func (s *storageResource) Delete(ctx context.Context, req resource.DeleteRequest
 		return
 	}
 
+	resp.Diagnostics.Append(deletion.CheckProtection(state.DeletionProtection, "storage volume", state.Identifier.ValueString())...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	err := s.client.Delete(ctx, state.Identifier.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError(
@@ -402,7 +460,7 @@ func (s *storageResource) Delete(ctx context.Context, req resource.DeleteRequest
 }
 
 func (s *storageResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("identifier"), req, resp)
+	resource.ImportStatePassthroughWithIdentity(ctx, path.Root("identifier"), path.Root("identifier"), req, resp)
 }
 
 func (s *storageResource) GetVolumeByName(ctx context.Context, name string) (*govpsie.Storage, error) {
@@ -417,7 +475,7 @@ func (s *storageResource) GetVolumeByName(ctx context.Context, name string) (*go
 		}
 	}
 
-	return nil, fmt.Errorf("volume with name %s not found", name)
+	return nil, apierror.NotFoundf("volume with name %s not found", name)
 }
 
 func (s *storageResource) GetVolumeByIdentifier(ctx context.Context, identifier string) (*govpsie.Storage, error) {
@@ -432,5 +490,5 @@ func (s *storageResource) GetVolumeByIdentifier(ctx context.Context, identifier
 		}
 	}
 
-	return nil, fmt.Errorf("volume with identifier %s not found", identifier)
+	return nil, apierror.NotFoundf("volume with identifier %s not found", identifier)
 }
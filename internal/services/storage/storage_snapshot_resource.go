@@ -3,11 +3,11 @@ package storage
 import (
 	"context"
 	"fmt"
-	"strings"
 
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
@@ -16,12 +16,15 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/types"
 
 	"github.com/vpsie/govpsie"
+	"github.com/vpsie/terraform-provider-vpsie/internal/apierror"
+	"github.com/vpsie/terraform-provider-vpsie/internal/providerdata"
 )
 
 var (
 	_ resource.Resource                = &storageSnapshotResource{}
 	_ resource.ResourceWithConfigure   = &storageSnapshotResource{}
 	_ resource.ResourceWithImportState = &storageSnapshotResource{}
+	_ resource.ResourceWithIdentity    = &storageSnapshotResource{}
 )
 
 type storageSnapshotResource struct {
@@ -47,6 +50,10 @@ type storageSnapshotResourceModel struct {
 	Type              types.String `tfsdk:"type"`
 }
 
+type storageSnapshotIdentityModel struct {
+	Identifier types.String `tfsdk:"identifier"`
+}
+
 func NewStorageSnapshotResource() resource.Resource {
 	return &storageSnapshotResource{}
 }
@@ -181,22 +188,33 @@ func (s *storageSnapshotResource) Schema(_ context.Context, _ resource.SchemaReq
 	}
 }
 
+func (s *storageSnapshotResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"identifier": identityschema.StringAttribute{
+				RequiredForImport: true,
+				Description:       "The unique identifier of the storage snapshot.",
+			},
+		},
+	}
+}
+
 func (s *storageSnapshotResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
 		return
 	}
 
-	client, ok := req.ProviderData.(*govpsie.Client)
+	data, ok := req.ProviderData.(*providerdata.Data)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configuration Type",
-			fmt.Sprintf("Expected *govpsie.Client, got %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *providerdata.Data, got %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	s.client = client
+	s.client = data.Client
 }
 
 // Create creates the resource and sets the initial Terraform state.
@@ -239,6 +257,8 @@ func (s *storageSnapshotResource) Create(ctx context.Context, req resource.Creat
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, storageSnapshotIdentityModel{Identifier: plan.Identifier})...)
 }
 
 // Read refreshes the Terraform state with the latest data.
@@ -252,7 +272,7 @@ func (s *storageSnapshotResource) Read(ctx context.Context, req resource.ReadReq
 
 	snapshot, err := s.GetStorageSnapshotByIdentifier(ctx, state.Identifier.ValueString())
 	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
+		if apierror.IsNotFound(err) {
 			resp.State.RemoveResource(ctx)
 			return
 		}
@@ -284,6 +304,8 @@ func (s *storageSnapshotResource) Read(ctx context.Context, req resource.ReadReq
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, storageSnapshotIdentityModel{Identifier: state.Identifier})...)
 }
 
 // Delete deletes the resource and removes the Terraform state on success.
@@ -343,7 +365,7 @@ func (s *storageSnapshotResource) Update(ctx context.Context, req resource.Updat
 }
 
 func (s *storageSnapshotResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("identifier"), req, resp)
+	resource.ImportStatePassthroughWithIdentity(ctx, path.Root("identifier"), path.Root("identifier"), req, resp)
 }
 
 func (s *storageSnapshotResource) GetStorageSnapshot(ctx context.Context, name string) (govpsie.StorageSnapShot, error) {
@@ -358,7 +380,7 @@ func (s *storageSnapshotResource) GetStorageSnapshot(ctx context.Context, name s
 		}
 	}
 
-	return govpsie.StorageSnapShot{}, fmt.Errorf("snapshot not found")
+	return govpsie.StorageSnapShot{}, apierror.NotFoundf("snapshot not found")
 }
 
 func (s *storageSnapshotResource) GetStorageSnapshotByIdentifier(ctx context.Context, identifier string) (govpsie.StorageSnapShot, error) {
@@ -373,5 +395,5 @@ func (s *storageSnapshotResource) GetStorageSnapshotByIdentifier(ctx context.Con
 		}
 	}
 
-	return govpsie.StorageSnapShot{}, fmt.Errorf("snapshot not found")
+	return govpsie.StorageSnapShot{}, apierror.NotFoundf("snapshot not found")
 }
@@ -0,0 +1,49 @@
+package storage_test
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/vpsie/terraform-provider-vpsie/internal/acctest"
+	"github.com/vpsie/terraform-provider-vpsie/internal/fakeapi"
+)
+
+// testStorageConfig returns a vpsie_storage configuration for a 10 GB
+// volume.
+func testStorageConfig() map[string]tftypes.Value {
+	return map[string]tftypes.Value{
+		"name":          tftypes.NewValue(tftypes.String, "data"),
+		"dc_identifier": tftypes.NewValue(tftypes.String, "dc-1"),
+		"description":   tftypes.NewValue(tftypes.String, "data volume"),
+		"size":          tftypes.NewValue(tftypes.Number, 10),
+		"storage_type":  tftypes.NewValue(tftypes.String, "standard"),
+		"disk_format":   tftypes.NewValue(tftypes.String, "EXT4"),
+	}
+}
+
+func TestUnitStorageResource_UpdateSize(t *testing.T) {
+	server, schemas := acctest.FakeProtoV6Server(t, fakeapi.New(), nil)
+	config := testStorageConfig()
+
+	created, diags := acctest.ApplyResource(t, server, schemas, "vpsie_storage", nil, config)
+	if len(diags) > 0 {
+		t.Fatalf("create: %s: %s", diags[0].Summary, diags[0].Detail)
+	}
+
+	config["name"] = tftypes.NewValue(tftypes.String, "data-2")
+	config["size"] = tftypes.NewValue(tftypes.Number, 20)
+	updated, diags := acctest.ApplyResource(t, server, schemas, "vpsie_storage", created, config)
+	if len(diags) > 0 {
+		t.Fatalf("update: %s: %s", diags[0].Summary, diags[0].Detail)
+	}
+
+	read, diags := acctest.ReadResource(t, server, schemas, "vpsie_storage", updated)
+	if len(diags) > 0 || read == nil {
+		t.Fatalf("read: %v", diags)
+	}
+	for attr, want := range map[string]tftypes.Value{"name": config["name"], "size": config["size"]} {
+		if !read.State[attr].Equal(want) {
+			t.Errorf("expected %s %s, got %s", attr, want, read.State[attr])
+		}
+	}
+}
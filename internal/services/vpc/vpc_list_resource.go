@@ -0,0 +1,59 @@
+package vpc
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/list/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/vpsie/govpsie"
+	"github.com/vpsie/terraform-provider-vpsie/internal/listresult"
+)
+
+var (
+	_ list.ListResource              = &vpcListResource{}
+	_ list.ListResourceWithConfigure = &vpcListResource{}
+)
+
+type vpcListResource struct {
+	resource vpcResource
+}
+
+func NewVpcListResource() list.ListResource {
+	return &vpcListResource{}
+}
+
+func (l *vpcListResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	l.resource.Metadata(ctx, req, resp)
+}
+
+func (l *vpcListResource) ListResourceConfigSchema(_ context.Context, _ list.ListResourceSchemaRequest, resp *list.ListResourceSchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Lists the VPSie VPCs in the account, for use with `terraform query`.",
+	}
+}
+
+func (l *vpcListResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	l.resource.Configure(ctx, req, resp)
+}
+
+func (l *vpcListResource) List(ctx context.Context, req list.ListRequest, stream *list.ListResultsStream) {
+	vpcs, err := l.resource.client.List(ctx, nil)
+	if err != nil {
+		var diags diag.Diagnostics
+		diags.AddError(
+			"Error listing VPCs",
+			"Could not list VPCs: "+err.Error(),
+		)
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	stream.Results = listresult.Stream(req, vpcs, func(vpc govpsie.VPC) list.ListResult {
+		return listresult.New(ctx, req, &l.resource, vpc.Name, vpcIdentityModel{
+			ID: types.Int64Value(int64(vpc.ID)),
+		})
+	})
+}
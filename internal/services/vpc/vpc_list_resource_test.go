@@ -0,0 +1,42 @@
+package vpc_test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/vpsie/govpsie"
+	"github.com/vpsie/terraform-provider-vpsie/internal/acctest"
+	"github.com/vpsie/terraform-provider-vpsie/internal/fakeapi"
+)
+
+func TestUnitVpcListResource(t *testing.T) {
+	ctx := context.Background()
+	backend := fakeapi.New()
+	client := backend.Client()
+	err := client.VPC.CreateVpc(ctx, &govpsie.CreateVpcReq{Name: "private", NetworkRange: "10.1.0.0", NetworkSize: "24"})
+	if err != nil {
+		t.Fatalf("CreateVpc: %v", err)
+	}
+	vpcs, _ := client.VPC.List(ctx, nil)
+	server, schemas := acctest.FakeProtoV6Server(t, backend, nil)
+
+	results, diags := acctest.ListResource(t, server, schemas, "vpsie_vpc", nil, true)
+	if len(diags) > 0 {
+		t.Fatalf("list: %s", diags[0].Detail)
+	}
+	if len(results) != 1 || results[0].DisplayName != "private" {
+		t.Fatalf("expected the private VPC, got %+v", results)
+	}
+
+	var id big.Float
+	var networkRange string
+	_ = results[0].Identity["id"].As(&id)
+	_ = results[0].Resource["network_range"].As(&networkRange)
+	if got, _ := id.Int64(); got != int64(vpcs[0].ID) {
+		t.Errorf("expected identity id %d, got %d", vpcs[0].ID, got)
+	}
+	if networkRange != "10.1.0.0" {
+		t.Errorf("expected the resource data to be read, got network_range %q", networkRange)
+	}
+}
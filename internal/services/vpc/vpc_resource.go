@@ -4,11 +4,11 @@ import (
 	"context"
 	"fmt"
 	"strconv"
-	"strings"
 
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
@@ -16,12 +16,15 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/vpsie/govpsie"
+	"github.com/vpsie/terraform-provider-vpsie/internal/apierror"
+	"github.com/vpsie/terraform-provider-vpsie/internal/providerdata"
 )
 
 var (
 	_ resource.Resource                = &vpcResource{}
 	_ resource.ResourceWithConfigure   = &vpcResource{}
 	_ resource.ResourceWithImportState = &vpcResource{}
+	_ resource.ResourceWithIdentity    = &vpcResource{}
 )
 
 type vpcResource struct {
@@ -56,6 +59,10 @@ type vpcResourceModel struct {
 	DcIdentifier     types.String `tfsdk:"dc_identifier"`
 }
 
+type vpcIdentityModel struct {
+	ID types.Int64 `tfsdk:"id"`
+}
+
 func NewVpcResource() resource.Resource {
 	return &vpcResource{}
 }
@@ -256,22 +263,33 @@ func (v *vpcResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *
 	}
 }
 
+func (v *vpcResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"id": identityschema.Int64Attribute{
+				RequiredForImport: true,
+				Description:       "The numeric ID of the VPC.",
+			},
+		},
+	}
+}
+
 func (v *vpcResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
 		return
 	}
 
-	client, ok := req.ProviderData.(*govpsie.Client)
+	data, ok := req.ProviderData.(*providerdata.Data)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configuration Type",
-			fmt.Sprintf("Expected *govpsie.Client, got %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *providerdata.Data, got %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	v.client = client.VPC
+	v.client = data.Client.VPC
 }
 
 // Create creates the resource and sets the initial Terraform state.
@@ -333,6 +351,8 @@ func (v *vpcResource) Create(ctx context.Context, req resource.CreateRequest, re
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, vpcIdentityModel{ID: plan.ID})...)
 }
 
 // Read refreshes the Terraform state with the latest data.
@@ -346,7 +366,7 @@ func (v *vpcResource) Read(ctx context.Context, req resource.ReadRequest, resp *
 
 	vpc, err := v.client.Get(ctx, state.ID.String())
 	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
+		if apierror.IsNotFound(err) {
 			resp.State.RemoveResource(ctx)
 			return
 		}
@@ -384,6 +404,8 @@ func (v *vpcResource) Read(ctx context.Context, req resource.ReadRequest, resp *
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, vpcIdentityModel{ID: state.ID})...)
 }
 
 // Update updates the resource and sets the updated Terraform state on success.
@@ -411,7 +433,25 @@ func (v *vpcResource) Delete(ctx context.Context, req resource.DeleteRequest, re
 }
 
 func (v *vpcResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	if req.ID == "" {
+		var identity vpcIdentityModel
+		resp.Diagnostics.Append(req.Identity.Get(ctx, &identity)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), identity.ID)...)
+		return
+	}
+
+	id, err := strconv.ParseInt(req.ID, 10, 64)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Invalid import ID",
+			fmt.Sprintf("expected the numeric ID of a VPC, got: %s", req.ID),
+		)
+		return
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
 }
 
 func (v *vpcResource) GetVpcByName(ctx context.Context, name string) (*govpsie.VPC, error) {
@@ -426,5 +466,5 @@ func (v *vpcResource) GetVpcByName(ctx context.Context, name string) (*govpsie.V
 		}
 	}
 
-	return nil, fmt.Errorf("vpc with name %s not found", name)
+	return nil, apierror.NotFoundf("vpc with name %s not found", name)
 }
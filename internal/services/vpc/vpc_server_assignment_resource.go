@@ -10,6 +10,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
@@ -17,12 +18,14 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/vpsie/govpsie"
+	"github.com/vpsie/terraform-provider-vpsie/internal/providerdata"
 )
 
 var (
 	_ resource.Resource                = &vpcServerAssignmentResource{}
 	_ resource.ResourceWithConfigure   = &vpcServerAssignmentResource{}
 	_ resource.ResourceWithImportState = &vpcServerAssignmentResource{}
+	_ resource.ResourceWithIdentity    = &vpcServerAssignmentResource{}
 )
 
 type vpcServerAssignmentResource struct {
@@ -38,6 +41,11 @@ type vpcServerAssignmentResourceModel struct {
 	PrivateIPID  types.Int64  `tfsdk:"private_ip_id"`
 }
 
+type vpcServerAssignmentIdentityModel struct {
+	VmIdentifier types.String `tfsdk:"vm_identifier"`
+	VpcID        types.Int64  `tfsdk:"vpc_id"`
+}
+
 func NewVpcServerAssignmentResource() resource.Resource {
 	return &vpcServerAssignmentResource{}
 }
@@ -98,22 +106,37 @@ func (v *vpcServerAssignmentResource) Schema(_ context.Context, _ resource.Schem
 	}
 }
 
+func (v *vpcServerAssignmentResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"vm_identifier": identityschema.StringAttribute{
+				RequiredForImport: true,
+				Description:       "The identifier of the server assigned to the VPC.",
+			},
+			"vpc_id": identityschema.Int64Attribute{
+				RequiredForImport: true,
+				Description:       "The numeric ID of the VPC.",
+			},
+		},
+	}
+}
+
 func (v *vpcServerAssignmentResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
 		return
 	}
 
-	client, ok := req.ProviderData.(*govpsie.Client)
+	data, ok := req.ProviderData.(*providerdata.Data)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configuration Type",
-			fmt.Sprintf("Expected *govpsie.Client, got %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *providerdata.Data, got %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 		return
 	}
 
-	v.client = client.VPC
-	v.ipClient = client.IP
+	v.client = data.Client.VPC
+	v.ipClient = data.Client.IP
 }
 
 func (v *vpcServerAssignmentResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -151,6 +174,8 @@ func (v *vpcServerAssignmentResource) Create(ctx context.Context, req resource.C
 
 	diags = resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
+
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, vpcServerAssignmentIdentityModel{VmIdentifier: plan.VmIdentifier, VpcID: plan.VpcID})...)
 }
 
 func (v *vpcServerAssignmentResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
@@ -184,6 +209,8 @@ func (v *vpcServerAssignmentResource) Read(ctx context.Context, req resource.Rea
 
 	diags = resp.State.Set(ctx, &state)
 	resp.Diagnostics.Append(diags...)
+
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, vpcServerAssignmentIdentityModel{VmIdentifier: state.VmIdentifier, VpcID: state.VpcID})...)
 }
 
 func (v *vpcServerAssignmentResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
@@ -191,27 +218,39 @@ func (v *vpcServerAssignmentResource) Update(ctx context.Context, req resource.U
 }
 
 func (v *vpcServerAssignmentResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	parts := strings.Split(req.ID, "/")
-	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
-		resp.Diagnostics.AddError(
-			"Invalid Import ID",
-			fmt.Sprintf("Expected import identifier with format: <vm_identifier>/<vpc_id>. Got: %s", req.ID),
-		)
-		return
-	}
+	var identity vpcServerAssignmentIdentityModel
+	if req.ID == "" {
+		resp.Diagnostics.Append(req.Identity.Get(ctx, &identity)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	} else {
+		parts := strings.Split(req.ID, "/")
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			resp.Diagnostics.AddError(
+				"Invalid Import ID",
+				fmt.Sprintf("Expected import identifier with format: <vm_identifier>/<vpc_id>. Got: %s", req.ID),
+			)
+			return
+		}
 
-	vpcID, err := strconv.ParseInt(parts[1], 10, 64)
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Invalid Import ID",
-			fmt.Sprintf("Expected vpc_id to be an integer, got: %s", parts[1]),
-		)
-		return
+		vpcID, err := strconv.ParseInt(parts[1], 10, 64)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Invalid Import ID",
+				fmt.Sprintf("Expected vpc_id to be an integer, got: %s", parts[1]),
+			)
+			return
+		}
+		identity = vpcServerAssignmentIdentityModel{
+			VmIdentifier: types.StringValue(parts[0]),
+			VpcID:        types.Int64Value(vpcID),
+		}
 	}
 
-	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("vm_identifier"), parts[0])...)
-	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("vpc_id"), vpcID)...)
-	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("vm_identifier"), identity.VmIdentifier)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("vpc_id"), identity.VpcID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), fmt.Sprintf("%s/%d", identity.VmIdentifier.ValueString(), identity.VpcID.ValueInt64()))...)
 }
 
 func (v *vpcServerAssignmentResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
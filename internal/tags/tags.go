@@ -0,0 +1,75 @@
+// Package tags merges the provider's default_tags into the tags that
+// resources send to the VPSie API.
+package tags
+
+import (
+	"context"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Merge returns the sorted union of a resource's own tags and the default
+// tags. Tags are plain strings, so a resource tag equal to a default tag
+// simply appears once.
+func Merge(resourceTags, defaultTags []string) []string {
+	return normalize(append(append([]string{}, resourceTags...), defaultTags...))
+}
+
+// ResourceTags splits the tags read from the API back into the tags that
+// belong in a resource's own tags attribute: everything that is not a
+// default tag, plus default tags the resource also declares itself.
+func ResourceTags(apiTags, defaultTags, resourceTags []string) []string {
+	isDefault := make(map[string]bool, len(defaultTags))
+	for _, tag := range defaultTags {
+		isDefault[tag] = true
+	}
+
+	declared := make(map[string]bool, len(resourceTags))
+	for _, tag := range resourceTags {
+		declared[tag] = true
+	}
+
+	own := []string{}
+	for _, tag := range apiTags {
+		if !isDefault[tag] || declared[tag] {
+			own = append(own, tag)
+		}
+	}
+
+	return normalize(own)
+}
+
+// MergeSet is Merge for a planned tags set. An unknown set stays unknown.
+func MergeSet(ctx context.Context, resourceTags types.Set, defaultTags []string) (types.Set, diag.Diagnostics) {
+	if resourceTags.IsUnknown() {
+		return types.SetUnknown(types.StringType), nil
+	}
+
+	var own []string
+	diags := resourceTags.ElementsAs(ctx, &own, false)
+	if diags.HasError() {
+		return types.SetUnknown(types.StringType), diags
+	}
+
+	merged, d := types.SetValueFrom(ctx, types.StringType, Merge(own, defaultTags))
+	diags.Append(d...)
+
+	return merged, diags
+}
+
+func normalize(tags []string) []string {
+	seen := make(map[string]bool, len(tags))
+	out := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		out = append(out, tag)
+	}
+	sort.Strings(out)
+
+	return out
+}
@@ -0,0 +1,61 @@
+package tags
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestUnitTags_Merge(t *testing.T) {
+	got := Merge([]string{"web", "env-prod"}, []string{"env-prod", "team-a"})
+	if fmt.Sprint(got) != "[env-prod team-a web]" {
+		t.Fatalf("unexpected merged tags: %v", got)
+	}
+}
+
+func TestUnitTags_ResourceTags(t *testing.T) {
+	tests := []struct {
+		name     string
+		api      []string
+		defaults []string
+		declared []string
+		expect   string
+	}{
+		{name: "inherited tags dropped", api: []string{"team-a", "web"}, defaults: []string{"team-a"}, declared: []string{"web"}, expect: "[web]"},
+		{name: "declared default kept", api: []string{"team-a", "web"}, defaults: []string{"team-a"}, declared: []string{"team-a", "web"}, expect: "[team-a web]"},
+		{name: "out of band tag kept", api: []string{"manual", "team-a"}, defaults: []string{"team-a"}, declared: nil, expect: "[manual]"},
+		{name: "no tags", api: nil, defaults: []string{"team-a"}, declared: nil, expect: "[]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fmt.Sprint(ResourceTags(tt.api, tt.defaults, tt.declared)); got != tt.expect {
+				t.Fatalf("expected %s, got %s", tt.expect, got)
+			}
+		})
+	}
+}
+
+func TestUnitTags_MergeSet(t *testing.T) {
+	ctx := t.Context()
+
+	planned, diags := types.SetValueFrom(ctx, types.StringType, []string{"web"})
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+
+	merged, diags := MergeSet(ctx, planned, []string{"team-a"})
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	expected, _ := types.SetValueFrom(ctx, types.StringType, []string{"team-a", "web"})
+	if !merged.Equal(expected) {
+		t.Fatalf("expected %s, got %s", expected, merged)
+	}
+
+	unknown, _ := MergeSet(ctx, types.SetUnknown(types.StringType), []string{"team-a"})
+	if !unknown.IsUnknown() {
+		t.Fatalf("expected unknown tags_all for unknown tags, got %s", unknown)
+	}
+}
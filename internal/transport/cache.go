@@ -0,0 +1,233 @@
+package transport
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// DefaultCacheTTL is how long a read is served from the cache when the
+// provider does not set read_cache_ttl.
+const DefaultCacheTTL = 30 * time.Second
+
+type pollingKey struct{}
+
+// Polling marks requests made with ctx as polls for a state change. They
+// always go to the API instead of the read cache, and Limit serves them
+// after other requests.
+func Polling(ctx context.Context) context.Context {
+	return context.WithValue(ctx, pollingKey{}, true)
+}
+
+func isPolling(ctx context.Context) bool {
+	return ctx.Value(pollingKey{}) != nil
+}
+
+// relatedServices lists the services whose reads also change when another
+// service is written to. A VM's IPs, VPC membership, volumes and firewall
+// groups are all managed through their own endpoints but show up in the VM
+// list, and the other way round.
+var relatedServices = map[string][]string{
+	"vm":       {"ip", "vpc", "storage", "firewall", "script", "sshkey", "snapshot", "backup"},
+	"ip":       {"vm"},
+	"vpc":      {"vm"},
+	"storage":  {"vm"},
+	"firewall": {"vm"},
+	"gateway":  {"ip"},
+}
+
+// serviceAliases maps path segments onto the service that owns them.
+var serviceAliases = map[string]string{
+	"fip":            "ip",
+	"virtualMachine": "vm",
+	"refresh":        "vm",
+}
+
+// Cache serves GET requests from a short-lived, per-provider cache. Most
+// resources read themselves by listing every object of their kind, so a
+// refresh would otherwise fetch the same list once per resource. Concurrent
+// reads of the same URL share one request, and any other request drops the
+// cached reads of the service it targets.
+type Cache struct {
+	Base http.RoundTripper
+	TTL  time.Duration
+
+	mu          sync.Mutex
+	entries     map[string]*cacheEntry
+	generations map[string]uint64
+
+	now func() time.Time
+}
+
+type cacheEntry struct {
+	service string
+	done    chan struct{}
+	expires time.Time
+
+	status int
+	header http.Header
+	body   []byte
+	err    error
+}
+
+// NewCache returns a Cache wrapping base, or http.DefaultTransport when base
+// is nil. A ttl of zero or less disables caching.
+func NewCache(base http.RoundTripper, ttl time.Duration) *Cache {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	return &Cache{
+		Base:        base,
+		TTL:         ttl,
+		entries:     map[string]*cacheEntry{},
+		generations: map[string]uint64{},
+		now:         time.Now,
+	}
+}
+
+func (c *Cache) RoundTrip(req *http.Request) (*http.Response, error) {
+	service := cacheService(req.URL.Path)
+
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		c.invalidate(req.Context(), service)
+		return c.Base.RoundTrip(req)
+	}
+
+	if req.Method != http.MethodGet || c.TTL <= 0 || isPolling(req.Context()) {
+		return c.Base.RoundTrip(req)
+	}
+
+	key := req.URL.String()
+
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	if ok && !entry.expired(c.now()) {
+		c.mu.Unlock()
+
+		select {
+		case <-entry.done:
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+
+		if entry.err != nil {
+			return nil, entry.err
+		}
+		tflog.Trace(req.Context(), "Serving VPSie API read from cache", map[string]any{"url": req.URL.Redacted()})
+		return entry.response(req), nil
+	}
+
+	entry = &cacheEntry{service: service, done: make(chan struct{})}
+	c.entries[key] = entry
+	generation := c.generations[service]
+	c.mu.Unlock()
+
+	resp, err := c.Base.RoundTrip(req)
+	entry.fill(resp, err)
+	close(entry.done)
+
+	c.mu.Lock()
+	if entry.err != nil || entry.status < 200 || entry.status > 299 || c.generations[service] != generation {
+		// Failed reads are not kept, and neither are reads that raced
+		// with a write to the same service.
+		if c.entries[key] == entry {
+			delete(c.entries, key)
+		}
+	} else {
+		entry.expires = c.now().Add(c.TTL)
+	}
+	c.mu.Unlock()
+
+	if entry.err != nil {
+		return nil, entry.err
+	}
+	return entry.response(req), nil
+}
+
+// invalidate drops the cached reads of service and of the services that
+// depend on it.
+func (c *Cache) invalidate(ctx context.Context, service string) {
+	services := append([]string{service}, relatedServices[service]...)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, s := range services {
+		c.generations[s]++
+	}
+	for key, entry := range c.entries {
+		for _, s := range services {
+			if entry.service == s {
+				delete(c.entries, key)
+				break
+			}
+		}
+	}
+
+	tflog.Trace(ctx, "Invalidated VPSie API read cache", map[string]any{"services": services})
+}
+
+func (e *cacheEntry) expired(now time.Time) bool {
+	// An entry without an expiry is still in flight.
+	return !e.expires.IsZero() && now.After(e.expires)
+}
+
+func (e *cacheEntry) fill(resp *http.Response, err error) {
+	if err != nil {
+		e.err = err
+		return
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		e.err = err
+		return
+	}
+
+	e.status = resp.StatusCode
+	e.header = resp.Header.Clone()
+	e.body = body
+}
+
+func (e *cacheEntry) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", e.status, http.StatusText(e.status)),
+		StatusCode:    e.status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        e.header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(e.body)),
+		ContentLength: int64(len(e.body)),
+		Request:       req,
+	}
+}
+
+// cacheService returns the API service a path belongs to: the first segment
+// after the API version, singular, so that /apps/v2/storages and
+// /apps/v2/storage/create are both "storage".
+func cacheService(p string) string {
+	segments := strings.Split(strings.Trim(p, "/"), "/")
+	if len(segments) >= 2 && (segments[0] == "apps" || segments[0] == "api") {
+		segments = segments[2:]
+	}
+	if len(segments) == 0 {
+		return ""
+	}
+
+	service := strings.TrimSuffix(segments[0], "s")
+	if alias, ok := serviceAliases[service]; ok {
+		return alias
+	}
+
+	return service
+}
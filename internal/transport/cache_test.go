@@ -0,0 +1,135 @@
+package transport
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestUnitCache_SharesReads(t *testing.T) {
+	var calls atomic.Int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		<-release
+		_, _ = io.WriteString(w, `{"data":[]}`)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: NewCache(nil, time.Minute)}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := client.Get(server.URL + "/apps/v2/storages")
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+			defer resp.Body.Close()
+			if body, _ := io.ReadAll(resp.Body); string(body) != `{"data":[]}` {
+				t.Errorf("unexpected body %q", body)
+			}
+		}()
+	}
+
+	// Let the concurrent reads pile up on the first one.
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	resp, err := client.Get(server.URL + "/apps/v2/storages")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	if calls.Load() != 1 {
+		t.Fatalf("expected reads to share one request, got %d", calls.Load())
+	}
+}
+
+func TestUnitCache_Invalidation(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			calls.Add(1)
+		}
+	}))
+	defer server.Close()
+
+	cache := NewCache(nil, time.Minute)
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+	client := &http.Client{Transport: cache}
+
+	get := func(ctx context.Context, path string) {
+		t.Helper()
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+path, nil)
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		resp.Body.Close()
+	}
+
+	get(context.Background(), "/apps/v2/storages")
+	get(context.Background(), "/apps/v2/domains")
+	get(context.Background(), "/apps/v2/storages")
+	if calls.Load() != 2 {
+		t.Fatalf("expected cached read, got %d calls", calls.Load())
+	}
+
+	// A write to the storage service drops only its own reads.
+	resp, err := client.Post(server.URL+"/apps/v2/storage/create", "application/json", strings.NewReader(`{}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	get(context.Background(), "/apps/v2/storages")
+	get(context.Background(), "/apps/v2/domains")
+	if calls.Load() != 3 {
+		t.Fatalf("expected only the storage list to be read again, got %d calls", calls.Load())
+	}
+
+	now = now.Add(2 * time.Minute)
+	get(context.Background(), "/apps/v2/domains")
+	if calls.Load() != 4 {
+		t.Fatalf("expected expired read to go to the API, got %d calls", calls.Load())
+	}
+
+	get(Polling(context.Background()), "/apps/v2/domains")
+	if calls.Load() != 5 {
+		t.Fatalf("expected polling read to bypass the cache, got %d calls", calls.Load())
+	}
+}
+
+func TestUnitCache_Service(t *testing.T) {
+	tests := map[string]string{
+		"/apps/v2/storages":           "storage",
+		"/apps/v2/storage/create":     "storage",
+		"/apps/v2/storages/vm/attach": "storage",
+		"/apps/v2/fip/add":            "ip",
+		"/apps/v2/ips":                "ip",
+		"/apps/v2/vm/tags/edit":       "vm",
+		"/apps/v2/vms/all/user":       "vm",
+		"/api/v1/lb/all":              "lb",
+		"/apps/v2/domains":            "domain",
+		"/apps/v2/domain/add/record":  "domain",
+	}
+
+	for path, want := range tests {
+		if got := cacheService(path); got != want {
+			t.Errorf("cacheService(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package transport
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// Limit caps the number of API requests in flight across all resources of
+// a provider. A request holds its slot until its response body is closed.
+// When requests are queued, polls marked with Polling only get a slot once
+// no other request is waiting, so that waiters do not hold up the calls
+// that change infrastructure.
+type Limit struct {
+	Base http.RoundTripper
+	Max  int
+
+	mu     sync.Mutex
+	active int
+	queues [2][]chan struct{}
+}
+
+const (
+	priorityNormal = iota
+	priorityLow
+)
+
+// NewLimit returns a Limit wrapping base, or http.DefaultTransport when base
+// is nil, that allows at most max requests in flight.
+func NewLimit(base http.RoundTripper, max int) *Limit {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	return &Limit{Base: base, Max: max}
+}
+
+func (l *Limit) RoundTrip(req *http.Request) (*http.Response, error) {
+	priority := priorityNormal
+	if isPolling(req.Context()) {
+		priority = priorityLow
+	}
+
+	if err := l.acquire(req.Context(), priority); err != nil {
+		return nil, err
+	}
+
+	resp, err := l.Base.RoundTrip(req)
+	if err != nil {
+		l.release()
+		return nil, err
+	}
+
+	resp.Body = &releaseOnClose{ReadCloser: resp.Body, release: l.release}
+
+	return resp, nil
+}
+
+func (l *Limit) acquire(ctx context.Context, priority int) error {
+	l.mu.Lock()
+	if l.active < l.Max && l.queued(priority) == 0 {
+		l.active++
+		l.mu.Unlock()
+		return nil
+	}
+
+	ready := make(chan struct{})
+	l.queues[priority] = append(l.queues[priority], ready)
+	l.mu.Unlock()
+
+	select {
+	case <-ready:
+		return nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		select {
+		case <-ready:
+			// The slot was handed over while the context was cancelled.
+			l.releaseLocked()
+		default:
+			l.remove(priority, ready)
+		}
+
+		return ctx.Err()
+	}
+}
+
+// queued returns the number of requests waiting at priority or above.
+func (l *Limit) queued(priority int) int {
+	n := 0
+	for p := priorityNormal; p <= priority; p++ {
+		n += len(l.queues[p])
+	}
+	return n
+}
+
+func (l *Limit) release() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.releaseLocked()
+}
+
+// releaseLocked hands the slot to the next waiting request, if any.
+func (l *Limit) releaseLocked() {
+	for p := range l.queues {
+		if len(l.queues[p]) > 0 {
+			next := l.queues[p][0]
+			l.queues[p] = l.queues[p][1:]
+			close(next)
+			return
+		}
+	}
+
+	l.active--
+}
+
+func (l *Limit) remove(priority int, ready chan struct{}) {
+	queue := l.queues[priority]
+	for i, waiting := range queue {
+		if waiting == ready {
+			l.queues[priority] = append(queue[:i], queue[i+1:]...)
+			return
+		}
+	}
+}
+
+type releaseOnClose struct {
+	io.ReadCloser
+	release func()
+	once    sync.Once
+}
+
+func (b *releaseOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.release)
+	return err
+}
@@ -0,0 +1,135 @@
+package transport
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestUnitLimit_CapsConcurrency(t *testing.T) {
+	var mu sync.Mutex
+	active, peak := 0, 0
+
+	limit := NewLimit(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		mu.Lock()
+		active++
+		peak = max(peak, active)
+		mu.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+
+		mu.Lock()
+		active--
+		mu.Unlock()
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(""))}, nil
+	}), 2)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req, _ := http.NewRequest(http.MethodGet, "http://vpsie.test/apps/v2/vm", nil)
+			resp, err := limit.RoundTrip(req)
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+			resp.Body.Close()
+		}()
+	}
+	wg.Wait()
+
+	if peak > 2 {
+		t.Fatalf("expected at most 2 requests in flight, got %d", peak)
+	}
+	if limit.active != 0 {
+		t.Fatalf("expected all slots to be released, got %d active", limit.active)
+	}
+}
+
+func TestUnitLimit_PollingWaitsBehindOtherRequests(t *testing.T) {
+	var mu sync.Mutex
+	var order []string
+
+	limit := NewLimit(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		mu.Lock()
+		order = append(order, req.Method)
+		mu.Unlock()
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(""))}, nil
+	}), 1)
+
+	// Hold the only slot until both waiters are queued.
+	if err := limit.acquire(context.Background(), priorityNormal); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	send := func(ctx context.Context, method string) {
+		defer wg.Done()
+		req, _ := http.NewRequestWithContext(ctx, method, "http://vpsie.test/apps/v2/vm", nil)
+		resp, err := limit.RoundTrip(req)
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+			return
+		}
+		resp.Body.Close()
+	}
+
+	wg.Add(1)
+	go send(Polling(context.Background()), http.MethodGet)
+	waitQueued(t, limit, priorityLow, 1)
+
+	wg.Add(1)
+	go send(context.Background(), http.MethodPost)
+	waitQueued(t, limit, priorityNormal, 1)
+
+	limit.release()
+	wg.Wait()
+
+	if len(order) != 2 || order[0] != http.MethodPost {
+		t.Fatalf("expected the POST to run before the poll, got %v", order)
+	}
+}
+
+func TestUnitLimit_CancelledWhileQueued(t *testing.T) {
+	limit := NewLimit(nil, 1)
+	if err := limit.acquire(context.Background(), priorityNormal); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := limit.acquire(ctx, priorityNormal); err == nil {
+		t.Fatal("expected the queued request to fail with its context")
+	}
+
+	limit.release()
+	if limit.active != 0 || limit.queued(priorityLow) != 0 {
+		t.Fatalf("expected an empty limiter, got %d active and %d queued", limit.active, limit.queued(priorityLow))
+	}
+}
+
+func waitQueued(t *testing.T, limit *Limit, priority, n int) {
+	t.Helper()
+	for i := 0; i < 100; i++ {
+		limit.mu.Lock()
+		queued := len(limit.queues[priority])
+		limit.mu.Unlock()
+		if queued == n {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("expected %d queued requests at priority %d", n, priority)
+}
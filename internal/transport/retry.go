@@ -0,0 +1,213 @@
+// Package transport provides the http.RoundTripper layers the provider
+// installs in front of the VPSie API client.
+package transport
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	// DefaultMaxRetries is the number of times a request is retried when the
+	// provider does not set max_retries.
+	DefaultMaxRetries = 4
+
+	// DefaultRetryMaxWait caps the wait between two attempts when the
+	// provider does not set retry_max_wait.
+	DefaultRetryMaxWait = 30 * time.Second
+
+	retryMinWait = 500 * time.Millisecond
+)
+
+type allowRetryKey struct{}
+
+// AllowRetry marks requests made with ctx as safe to retry even when their
+// HTTP method is not idempotent, for API actions that can be repeated
+// without side effects.
+func AllowRetry(ctx context.Context) context.Context {
+	return context.WithValue(ctx, allowRetryKey{}, true)
+}
+
+// Retry retries requests that fail with a rate limit, a server error or a
+// network error, waiting with exponential backoff and jitter between
+// attempts and honoring Retry-After. Only idempotent requests are retried
+// unless the request context was marked with AllowRetry.
+type Retry struct {
+	Base       http.RoundTripper
+	MaxRetries int
+	MaxWait    time.Duration
+
+	// sleep is replaced in tests.
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// NewRetry returns a Retry wrapping base, or http.DefaultTransport when base
+// is nil.
+func NewRetry(base http.RoundTripper, maxRetries int, maxWait time.Duration) *Retry {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	return &Retry{
+		Base:       base,
+		MaxRetries: maxRetries,
+		MaxWait:    maxWait,
+		sleep:      sleepContext,
+	}
+}
+
+func (t *Retry) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	retryable := isIdempotent(req.Method) || ctx.Value(allowRetryKey{}) != nil
+
+	for attempt := 0; ; attempt++ {
+		resp, err := t.Base.RoundTrip(req)
+
+		if !retryable || attempt >= t.MaxRetries || !shouldRetry(resp, err) {
+			return resp, err
+		}
+
+		next, ok := rewind(req)
+		if !ok {
+			return resp, err
+		}
+
+		wait := t.backoff(attempt, resp)
+
+		fields := map[string]any{
+			"method":  req.Method,
+			"url":     req.URL.Redacted(),
+			"attempt": attempt + 1,
+			"max":     t.MaxRetries,
+			"wait":    wait.String(),
+		}
+		if err != nil {
+			fields["error"] = err.Error()
+		} else {
+			fields["status"] = resp.StatusCode
+			drain(resp)
+		}
+		tflog.Warn(ctx, "Retrying VPSie API request", fields)
+
+		if err := t.sleep(ctx, wait); err != nil {
+			return nil, err
+		}
+
+		req = next
+	}
+}
+
+// backoff returns how long to wait before the next attempt: the server's
+// Retry-After when present, otherwise exponential backoff with full jitter.
+// Both are capped at MaxWait.
+func (t *Retry) backoff(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if wait, ok := retryAfter(resp.Header.Get("Retry-After")); ok {
+			return min(wait, t.MaxWait)
+		}
+	}
+
+	ceiling := retryMinWait << attempt
+	if ceiling <= 0 || ceiling > t.MaxWait {
+		ceiling = t.MaxWait
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+
+	return min(retryMinWait/2+rand.N(ceiling), t.MaxWait)
+}
+
+func shouldRetry(resp *http.Response, err error) bool {
+	if err != nil {
+		// A cancelled or expired context is not worth retrying.
+		return resp == nil && !isContextError(err)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	}
+
+	return false
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+func isContextError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// rewind returns a copy of req whose body can be sent again.
+func rewind(req *http.Request) (*http.Request, bool) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return req, true
+	}
+	if req.GetBody == nil {
+		return nil, false
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, false
+	}
+
+	next := req.Clone(req.Context())
+	next.Body = body
+
+	return next, true
+}
+
+// retryAfter parses a Retry-After header given either in seconds or as an
+// HTTP date.
+func retryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if at, err := http.ParseTime(value); err == nil {
+		return max(time.Until(at), 0), true
+	}
+
+	return 0, false
+}
+
+// drain discards the body of a response that is about to be retried so the
+// connection can be reused.
+func drain(resp *http.Response) {
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+	resp.Body.Close()
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
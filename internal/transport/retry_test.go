@@ -0,0 +1,134 @@
+package transport
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newTestRetry(maxRetries int, waits *[]time.Duration) *Retry {
+	r := NewRetry(nil, maxRetries, 10*time.Second)
+	r.sleep = func(ctx context.Context, d time.Duration) error {
+		*waits = append(*waits, d)
+		return nil
+	}
+	return r
+}
+
+func TestUnitRetry_RetriesIdempotentRequests(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = io.WriteString(w, "ok")
+	}))
+	defer server.Close()
+
+	var waits []time.Duration
+	client := &http.Client{Transport: newTestRetry(4, &waits)}
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	if calls.Load() != 3 || len(waits) != 2 {
+		t.Fatalf("expected 3 calls and 2 waits, got %d calls and %v", calls.Load(), waits)
+	}
+}
+
+func TestUnitRetry_StopsAfterMaxRetries(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	var waits []time.Duration
+	client := &http.Client{Transport: newTestRetry(2, &waits)}
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("expected 429, got %d", resp.StatusCode)
+	}
+	if calls.Load() != 3 {
+		t.Fatalf("expected 3 calls, got %d", calls.Load())
+	}
+}
+
+func TestUnitRetry_NonIdempotentRequests(t *testing.T) {
+	var calls atomic.Int32
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer server.Close()
+
+	var waits []time.Duration
+	client := &http.Client{Transport: newTestRetry(4, &waits)}
+
+	resp, err := client.Post(server.URL, "application/json", strings.NewReader(`{"a":1}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadGateway || calls.Load() != 1 {
+		t.Fatalf("expected POST not to be retried, got status %d after %d calls", resp.StatusCode, calls.Load())
+	}
+
+	calls.Store(0)
+	bodies = nil
+	req, _ := http.NewRequestWithContext(AllowRetry(context.Background()), http.MethodPost, server.URL, strings.NewReader(`{"a":1}`))
+	resp, err = client.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || calls.Load() != 2 {
+		t.Fatalf("expected POST marked with AllowRetry to be retried, got status %d after %d calls", resp.StatusCode, calls.Load())
+	}
+	if bodies[1] != `{"a":1}` {
+		t.Fatalf("expected request body to be replayed, got %q", bodies[1])
+	}
+}
+
+func TestUnitRetry_Backoff(t *testing.T) {
+	r := NewRetry(nil, 4, 5*time.Second)
+
+	resp := &http.Response{Header: http.Header{"Retry-After": []string{"3"}}}
+	if wait := r.backoff(0, resp); wait != 3*time.Second {
+		t.Fatalf("expected Retry-After of 3s to be honored, got %s", wait)
+	}
+
+	resp.Header.Set("Retry-After", "120")
+	if wait := r.backoff(0, resp); wait != 5*time.Second {
+		t.Fatalf("expected Retry-After to be capped at 5s, got %s", wait)
+	}
+
+	for attempt := 0; attempt < 10; attempt++ {
+		if wait := r.backoff(attempt, nil); wait <= 0 || wait > 5*time.Second {
+			t.Fatalf("attempt %d: backoff %s outside (0, 5s]", attempt, wait)
+		}
+	}
+}
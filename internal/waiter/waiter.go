@@ -0,0 +1,222 @@
+// Package waiter polls the VPSie API until an object reaches a target state.
+package waiter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/vpsie/terraform-provider-vpsie/internal/apierror"
+	"github.com/vpsie/terraform-provider-vpsie/internal/transport"
+)
+
+const (
+	// DefaultMinInterval is the first wait between two polls.
+	DefaultMinInterval = 5 * time.Second
+
+	// DefaultMaxInterval caps the wait between two polls.
+	DefaultMaxInterval = 30 * time.Second
+
+	// DefaultBackoff is the factor the wait grows by after every poll.
+	DefaultBackoff = 1.5
+)
+
+// RefreshFunc returns the object being waited on and its current state. A
+// missing object is reported with a nil result and an empty state; a nil
+// result with a non-empty state, such as a resource that is not listed yet
+// while it is being created, is treated like any other state.
+type RefreshFunc[T any] func(ctx context.Context) (result *T, state string, err error)
+
+// Conf describes how to wait for an object.
+type Conf[T any] struct {
+	// Pending lists the states the object may be in while waiting. When
+	// empty, any state that is not a target keeps the waiter polling.
+	Pending []string
+
+	// Target lists the states that end the wait.
+	Target []string
+
+	Refresh RefreshFunc[T]
+
+	// Timeout bounds the whole wait. Zero only relies on the deadline of
+	// the context passed to Wait.
+	Timeout time.Duration
+
+	// MinInterval is the first wait between two polls. It grows by Backoff
+	// after every poll, up to MaxInterval.
+	MinInterval time.Duration
+	MaxInterval time.Duration
+	Backoff     float64
+
+	// NotFoundChecks is the number of consecutive polls the object may be
+	// missing before the wait fails, for objects the API briefly stops
+	// listing while it works on them.
+	NotFoundChecks int
+}
+
+// TimeoutError is returned when the object did not reach a target state in
+// time.
+type TimeoutError struct {
+	LastState string
+	Target    []string
+	Timeout   time.Duration
+}
+
+func (e *TimeoutError) Error() string {
+	lastState := e.LastState
+	if lastState == "" {
+		lastState = "not found"
+	}
+
+	if e.Timeout > 0 {
+		return fmt.Sprintf("timeout after %s while waiting for state to become %s (last state: %q)",
+			e.Timeout, quoteStates(e.Target), lastState)
+	}
+	return fmt.Sprintf("timeout while waiting for state to become %s (last state: %q)", quoteStates(e.Target), lastState)
+}
+
+// NotFoundError is returned when the object was missing for more than
+// NotFoundChecks consecutive polls.
+type NotFoundError struct {
+	Checks    int
+	LastState string
+}
+
+func (e *NotFoundError) Error() string {
+	if e.LastState != "" {
+		return fmt.Sprintf("object not found after %d checks (last state: %q)", e.Checks, e.LastState)
+	}
+	return fmt.Sprintf("object not found after %d checks", e.Checks)
+}
+
+// UnexpectedStateError is returned when the object enters a state that is
+// neither pending nor a target.
+type UnexpectedStateError struct {
+	State    string
+	Expected []string
+}
+
+func (e *UnexpectedStateError) Error() string {
+	return fmt.Sprintf("unexpected state %q, wanted %s", e.State, quoteStates(e.Expected))
+}
+
+// Wait polls Refresh until the object reaches a target state and returns
+// the last result. A refresh that is throttled or hits a busy object is
+// retried on the next poll; any other error ends the wait.
+func (c *Conf[T]) Wait(ctx context.Context) (*T, error) {
+	if c.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.Timeout)
+		defer cancel()
+	}
+
+	interval := c.MinInterval
+	if interval <= 0 {
+		interval = DefaultMinInterval
+	}
+	maxInterval := c.MaxInterval
+	if maxInterval <= 0 {
+		maxInterval = max(DefaultMaxInterval, interval)
+	}
+	backoff := c.Backoff
+	if backoff < 1 {
+		backoff = DefaultBackoff
+	}
+
+	lastState := ""
+	notFound := 0
+
+	for {
+		result, state, err := c.Refresh(transport.Polling(ctx))
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, c.timeoutError(lastState)
+			}
+			if !isTransient(err) {
+				return nil, err
+			}
+			tflog.Debug(ctx, "Polling again after a transient error", map[string]any{
+				"error": err.Error(),
+			})
+		} else if result == nil && state == "" {
+			notFound++
+			if notFound > c.NotFoundChecks {
+				return nil, &NotFoundError{Checks: notFound, LastState: lastState}
+			}
+		} else {
+			notFound = 0
+			lastState = state
+
+			if slices.Contains(c.Target, state) {
+				return result, nil
+			}
+			if len(c.Pending) > 0 && !slices.Contains(c.Pending, state) {
+				return result, &UnexpectedStateError{State: state, Expected: append(slices.Clone(c.Pending), c.Target...)}
+			}
+		}
+
+		tflog.Debug(ctx, "Waiting for state change", map[string]any{
+			"state":    lastState,
+			"target":   c.Target,
+			"interval": interval.String(),
+		})
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, c.timeoutError(lastState)
+		case <-timer.C:
+		}
+
+		interval = min(time.Duration(float64(interval)*backoff), maxInterval)
+	}
+}
+
+// isTransient reports whether a refresh that failed with err should be
+// retried on the next poll: the API throttles frequent polls and may answer
+// with a conflict while the object is busy, and both pass with time.
+func isTransient(err error) bool {
+	return apierror.IsRateLimited(err) || apierror.IsConflict(err)
+}
+
+func (c *Conf[T]) timeoutError(lastState string) error {
+	return &TimeoutError{LastState: lastState, Target: c.Target, Timeout: c.Timeout}
+}
+
+// IsTimeout reports whether err is a TimeoutError.
+func IsTimeout(err error) bool {
+	var timeoutErr *TimeoutError
+	return errors.As(err, &timeoutErr)
+}
+
+func quoteStates(states []string) string {
+	quoted := make([]string, len(states))
+	for i, state := range states {
+		quoted[i] = fmt.Sprintf("%q", state)
+	}
+	return strings.Join(quoted, " or ")
+}
+
+// States reported by Appeared.
+const (
+	StateCreating = "creating"
+	StateCreated  = "created"
+)
+
+// Appeared adapts a check that reports whether a newly created object is
+// listed by the API yet into a RefreshFunc, for create endpoints that do not
+// return the new object.
+func Appeared[T any](check func(ctx context.Context) (*T, bool, error)) RefreshFunc[T] {
+	return func(ctx context.Context) (*T, string, error) {
+		result, ok, err := check(ctx)
+		if err != nil || !ok {
+			return nil, StateCreating, err
+		}
+		return result, StateCreated, nil
+	}
+}
@@ -0,0 +1,143 @@
+package waiter
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+type object struct {
+	state string
+}
+
+// sequence returns a RefreshFunc that walks through states, repeating the
+// last one. An empty state stands for a missing object.
+func sequence(states ...string) (RefreshFunc[object], *int) {
+	calls := 0
+	return func(ctx context.Context) (*object, string, error) {
+		state := states[min(calls, len(states)-1)]
+		calls++
+		if state == "" {
+			return nil, "", nil
+		}
+		return &object{state: state}, state, nil
+	}, &calls
+}
+
+func TestUnitWaiter_ReachesTarget(t *testing.T) {
+	refresh, calls := sequence("creating", "creating", "running")
+	conf := &Conf[object]{
+		Pending:     []string{"creating"},
+		Target:      []string{"running"},
+		Refresh:     refresh,
+		MinInterval: time.Millisecond,
+	}
+
+	result, err := conf.Wait(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.state != "running" || *calls != 3 {
+		t.Fatalf("expected running after 3 calls, got %q after %d", result.state, *calls)
+	}
+}
+
+func TestUnitWaiter_NotFoundChecks(t *testing.T) {
+	refresh, _ := sequence("", "", "running")
+	conf := &Conf[object]{
+		Target:         []string{"running"},
+		Refresh:        refresh,
+		MinInterval:    time.Millisecond,
+		NotFoundChecks: 2,
+	}
+	if _, err := conf.Wait(context.Background()); err != nil {
+		t.Fatalf("expected two misses to be tolerated, got %v", err)
+	}
+
+	refresh, _ = sequence("creating", "", "", "running")
+	conf.Refresh = refresh
+	conf.NotFoundChecks = 1
+
+	var notFound *NotFoundError
+	if _, err := conf.Wait(context.Background()); !errors.As(err, &notFound) {
+		t.Fatalf("expected NotFoundError, got %v", err)
+	}
+	if notFound.Checks != 2 || notFound.LastState != "creating" {
+		t.Fatalf("unexpected NotFoundError: %+v", notFound)
+	}
+}
+
+func TestUnitWaiter_UnexpectedState(t *testing.T) {
+	refresh, _ := sequence("creating", "failed")
+	conf := &Conf[object]{
+		Pending:     []string{"creating"},
+		Target:      []string{"running"},
+		Refresh:     refresh,
+		MinInterval: time.Millisecond,
+	}
+
+	var unexpected *UnexpectedStateError
+	if _, err := conf.Wait(context.Background()); !errors.As(err, &unexpected) || unexpected.State != "failed" {
+		t.Fatalf("expected UnexpectedStateError for failed, got %v", err)
+	}
+}
+
+func TestUnitWaiter_Timeout(t *testing.T) {
+	refresh, _ := sequence("creating")
+	conf := &Conf[object]{
+		Target:      []string{"running"},
+		Refresh:     refresh,
+		Timeout:     20 * time.Millisecond,
+		MinInterval: time.Millisecond,
+		MaxInterval: 5 * time.Millisecond,
+	}
+
+	_, err := conf.Wait(context.Background())
+	if !IsTimeout(err) {
+		t.Fatalf("expected TimeoutError, got %v", err)
+	}
+	if !strings.Contains(err.Error(), `last state: "creating"`) {
+		t.Fatalf("expected the last state in the error, got %q", err)
+	}
+}
+
+func TestUnitWaiter_RefreshError(t *testing.T) {
+	conf := &Conf[object]{
+		Target: []string{"running"},
+		Refresh: func(ctx context.Context) (*object, string, error) {
+			return nil, "", errors.New("boom")
+		},
+	}
+
+	if _, err := conf.Wait(context.Background()); err == nil || err.Error() != "boom" {
+		t.Fatalf("expected refresh error, got %v", err)
+	}
+}
+
+func TestUnitWaiter_TransientRefreshError(t *testing.T) {
+	errs := []error{
+		errors.New("Slow down (HTTP 429)"),
+		errors.New("Server is busy (HTTP 409)"),
+	}
+	calls := 0
+	conf := &Conf[object]{
+		Target: []string{"running"},
+		Refresh: func(ctx context.Context) (*object, string, error) {
+			calls++
+			if calls <= len(errs) {
+				return nil, "", errs[calls-1]
+			}
+			return &object{state: "running"}, "running", nil
+		},
+		MinInterval: time.Millisecond,
+	}
+
+	if _, err := conf.Wait(context.Background()); err != nil {
+		t.Fatalf("expected rate limits and conflicts to be polled through, got %v", err)
+	}
+	if calls != 3 {
+		t.Fatalf("expected 3 calls, got %d", calls)
+	}
+}